@@ -27,6 +27,8 @@ type ABUGames struct {
 
 	inventory mtgban.InventoryRecord
 	buylist   mtgban.BuylistRecord
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a singles scraper. ABU needs no credentials for prices.
@@ -79,7 +81,7 @@ func (abu *ABUGames) processEntry(ctx context.Context, query string, channel cha
 			continue
 		}
 
-		cardID, err := mtgmatcher.Match(theCard)
+		cardID, err := abu.matchStats.Match(theCard)
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			continue
 		} else if err != nil {
@@ -394,6 +396,11 @@ func (abu *ABUGames) InfoForScraper(name string) mtgban.ScraperInfo {
 	return info
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (abu *ABUGames) MatchStats() *mtgban.MatchStats {
+	return &abu.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (abu *ABUGames) Info() (info mtgban.ScraperInfo) {
 	info.Name = "ABU Games"
@@ -10,7 +10,7 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/hashicorp/go-cleanhttp"
+	"github.com/mtgban/go-mtgban/mtgban"
 )
 
 const buylistURL = "https://buylist.arcanafrisia.com/buylist.csv"
@@ -39,7 +39,7 @@ func GetBuylist(ctx context.Context) ([]Card, error) {
 		return nil, err
 	}
 
-	resp, err := cleanhttp.DefaultClient().Do(req)
+	resp, err := mtgban.NewHTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
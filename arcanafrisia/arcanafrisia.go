@@ -13,8 +13,12 @@ import (
 type Arcanafrisia struct {
 	LogCallback mtgban.LogCallbackFunc
 
-	buylistDate time.Time
-	buylist     mtgban.BuylistRecord
+	buylistDate  time.Time
+	buylist      mtgban.BuylistRecord
+	exchangeRate float64
+
+	// Source of the exchange rates, the default one when nil
+	RateProvider mtgban.ExchangeRateProvider
 }
 
 // NewScraper returns a buylist scraper.
@@ -32,10 +36,11 @@ func (af *Arcanafrisia) printf(format string, a ...any) {
 
 // Load fetches everything this scraper offers. See mtgban.Scraper.
 func (af *Arcanafrisia) Load(ctx context.Context) error {
-	rate, err := mtgban.GetExchangeRate(ctx, "EUR")
+	rate, err := mtgban.ExchangeRateFrom(ctx, af.RateProvider, "EUR")
 	if err != nil {
 		return err
 	}
+	af.exchangeRate = rate
 
 	cards, err := GetBuylist(ctx)
 	if err != nil {
@@ -64,10 +69,12 @@ func (af *Arcanafrisia) Load(ctx context.Context) error {
 		}
 
 		out := &mtgban.BuylistEntry{
-			Conditions: cond,
-			BuyPrice:   card.PriceEUR * rate,
-			Quantity:   card.BuyLimit,
-			URL:        card.URL,
+			Conditions:  cond,
+			BuyPrice:    card.PriceEUR * rate,
+			Quantity:    card.BuyLimit,
+			URL:         card.URL,
+			Currency:    "EUR",
+			NativePrice: card.PriceEUR,
 		}
 		err = af.buylist.AddRelaxed(cardID, out)
 		if err != nil {
@@ -91,5 +98,9 @@ func (af *Arcanafrisia) Info() (info mtgban.ScraperInfo) {
 	info.Shorthand = "AF"
 	info.CountryFlag = "EU"
 	info.BuylistTimestamp = &af.buylistDate
+	info.Currency = "EUR"
+	if af.exchangeRate != 0 {
+		info.ExchangeRates = map[string]float64{"eur": af.exchangeRate}
+	}
 	return
 }
@@ -29,6 +29,8 @@ type Cardkingdom struct {
 
 	inventory mtgban.InventoryRecord
 	buylist   mtgban.BuylistRecord
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraperLocal returns a singles scraper reading the feed from a file
@@ -81,7 +83,7 @@ func (ck *Cardkingdom) Load(ctx context.Context) error {
 			continue
 		}
 
-		cardID, err := mtgmatcher.Match(theCard)
+		cardID, err := ck.matchStats.Match(theCard)
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			continue
 		} else if err != nil {
@@ -299,6 +301,11 @@ func (ck *Cardkingdom) InfoForScraper(name string) mtgban.ScraperInfo {
 	return info
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (ck *Cardkingdom) MatchStats() *mtgban.MatchStats {
+	return &ck.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (ck *Cardkingdom) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Card Kingdom"
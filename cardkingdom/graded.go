@@ -31,6 +31,8 @@ type Graded struct {
 	inventory     mtgban.InventoryRecord
 
 	client *cloudscraper.CloudScrapper
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraperGraded returns a graded scraper.
@@ -126,7 +128,7 @@ func (ck *Graded) scrapePage(session string, page int) error {
 			return
 		}
 
-		cardID, err := mtgmatcher.Match(theCard)
+		cardID, err := ck.matchStats.Match(theCard)
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			return
 		} else if err != nil {
@@ -213,6 +215,11 @@ func (ck *Graded) Inventory() mtgban.InventoryRecord {
 	return ck.inventory
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (ck *Graded) MatchStats() *mtgban.MatchStats {
+	return &ck.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (ck *Graded) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Card Kingdom Graded"
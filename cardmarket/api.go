@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/mtgban/go-mtgban/mtgban"
 )
 
 const (
@@ -42,7 +43,7 @@ type MKMClient struct {
 // NewMKMClient returns a client signing with the given app credentials.
 func NewMKMClient(appToken, appSecret string) *MKMClient {
 	mkm := MKMClient{}
-	client := retryablehttp.NewClient()
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	// The api is very sensitive to multiple concurrent requests,
 	// This backoff strategy lets the system chill out a bit before retrying
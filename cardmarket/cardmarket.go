@@ -18,10 +18,11 @@ const (
 	defaultConcurrency = 8
 )
 
+// responseChan is one priced product, saved as is to the checkpoint.
 type responseChan struct {
-	ogID   int
-	cardID string
-	entry  mtgban.InventoryEntry
+	OgID   int                   `json:"og_id,omitempty"`
+	CardID string                `json:"card_id"`
+	Entry  mtgban.InventoryEntry `json:"entry"`
 }
 
 // Index prices singles from Cardmarket's price guide, the low and
@@ -36,6 +37,10 @@ type Index struct {
 	// Optional field to select a single edition to go through
 	TargetEdition string
 
+	// Optional directory where to keep the expansions already processed,
+	// see mtgban.CheckpointedWorkerPool
+	CheckpointDir string
+
 	// TCGBridge maps a Cardmarket product id to the TCGplayer id of the
 	// same single, for the keyless catalogs (yugioh, flesh and blood)
 	// whose products carry no collector number and no version index, so
@@ -54,6 +59,11 @@ type Index struct {
 
 	client *MKMClient
 	gameID int
+
+	// Source of the exchange rates, the default one when nil
+	RateProvider mtgban.ExchangeRateProvider
+
+	matchStats mtgban.MatchStats
 }
 
 var availableIndexNames = []string{
@@ -192,7 +202,7 @@ func (mkm *Index) processProduct(channel chan<- responseChan, product *MKMProduc
 			return nil
 		}
 
-		cardID, err = mtgmatcher.Match(theCard)
+		cardID, err = mkm.matchStats.Match(theCard)
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			return nil
 		} else if err != nil {
@@ -232,10 +242,15 @@ func (mkm *Index) processProduct(channel chan<- responseChan, product *MKMProduc
 			number = strings.TrimSpace(number + " V." + strings.TrimSuffix(fields[1], ")"))
 		}
 
+		// Only the outcome of both probes is recorded
+		input := mtgmatcher.InputCard{Name: cardName, Edition: product.ExpansionName, Variation: number}
+
 		cardID, err = mtgmatcher.Match(&mtgmatcher.InputCard{Name: cardName, Edition: product.ExpansionName, Variation: number, Foil: false})
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
+			mkm.matchStats.Record(&input, err)
 			return nil
 		} else if err != nil && !errors.Is(err, mtgmatcher.ErrCardWrongVariant) {
+			mkm.matchStats.Record(&input, err)
 			mkm.printf("%v", err)
 			mkm.printf("%+v", product)
 
@@ -264,10 +279,12 @@ func (mkm *Index) processProduct(channel chan<- responseChan, product *MKMProduc
 			if errFoil != nil {
 				err = errFoil
 			}
+			mkm.matchStats.Record(&input, err)
 			mkm.printf("%v", err)
 			mkm.printf("%+v", product)
 			return err
 		}
+		mkm.matchStats.Record(&input, nil)
 	case GameYuGiOh, GameFleshAndBlood, GamePokemon:
 		// These catalogs carry no collector number and no version index,
 		// and same-name products abound, so a product resolves through the
@@ -356,15 +373,17 @@ func (mkm *Index) processProduct(channel chan<- responseChan, product *MKMProduc
 			}
 
 			out := responseChan{
-				ogID:   product.IDProduct,
-				cardID: cardID,
-				entry: mtgban.InventoryEntry{
-					Conditions: "NM",
-					Price:      prices[i] * mkm.exchangeRate,
-					Quantity:   quantity,
-					URL:        link,
-					SellerName: availableIndexNames[i],
-					OriginalID: fmt.Sprint(product.IDProduct),
+				OgID:   product.IDProduct,
+				CardID: cardID,
+				Entry: mtgban.InventoryEntry{
+					Conditions:  "NM",
+					Price:       prices[i] * mkm.exchangeRate,
+					Quantity:    quantity,
+					URL:         link,
+					SellerName:  availableIndexNames[i],
+					OriginalID:  fmt.Sprint(product.IDProduct),
+					Currency:    "EUR",
+					NativePrice: prices[i],
 				},
 			}
 
@@ -383,15 +402,17 @@ func (mkm *Index) processProduct(channel chan<- responseChan, product *MKMProduc
 						continue
 					}
 					out := responseChan{
-						ogID:   product.IDProduct,
-						cardID: cardIDFoil,
-						entry: mtgban.InventoryEntry{
-							Conditions: "NM",
-							Price:      foilprices[i] * mkm.exchangeRate,
-							Quantity:   product.CountFoils,
-							URL:        link,
-							SellerName: availableIndexNames[i],
-							OriginalID: fmt.Sprint(product.IDProduct),
+						OgID:   product.IDProduct,
+						CardID: cardIDFoil,
+						Entry: mtgban.InventoryEntry{
+							Conditions:  "NM",
+							Price:       foilprices[i] * mkm.exchangeRate,
+							Quantity:    product.CountFoils,
+							URL:         link,
+							SellerName:  availableIndexNames[i],
+							OriginalID:  fmt.Sprint(product.IDProduct),
+							Currency:    "EUR",
+							NativePrice: foilprices[i],
 						},
 					}
 
@@ -407,15 +428,17 @@ func (mkm *Index) processProduct(channel chan<- responseChan, product *MKMProduc
 				continue
 			}
 			out := responseChan{
-				ogID:   product.IDProduct,
-				cardID: cardID,
-				entry: mtgban.InventoryEntry{
-					Conditions: "NM",
-					Price:      foilprices[i] * mkm.exchangeRate,
-					Quantity:   product.CountFoils,
-					URL:        link,
-					SellerName: availableIndexNames[i],
-					OriginalID: fmt.Sprint(product.IDProduct),
+				OgID:   product.IDProduct,
+				CardID: cardID,
+				Entry: mtgban.InventoryEntry{
+					Conditions:  "NM",
+					Price:       foilprices[i] * mkm.exchangeRate,
+					Quantity:    product.CountFoils,
+					URL:         link,
+					SellerName:  availableIndexNames[i],
+					OriginalID:  fmt.Sprint(product.IDProduct),
+					Currency:    "EUR",
+					NativePrice: foilprices[i],
 				},
 			}
 
@@ -426,9 +449,15 @@ func (mkm *Index) processProduct(channel chan<- responseChan, product *MKMProduc
 	return nil
 }
 
+// SetConfig applies options after the scraper was built. See
+// mtgban.ScraperConfig.
+func (mkm *Index) SetConfig(opt mtgban.ScraperOptions) {
+	mkm.CheckpointDir = opt.CheckpointDir
+}
+
 // Load fetches everything this scraper offers. See mtgban.Scraper.
 func (mkm *Index) Load(ctx context.Context) error {
-	rate, err := mtgban.GetExchangeRate(ctx, "EUR")
+	rate, err := mtgban.ExchangeRateFrom(ctx, mkm.RateProvider, "EUR")
 	if err != nil {
 		return err
 	}
@@ -490,7 +519,16 @@ func (mkm *Index) Load(ctx context.Context) error {
 		add = mkm.inventory.AddUnique
 	}
 
-	mtgban.WorkerPool(ctx, mkm.MaxConcurrency, items,
+	cp, err := mtgban.OpenCheckpoint(mkm.CheckpointDir, "expansions")
+	if err != nil {
+		return err
+	}
+	defer cp.Close()
+	if cp.Len() > 0 {
+		mkm.printf("Resuming after %d expansions", cp.Len())
+	}
+
+	mtgban.CheckpointedWorkerPool(ctx, cp, mkm.MaxConcurrency, items,
 		func(ctx context.Context, exp MKMExpansion, channel chan<- responseChan) error {
 			mkm.printf("Processing %s (%d)", exp.Name, exp.IDExpansion)
 			err := mkm.processEdition(ctx, channel, exp.IDExpansion)
@@ -500,11 +538,11 @@ func (mkm *Index) Load(ctx context.Context) error {
 			return nil
 		},
 		func(result responseChan) {
-			err := add(result.cardID, &result.entry)
+			err := add(result.CardID, &result.Entry)
 			if err != nil {
-				card, cerr := mtgmatcher.GetUUID(result.cardID)
+				card, cerr := mtgmatcher.GetUUID(result.CardID)
 				if cerr != nil {
-					mkm.printf("%d - %s: %s", result.ogID, cerr.Error(), result.cardID)
+					mkm.printf("%d - %s: %s", result.OgID, cerr.Error(), result.CardID)
 					return
 				}
 				// Skip too many errors
@@ -513,7 +551,7 @@ func (mkm *Index) Load(ctx context.Context) error {
 					strings.HasPrefix(card.Edition, "World Championship Decks") {
 					return
 				}
-				mkm.printf("%d - %s", result.ogID, err.Error())
+				mkm.printf("%d - %s", result.OgID, err.Error())
 			}
 		},
 		mkm.printf,
@@ -543,6 +581,11 @@ func (mkm *Index) InfoForScraper(name string) mtgban.ScraperInfo {
 	return info
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (mkm *Index) MatchStats() *mtgban.MatchStats {
+	return &mkm.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (mkm *Index) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Card Market Index"
@@ -551,6 +594,10 @@ func (mkm *Index) Info() (info mtgban.ScraperInfo) {
 	info.InventoryTimestamp = &mkm.inventoryDate
 	info.MetadataOnly = true
 	info.Family = "MKM"
+	info.Currency = "EUR"
+	if mkm.exchangeRate != 0 {
+		info.ExchangeRates = map[string]float64{"eur": mkm.exchangeRate}
+	}
 	switch mkm.gameID {
 	case GameMagic:
 		info.Game = mtgban.GameMagic
@@ -41,6 +41,9 @@ type Sealed struct {
 
 	client *MKMClient
 	gameID int
+
+	// Source of the exchange rates, the default one when nil
+	RateProvider mtgban.ExchangeRateProvider
 }
 
 func (mkm *Sealed) printf(format string, a ...any) {
@@ -148,15 +151,17 @@ func (mkm *Sealed) processProduct(ctx context.Context, channel chan<- responseCh
 
 			link := BuildURL(article.IDProduct, GameMagic, mkm.Affiliate, article.IsFoil)
 			out := responseChan{
-				cardID: uuid,
-				entry: mtgban.InventoryEntry{
-					Conditions: "NM",
-					Price:      article.Price * mkm.exchangeRate,
-					Quantity:   article.Count,
-					SellerName: article.Seller.Username,
-					URL:        link,
-					OriginalID: fmt.Sprint(article.IDProduct),
-					InstanceID: fmt.Sprint(article.IDArticle),
+				CardID: uuid,
+				Entry: mtgban.InventoryEntry{
+					Conditions:  "NM",
+					Price:       article.Price * mkm.exchangeRate,
+					Quantity:    article.Count,
+					SellerName:  article.Seller.Username,
+					URL:         link,
+					OriginalID:  fmt.Sprint(article.IDProduct),
+					InstanceID:  fmt.Sprint(article.IDArticle),
+					Currency:    "EUR",
+					NativePrice: article.Price,
 				},
 			}
 			channel <- out
@@ -179,7 +184,7 @@ func (mkm *Sealed) processProduct(ctx context.Context, channel chan<- responseCh
 
 // Load fetches everything this scraper offers. See mtgban.Scraper.
 func (mkm *Sealed) Load(ctx context.Context) error {
-	rate, err := mtgban.GetExchangeRate(ctx, "EUR")
+	rate, err := mtgban.ExchangeRateFrom(ctx, mkm.RateProvider, "EUR")
 	if err != nil {
 		return err
 	}
@@ -297,14 +302,14 @@ func (mkm *Sealed) Load(ctx context.Context) error {
 			return nil
 		},
 		func(result responseChan) {
-			err := mkm.inventory.AddStrict(result.cardID, &result.entry)
+			err := mkm.inventory.AddStrict(result.CardID, &result.Entry)
 			if err != nil {
-				_, cerr := mtgmatcher.GetUUID(result.cardID)
+				_, cerr := mtgmatcher.GetUUID(result.CardID)
 				if cerr != nil {
-					mkm.printf("%s - %s: %s", result.entry.OriginalID, cerr.Error(), result.cardID)
+					mkm.printf("%s - %s: %s", result.Entry.OriginalID, cerr.Error(), result.CardID)
 					return
 				}
-				mkm.printf("%d - %s", result.ogID, err.Error())
+				mkm.printf("%d - %s", result.OgID, err.Error())
 			}
 		},
 		mkm.printf,
@@ -328,6 +333,10 @@ func (mkm *Sealed) Info() (info mtgban.ScraperInfo) {
 	info.CountryFlag = "EU"
 	info.InventoryTimestamp = &mkm.inventoryDate
 	info.SealedMode = true
+	info.Currency = "EUR"
+	if mkm.exchangeRate != 0 {
+		info.ExchangeRates = map[string]float64{"eur": mkm.exchangeRate}
+	}
 	switch mkm.gameID {
 	case GameMagic:
 		info.Game = mtgban.GameMagic
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/mtgban/go-mtgban/mtgban"
 )
 
 // Client reads the Cardsphere API.
@@ -20,7 +21,7 @@ type Client struct {
 // NewClient returns a client using the given token.
 func NewClient(token string) *Client {
 	cs := Client{}
-	client := retryablehttp.NewClient()
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	// The api is very sensitive to multiple concurrent requests,
 	// This backoff strategy lets the system chill out a bit before retrying
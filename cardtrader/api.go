@@ -10,7 +10,7 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/hashicorp/go-retryablehttp"
+	"github.com/mtgban/go-mtgban/mtgban"
 )
 
 const (
@@ -351,7 +351,7 @@ type authTransport struct {
 // NewCTAuthClient returns a client authenticated with the given token.
 func NewCTAuthClient(token string) *CTAuthClient {
 	ct := CTAuthClient{}
-	client := retryablehttp.NewClient()
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	// A full catalog walk gets rate limited partway through; back off for
 	// longer than the default to wait a 429 out rather than fail on it.
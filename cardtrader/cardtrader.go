@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -33,12 +34,18 @@ type Market struct {
 
 	exchangeRates map[string]float64
 	client        *CTAuthClient
+	cartClient    *CTAuthClient
 
 	inventory mtgban.InventoryRecord
 
 	blueprints map[int]*Blueprint
 
 	gameID int
+
+	// Source of the exchange rates, the default one when nil
+	RateProvider mtgban.ExchangeRateProvider
+
+	matchStats mtgban.MatchStats
 }
 
 var availableMarketNames = []string{
@@ -206,7 +213,7 @@ func (ct *Market) processProducts(channel chan<- resultChan, bpID int, products
 
 		if cardID == "" {
 			var err error
-			cardID, err = mtgmatcher.Match(theCard)
+			cardID, err = ct.matchStats.Match(theCard)
 			if errors.Is(err, mtgmatcher.ErrUnsupported) {
 				continue
 			} else if err != nil {
@@ -248,6 +255,7 @@ func (ct *Market) processProducts(channel chan<- resultChan, bpID int, products
 			ct.printf("%v for blueprint %d", err, product.BlueprintID)
 			continue
 		}
+		currency, native := nativePrice(product.Price.Cents, product.Price.Currency)
 
 		// Assign a seller name as required by Market
 		sellerName := availableMarketNames[0]
@@ -266,21 +274,28 @@ func (ct *Market) processProducts(channel chan<- resultChan, bpID int, products
 			}
 		}
 
+		customFields := map[string]string{
+			"SubSellerName": product.User.Name,
+			"SubSellerGeo":  product.User.CountryCode,
+		}
+		if product.Bundle {
+			customFields["Playset"] = "true"
+		}
+
 		channel <- resultChan{
 			cardID: cardID,
 			invEntry: &mtgban.InventoryEntry{
-				Conditions: conditions,
-				Price:      price,
-				Quantity:   qty,
-				URL:        link,
-				SellerName: sellerName,
-				Bundle:     product.User.SinglesZero,
-				OriginalID: fmt.Sprint(product.BlueprintID),
-				InstanceID: fmt.Sprint(product.ID),
-				CustomFields: map[string]string{
-					"SubSellerName": product.User.Name,
-					"SubSellerGeo":  product.User.CountryCode,
-				},
+				Conditions:   conditions,
+				Price:        price,
+				Quantity:     qty,
+				URL:          link,
+				SellerName:   sellerName,
+				Bundle:       product.User.SinglesZero,
+				OriginalID:   fmt.Sprint(product.BlueprintID),
+				InstanceID:   fmt.Sprint(product.ID),
+				CustomFields: customFields,
+				Currency:     currency,
+				NativePrice:  native,
 			},
 		}
 	}
@@ -301,7 +316,7 @@ func (ct *Market) processExpansion(ctx context.Context, channel chan<- resultCha
 
 // Load fetches everything this scraper offers. See mtgban.Scraper.
 func (ct *Market) Load(ctx context.Context) error {
-	rates, err := mtgban.GetExchangeRates(ctx)
+	rates, err := mtgban.ExchangeRatesFrom(ctx, ct.RateProvider)
 	if err != nil {
 		return err
 	}
@@ -386,6 +401,72 @@ func (ct *Market) InfoForScraper(name string) mtgban.ScraperInfo {
 	return info
 }
 
+// Activate prepares the market to fill the cart of the account owning the
+// given token, or of the one the market was created with when it is empty.
+// The password is not used: the API authenticates by token alone.
+func (ct *Market) Activate(ctx context.Context, token, _ string) error {
+	ct.cartClient = ct.client
+	if token != "" {
+		ct.cartClient = NewCTAuthClient(token)
+	}
+	return nil
+}
+
+// Add puts entry in the cart. See mtgban.Carter.
+//
+// The InstanceID is the listing to buy, and a listing from one of the Zero
+// storefronts is added through Zero so it ships with the rest of that order.
+// Playset listings are counted in single cards, so their quantity must be a
+// whole number of playsets.
+func (ct *Market) Add(ctx context.Context, entry mtgban.InventoryEntry) error {
+	if ct.cartClient == nil {
+		return mtgban.ErrCartNotActive
+	}
+
+	productID, err := strconv.Atoi(entry.InstanceID)
+	if err != nil {
+		return fmt.Errorf("invalid product id %q: %w", entry.InstanceID, err)
+	}
+	qty := entry.Quantity
+	if qty < 1 {
+		qty = 1
+	}
+	if entry.CustomFields["Playset"] == "true" {
+		if qty%4 != 0 {
+			return fmt.Errorf("quantity %d of product %d is not a whole number of playsets", qty, productID)
+		}
+		qty /= 4
+	}
+
+	zero := entry.Bundle
+	switch entry.SellerName {
+	case availableMarketNames[0]:
+		zero = false
+	case availableMarketNames[1], availableMarketNames[2]:
+		zero = true
+	}
+
+	response, err := ct.cartClient.AddProductToCart(ctx, productID, qty, zero)
+	if err != nil {
+		return err
+	}
+	for _, subcart := range response.Subcarts {
+		for _, item := range subcart.CartItems {
+			if item.Product.ID == productID && item.Quantity < qty {
+				return fmt.Errorf("only %d of %d added for product %d (%s)",
+					item.Quantity, qty, productID, subcart.Seller.Username)
+			}
+		}
+	}
+
+	return nil
+}
+
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (ct *Market) MatchStats() *mtgban.MatchStats {
+	return &ct.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (ct *Market) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Card Trader"
@@ -393,6 +474,7 @@ func (ct *Market) Info() (info mtgban.ScraperInfo) {
 	info.InventoryTimestamp = &ct.inventoryDate
 	info.CountryFlag = "EU"
 	info.Family = "CT"
+	info.ExchangeRates = ct.exchangeRates
 	switch ct.gameID {
 	case GameMagic:
 		info.Game = mtgban.GameMagic
@@ -0,0 +1,68 @@
+package cardtrader
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/go-mtgban/mtgban/scrapertest"
+)
+
+func TestMarketAdd(t *testing.T) {
+	ct := &Market{}
+	err := ct.Add(context.Background(), mtgban.InventoryEntry{InstanceID: "1"})
+	if !errors.Is(err, mtgban.ErrCartNotActive) {
+		t.Fatalf("expected ErrCartNotActive, got %v", err)
+	}
+
+	// The fixtures only answer the requests each entry should make, so a
+	// wrong product, quantity or storefront is a miss failing the test; playset
+	// listings are bought by the playset
+	scrapertest.Replay(t, "testdata/cart")
+	err = ct.Activate(context.Background(), "token", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		entry   mtgban.InventoryEntry
+		failure bool
+	}{
+		{"direct seller", mtgban.InventoryEntry{InstanceID: "1", SellerName: "Card Trader", Quantity: 2, Bundle: true}, false},
+		{"zero storefront", mtgban.InventoryEntry{InstanceID: "2", SellerName: "Card Trader Zero"}, false},
+		{"bundle of a seller", mtgban.InventoryEntry{InstanceID: "3", SellerName: "someone", Bundle: true}, false},
+		{"playsets", mtgban.InventoryEntry{InstanceID: "5", SellerName: "Card Trader", Quantity: 8, CustomFields: map[string]string{"Playset": "true"}}, false},
+		{"partial playset", mtgban.InventoryEntry{InstanceID: "5", SellerName: "Card Trader", Quantity: 6, CustomFields: map[string]string{"Playset": "true"}}, true},
+		{"partial fill", mtgban.InventoryEntry{InstanceID: "4", SellerName: "Card Trader", Quantity: 5}, true},
+		{"invalid id", mtgban.InventoryEntry{InstanceID: "abc", SellerName: "Card Trader"}, true},
+		{"refused", mtgban.InventoryEntry{InstanceID: "404", SellerName: "Card Trader"}, true},
+	}
+
+	var entries []mtgban.InventoryEntry
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ct.Add(context.Background(), test.entry)
+			if (err != nil) != test.failure {
+				t.Errorf("expected failure %v, got %v", test.failure, err)
+			}
+		})
+		entries = append(entries, test.entry)
+	}
+
+	// The failures are reported one by one, the rest added
+	err = mtgban.AddToCart(context.Background(), ct, entries)
+	var cartErr *mtgban.CartError
+	if !errors.As(err, &cartErr) {
+		t.Fatalf("expected a CartError, got %v", err)
+	}
+	if cartErr.Total != len(entries) || len(cartErr.Failures) != 4 {
+		t.Fatalf("expected 4 of %d failures, got %+v", len(entries), cartErr)
+	}
+	for i, instanceID := range []string{"5", "4", "abc", "404"} {
+		if cartErr.Failures[i].Entry.InstanceID != instanceID {
+			t.Errorf("failure %d: expected %s, got %s", i, instanceID, cartErr.Failures[i].Entry.InstanceID)
+		}
+	}
+}
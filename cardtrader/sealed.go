@@ -26,6 +26,9 @@ type Sealed struct {
 	inventoryDate time.Time
 	inventory     mtgban.InventoryRecord
 	gameID        int
+
+	// Source of the exchange rates, the default one when nil
+	RateProvider mtgban.ExchangeRateProvider
 }
 
 // NewScraperSealed returns a sealed scraper for one game, authenticated with a
@@ -102,6 +105,7 @@ func (ct *Sealed) processEntry(ctx context.Context, channel chan<- resultChan, e
 				ct.printf("%v for blueprint %d", err, product.BlueprintID)
 				continue
 			}
+			currency, native := nativePrice(product.Price.Cents, product.Price.Currency)
 
 			// Assign a seller name as required by Market
 			sellerName := availableMarketNames[0]
@@ -127,6 +131,8 @@ func (ct *Sealed) processEntry(ctx context.Context, channel chan<- resultChan, e
 						"SubSellerName": product.User.Name,
 						"SubSellerGeo":  product.User.CountryCode,
 					},
+					Currency:    currency,
+					NativePrice: native,
 				},
 			}
 		}
@@ -137,7 +143,7 @@ func (ct *Sealed) processEntry(ctx context.Context, channel chan<- resultChan, e
 
 // Load fetches everything this scraper offers. See mtgban.Scraper.
 func (ct *Sealed) Load(ctx context.Context) error {
-	rates, err := mtgban.GetExchangeRates(ctx)
+	rates, err := mtgban.ExchangeRatesFrom(ctx, ct.RateProvider)
 	if err != nil {
 		return err
 	}
@@ -284,6 +290,7 @@ func (ct *Sealed) Info() (info mtgban.ScraperInfo) {
 	info.InventoryTimestamp = &ct.inventoryDate
 	info.CountryFlag = "EU"
 	info.SealedMode = true
+	info.ExchangeRates = ct.exchangeRates
 	switch ct.gameID {
 	case GameMagic:
 		info.Game = mtgban.GameMagic
@@ -34,15 +34,25 @@ func priceToUSD(cents int, currency string, rates map[string]float64) (float64,
 	return price * rate, nil
 }
 
+// nativePrice returns the currency and amount a listing was quoted in, to be
+// kept next to the converted price, or nothing when it was quoted in dollars.
+func nativePrice(cents int, currency string) (string, float64) {
+	if currency == "USD" {
+		return "", 0
+	}
+	return currency, float64(cents) / 100
+}
+
 // ExportStock returns your own listings as an InventoryRecord, using the
-// Simple API token rather than the full one.
-func (ct *CTAuthClient) ExportStock(ctx context.Context, blueprints map[int]*Blueprint) (mtgban.InventoryRecord, error) {
+// Simple API token rather than the full one. Prices are converted with the
+// rates of provider, or of mtgban.DefaultExchangeRateProvider when nil.
+func (ct *CTAuthClient) ExportStock(ctx context.Context, blueprints map[int]*Blueprint, provider mtgban.ExchangeRateProvider) (mtgban.InventoryRecord, error) {
 	products, err := ct.ProductsExport(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	rates, err := mtgban.GetExchangeRates(ctx)
+	rates, err := mtgban.ExchangeRatesFrom(ctx, provider)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +80,8 @@ func (ct *CTAuthClient) ExportStock(ctx context.Context, blueprints map[int]*Blu
 			continue
 		}
 
+		currency, native := nativePrice(product.PriceCents, product.PriceCurrency)
+
 		quantity := product.Quantity
 
 		condition, found := condMap[product.Properties.Condition]
@@ -78,12 +90,14 @@ func (ct *CTAuthClient) ExportStock(ctx context.Context, blueprints map[int]*Blu
 		}
 
 		inventory.AddRelaxed(cardID, &mtgban.InventoryEntry{
-			Price:      price,
-			Quantity:   quantity,
-			Conditions: condition,
-			SellerName: "mtgban",
-			OriginalID: fmt.Sprint(product.BlueprintID),
-			InstanceID: fmt.Sprint(product.ID),
+			Price:       price,
+			Quantity:    quantity,
+			Conditions:  condition,
+			SellerName:  "mtgban",
+			OriginalID:  fmt.Sprint(product.BlueprintID),
+			InstanceID:  fmt.Sprint(product.ID),
+			Currency:    currency,
+			NativePrice: native,
 		})
 	}
 
@@ -132,6 +146,7 @@ func ConvertProducts(blueprints map[int]*Blueprint, products []Product, rates ma
 		if err != nil {
 			continue
 		}
+		nativeCurrency, native := nativePrice(cents, currency)
 
 		quantity := product.Quantity
 
@@ -162,6 +177,8 @@ func ConvertProducts(blueprints map[int]*Blueprint, products []Product, rates ma
 			OriginalID:   fmt.Sprint(product.BlueprintID),
 			InstanceID:   fmt.Sprint(product.ID),
 			CustomFields: customFields,
+			Currency:     nativeCurrency,
+			NativePrice:  native,
 		})
 	}
 
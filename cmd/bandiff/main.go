@@ -0,0 +1,125 @@
+// Command bandiff compares two dumps of the same scraper, as bantool writes
+// them, and reports what changed between the two runs.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+
+	_ "github.com/mtgban/go-mtgban/mtgmatcher/games"
+)
+
+// readDump loads both sides of a json dump, either of which may be empty.
+func readDump(filename string) (mtgban.InventoryRecord, mtgban.BuylistRecord, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seller, err := mtgban.ReadSellerFromJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	vendor, err := mtgban.ReadVendorFromJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return seller.Inventory(), vendor.Buylist(), nil
+}
+
+func run() int {
+	minDiffOpt := flag.Float64("min-diff", 0, "Minimum absolute price change of a significant move")
+	minSpreadOpt := flag.Float64("min-spread", 0, "Minimum percentage price change of a significant move")
+	minPriceOpt := flag.Float64("min-price", 0, "Minimum price of a significant move")
+	onlySignificantOpt := flag.Bool("significant", false, "Only output significant price moves")
+	fileFormatOpt := flag.String("format", "json", "File format of the output (json/csv)")
+	datastoreOpt := flag.String("datastore", "", "Path to AllPrintings file, required for csv")
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: bandiff [options] <old.json> <new.json>")
+		flag.PrintDefaults()
+		return 1
+	}
+
+	switch *fileFormatOpt {
+	case "json":
+	case "csv":
+		if *datastoreOpt == "" {
+			fmt.Fprintln(os.Stderr, "Missing datastore argument, required for csv")
+			return 1
+		}
+		err := mtgmatcher.LoadDatastoreFile(*datastoreOpt)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	default:
+		fmt.Fprintln(os.Stderr, "Invalid -format option, see -h for supported values")
+		return 1
+	}
+
+	oldInv, oldBl, err := readDump(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	newInv, newBl, err := readDump(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	opts := &mtgban.DiffOpts{
+		MinDiff:   *minDiffOpt,
+		MinSpread: *minSpreadOpt,
+		MinPrice:  *minPriceOpt,
+	}
+
+	var diff *mtgban.SnapshotDiff
+	switch {
+	case len(oldInv) != 0 || len(newInv) != 0:
+		diff = mtgban.DiffInventory(opts, oldInv, newInv)
+	case len(oldBl) != 0 || len(newBl) != 0:
+		diff = mtgban.DiffBuylist(opts, oldBl, newBl)
+	default:
+		fmt.Fprintln(os.Stderr, errors.New("both dumps are empty"))
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "%d cards added, %d removed, %d price moves (%d significant), %d quantity changes\n",
+		len(diff.Added), len(diff.Removed), len(diff.PriceMoves), len(diff.SignificantMoves()), len(diff.QuantityChanges))
+	fmt.Fprintf(os.Stderr, "%d names added %v, %d removed %v\n",
+		len(diff.NamesAdded), diff.NamesAdded, len(diff.NamesRemoved), diff.NamesRemoved)
+
+	if *onlySignificantOpt {
+		diff.PriceMoves = diff.SignificantMoves()
+	}
+
+	switch *fileFormatOpt {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(diff)
+	case "csv":
+		err = mtgban.WriteDiffToCSV(diff.PriceMoves, os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+func main() {
+	os.Exit(run())
+}
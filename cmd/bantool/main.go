@@ -18,12 +18,12 @@ import (
 	"os/signal"
 	"path"
 	"runtime/debug"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
-	"github.com/scizorman/go-ndjson"
 
 	_ "github.com/joho/godotenv/autoload"
 	"github.com/mtgban/go-mtgban/abugames"
@@ -1645,60 +1645,6 @@ var options = map[string]*scraperOption{
 	},
 }
 
-type inventoryElement struct {
-	UUID string
-	mtgban.InventoryEntry
-}
-
-type buylistElement struct {
-	UUID string
-	mtgban.BuylistEntry
-}
-
-func writeSellerToNDJSON(seller mtgban.Seller, w io.Writer) error {
-	inventory := seller.Inventory()
-
-	var inventoryFlat []inventoryElement
-	for uuid, entries := range inventory {
-		for _, entry := range entries {
-			inventoryFlat = append(inventoryFlat, inventoryElement{
-				UUID:           uuid,
-				InventoryEntry: entry,
-			})
-		}
-	}
-
-	output, err := ndjson.Marshal(inventoryFlat)
-	if err != nil {
-		return err
-	}
-
-	_, err = w.Write(output)
-	return err
-}
-
-func writeVendorToNDJSON(vendor mtgban.Vendor, w io.Writer) error {
-	buylist := vendor.Buylist()
-
-	var buylistFlat []buylistElement
-	for uuid, entries := range buylist {
-		for _, entry := range entries {
-			buylistFlat = append(buylistFlat, buylistElement{
-				UUID:         uuid,
-				BuylistEntry: entry,
-			})
-		}
-	}
-
-	output, err := ndjson.Marshal(buylistFlat)
-	if err != nil {
-		return err
-	}
-
-	_, err = w.Write(output)
-	return err
-}
-
 func dumpSeller(dataBucket simplecloud.Writer, seller mtgban.Seller, outputPath, format string) (err error) {
 	if len(seller.Inventory()) == 0 {
 		return fmt.Errorf("seller %s has no data", seller.Info().Shorthand)
@@ -1723,10 +1669,12 @@ func dumpSeller(dataBucket simplecloud.Writer, seller mtgban.Seller, outputPath,
 	switch strings.Split(format, ".")[0] {
 	case "json":
 		err = mtgban.WriteSellerToJSON(seller, writer)
+	case "col":
+		err = mtgban.WriteSellerToColumnar(seller, writer)
 	case "csv":
 		err = mtgban.WriteInventoryToCSV(seller.Inventory(), writer)
 	case "ndjson":
-		err = writeSellerToNDJSON(seller, writer)
+		err = mtgban.WriteSellerToNDJSON(seller, writer)
 	default:
 		err = errors.New("invalid format")
 	}
@@ -1758,10 +1706,12 @@ func dumpVendor(dataBucket simplecloud.Writer, vendor mtgban.Vendor, outputPath,
 	switch strings.Split(format, ".")[0] {
 	case "json":
 		err = mtgban.WriteVendorToJSON(vendor, writer)
+	case "col":
+		err = mtgban.WriteVendorToColumnar(vendor, writer)
 	case "csv":
 		err = mtgban.WriteBuylistToCSV(vendor.Buylist(), vendor.Info().CreditMultiplier, writer)
 	case "ndjson":
-		err = writeVendorToNDJSON(vendor, writer)
+		err = mtgban.WriteVendorToNDJSON(vendor, writer)
 	default:
 		err = errors.New("invalid format")
 	}
@@ -1769,6 +1719,55 @@ func dumpVendor(dataBucket simplecloud.Writer, vendor mtgban.Vendor, outputPath,
 	return err
 }
 
+// dumpMeta writes the json sidecar holding info alone next to the retail or
+// buylist file whose format does not carry it, which mtgban.ReadSellerFromFile
+// and mtgban.ReadVendorFromFile pick up.
+func dumpMeta(dataBucket simplecloud.Writer, kind string, info mtgban.ScraperInfo, outputPath string) (err error) {
+	target := fmt.Sprintf("%s/%s/%s.json", outputPath, kind, info.Shorthand)
+	log.Println("Writing", target)
+
+	writer, err := simplecloud.InitWriter(context.Background(), dataBucket, target)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		cerr := writer.Close()
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	switch kind {
+	case "retail":
+		err = mtgban.WriteSellerToJSON(mtgban.NewSellerFromInventory(nil, info), writer)
+	case "buylist":
+		err = mtgban.WriteVendorToJSON(mtgban.NewVendorFromBuylist(nil, info), writer)
+	default:
+		err = errors.New("invalid kind")
+	}
+	return err
+}
+
+// dumpMatchStats writes the match report of a scraper as a sidecar next to
+// its retail or buylist file.
+func dumpMatchStats(dataBucket simplecloud.Writer, kind, shorthand string, stats *mtgban.MatchStats, outputPath string) (err error) {
+	target := fmt.Sprintf("%s/%s/%s.match.json", outputPath, kind, shorthand)
+	log.Println("Writing", target)
+
+	writer, err := simplecloud.InitWriter(context.Background(), dataBucket, target)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		cerr := writer.Close()
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	return mtgban.WriteMatchReportToJSON(stats, writer)
+}
+
 func dump(dataBucket simplecloud.Writer, scrapers []mtgban.Scraper, outputPath, format string, meta bool) []error {
 	log.Println("Writing results to", outputPath)
 
@@ -1786,10 +1785,10 @@ func dump(dataBucket simplecloud.Writer, scrapers []mtgban.Scraper, outputPath,
 			continue
 		}
 
-		if meta && format != "json" {
-			sellerMeta := mtgban.NewSellerFromInventory(nil, seller.Info())
-			err := dumpSeller(dataBucket, sellerMeta, outputPath, "json")
+		if meta && !mtgban.HasOwnInfo(strings.Split(format, ".")[0]) {
+			err := dumpMeta(dataBucket, "retail", seller.Info(), outputPath)
 			if err != nil {
+				log.Println(err)
 				sellerErrs = append(sellerErrs, err)
 				continue
 			}
@@ -1805,17 +1804,36 @@ func dump(dataBucket simplecloud.Writer, scrapers []mtgban.Scraper, outputPath,
 			continue
 		}
 
-		if meta && format != "json" {
-			vendorMeta := mtgban.NewVendorFromBuylist(nil, vendor.Info())
-			err := dumpVendor(dataBucket, vendorMeta, outputPath, "json")
+		if meta && !mtgban.HasOwnInfo(strings.Split(format, ".")[0]) {
+			err := dumpMeta(dataBucket, "buylist", vendor.Info(), outputPath)
 			if err != nil {
+				log.Println(err)
 				vendorErrs = append(vendorErrs, err)
 				continue
 			}
 		}
 	}
 
-	return append(sellerErrs, vendorErrs...)
+	// The match reports are per scraper, not per unfolded seller or vendor
+	var matchErrs []error
+	for _, scraper := range scrapers {
+		reporter, ok := scraper.(mtgban.MatchReporter)
+		if !ok {
+			continue
+		}
+		kind := "buylist"
+		if _, ok := scraper.(mtgban.Seller); ok {
+			kind = "retail"
+		}
+		err := dumpMatchStats(dataBucket, kind, scraper.Info().Shorthand, reporter.MatchStats(), outputPath)
+		if err != nil {
+			log.Println(err)
+			matchErrs = append(matchErrs, err)
+		}
+	}
+
+	errs := append(sellerErrs, vendorErrs...)
+	return append(errs, matchErrs...)
 }
 
 // HTTPBucket reads a datastore over plain HTTP, for the files served rather
@@ -1936,15 +1954,26 @@ func run() int {
 		flag.BoolVar(&val.Enabled, key, false, "Enable "+label)
 	}
 
-	datastoreOpt := flag.String("datastore", "", "Path to AllPrintings file")
+	datastoreOpt := flag.String("datastore", "", "Path to AllPrintings file, or to a snapshot of it")
+	snapshotOpt := flag.String("write-snapshot", "", "Path where to write a snapshot of the datastore once loaded, for -datastore to start faster next time")
 	outputPathOpt := flag.String("output-path", "", "Path where to dump results")
 
 	scrapersOpt := flag.String("scrapers", "", "Comma-separated list of scrapers to enable")
 	sellersOpt := flag.String("sellers", "", "Comma-separated list of sellers to enable")
 	vendorsOpt := flag.String("vendors", "", "Comma-separated list of vendors to enable")
 
-	fileFormatOpt := flag.String("format", "json", "File format of the output files (json/csv/ndjson)")
-	metaOpt := flag.Bool("meta", false, "When format is not json, output a second file for scraper metadata")
+	fileFormatOpt := flag.String("format", "json", "File format of the output files (json/col/csv/ndjson)")
+	metaOpt := flag.Bool("meta", false, "When format is csv or ndjson, output a second file for scraper metadata")
+	ratesOpt := flag.String("rates", "latest", "Exchange rates to convert prices with: latest, a YYYY-MM-DD snapshot, or a path to a rates file")
+	recordOpt := flag.String("record", "", "Path to a directory where to save every response received, for replaying in tests")
+	checkpointOpt := flag.String("checkpoint", "", "Path to a directory where scrapers keep their progress, resuming from it after a failed run")
+	clearCheckpointOpt := flag.Bool("clear-checkpoint", false, "Delete any checkpoint before loading, starting from zero")
+	rateOpt := flag.Float64("rate", 0, "Maximum requests per second sent to each host, 0 for no limit")
+	burstOpt := flag.Int("burst", 1, "Requests that may be sent to a host at once, when -rate is set")
+	hostConcurrencyOpt := flag.Int("host-concurrency", 0, "Maximum requests in flight to each host, 0 for no limit until the host pushes back")
+	consensusOpt := flag.String("consensus", "", "Comma-separated list of loaded sellers and vendors (by shorthand) to build a Consensus price index from")
+	consensusMinOpt := flag.Int("consensus-min-sources", 2, "Sources needed for a card to be part of the Consensus index")
+	consensusOutlierOpt := flag.Float64("consensus-outliers", 3, "Deviations from the median past which a price is left out of the Consensus index, 0 to keep all")
 
 	signOpt := flag.String("sign", "", "Sign input")
 	versionOpt := flag.Bool("v", false, "Print version information")
@@ -1966,7 +1995,7 @@ func run() int {
 	}
 
 	switch strings.Split(*fileFormatOpt, ".")[0] {
-	case "json", "csv", "ndjson":
+	case "json", "col", "csv", "ndjson":
 	default:
 		log.Println("Invalid -format option, see -h for supported values")
 		return 1
@@ -2048,10 +2077,58 @@ func run() int {
 	}
 	log.Println("loading datastore took:", time.Since(now))
 
+	if *snapshotOpt != "" {
+		err = writeSnapshot(*snapshotOpt)
+		if err != nil {
+			log.Println(err)
+			return 1
+		}
+		log.Println("datastore snapshot written to", *snapshotOpt)
+	}
+
+	// Every client of the run shares the same pacing per host, if asked for
+	var throttled bool
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "rate", "burst", "host-concurrency":
+			throttled = true
+		}
+	})
+	if throttled {
+		mtgban.DefaultHostThrottle = &mtgban.HostThrottle{
+			Rate:           *rateOpt,
+			Burst:          *burstOpt,
+			MaxConcurrency: *hostConcurrencyOpt,
+		}
+	}
+
+	// Every scraper of the run converts with the same rates, fetched once
+	mtgban.DefaultExchangeRateProvider = mtgban.NewCachedRates(mtgban.ParseExchangeRateProvider(*ratesOpt), 0)
+
+	// Scrapers build their clients on init, so the hook goes in first
+	if *recordOpt != "" {
+		recorder, err := mtgban.NewRecorder(*recordOpt)
+		if err != nil {
+			log.Println(err)
+			return 1
+		}
+		mtgban.TransportHook = recorder.Wrap
+		log.Println("Recording responses to", *recordOpt)
+	}
+
+	if *checkpointOpt != "" && *clearCheckpointOpt {
+		err := os.RemoveAll(*checkpointOpt)
+		if err != nil {
+			log.Println(err)
+			return 1
+		}
+	}
+
 	var scrapers []mtgban.Scraper
+	checkpoints := map[mtgban.Scraper]string{}
 
 	// Initialize the enabled scrapers
-	for _, opt := range options {
+	for key, opt := range options {
 		if !opt.Enabled {
 			continue
 		}
@@ -2065,9 +2142,15 @@ func run() int {
 		// Check if any sub data source needs to be disabled
 		config, ok := scraper.(mtgban.ScraperConfig)
 		if ok {
+			var checkpointDir string
+			if *checkpointOpt != "" {
+				checkpointDir = path.Join(*checkpointOpt, key)
+				checkpoints[scraper] = checkpointDir
+			}
 			config.SetConfig(mtgban.ScraperOptions{
 				DisableRetail:  opt.OnlyVendor,
 				DisableBuylist: opt.OnlySeller,
+				CheckpointDir:  checkpointDir,
 			})
 		}
 
@@ -2094,11 +2177,52 @@ func run() int {
 		if err != nil {
 			log.Println(err)
 			nonFatalErrors = append(nonFatalErrors, err)
+			continue
+		}
+
+		// A complete load leaves nothing to resume, and the next run
+		// must not pick up stale results
+		checkpointDir, found := checkpoints[scraper]
+		if found && ctx.Err() == nil {
+			err = os.RemoveAll(checkpointDir)
+			if err != nil {
+				log.Println(err)
+			}
 		}
 	}
 
 	log.Println("loading scraper data took:", time.Since(now))
 
+	// The index is dumped like any other seller
+	if *consensusOpt != "" {
+		shorthands := strings.Split(*consensusOpt, ",")
+		sellers, vendors := mtgban.UnfoldScrapers(scrapers)
+		sellers = slices.DeleteFunc(sellers, func(seller mtgban.Seller) bool {
+			return !slices.Contains(shorthands, seller.Info().Shorthand)
+		})
+		vendors = slices.DeleteFunc(vendors, func(vendor mtgban.Vendor) bool {
+			return !slices.Contains(shorthands, vendor.Info().Shorthand)
+		})
+		for _, shorthand := range shorthands {
+			found := slices.ContainsFunc(sellers, func(seller mtgban.Seller) bool {
+				return seller.Info().Shorthand == shorthand
+			}) || slices.ContainsFunc(vendors, func(vendor mtgban.Vendor) bool {
+				return vendor.Info().Shorthand == shorthand
+			})
+			if !found {
+				err := fmt.Errorf("consensus source %q matches no loaded seller or vendor", shorthand)
+				log.Println(err)
+				nonFatalErrors = append(nonFatalErrors, err)
+			}
+		}
+		index := mtgban.ConsensusIndex(&mtgban.ConsensusOpts{
+			OutlierThreshold: *consensusOutlierOpt,
+			MinSources:       *consensusMinOpt,
+		}, sellers, vendors)
+		log.Println("Consensus index built from", len(sellers), "sellers and", len(vendors), "vendors")
+		scrapers = append(scrapers, index)
+	}
+
 	now = time.Now()
 	// Dump the results
 	dumpErrors := dump(dataBucket, scrapers, *outputPathOpt, *fileFormatOpt, *metaOpt)
@@ -2158,3 +2282,17 @@ func signAPI(link string) (string, error) {
 
 	return str, nil
 }
+
+// writeSnapshot saves the loaded datastore to a local file.
+func writeSnapshot(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = mtgmatcher.WriteSnapshot(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/simplecloud"
+)
+
+func TestDumpMeta(t *testing.T) {
+	inventory := mtgban.InventoryRecord{
+		"x|A|SET|1": {{Conditions: "NM", Price: 2, Quantity: 1, URL: "a"}},
+	}
+	buylist := mtgban.BuylistRecord{
+		"x|A|SET|1": {{Conditions: "NM", BuyPrice: 1, Quantity: 4, PriceRatio: 50}},
+	}
+	info := mtgban.ScraperInfo{Name: "Test Store", Shorthand: "TST", CountryFlag: "EU", CreditMultiplier: 1.3}
+	scrapers := []mtgban.Scraper{
+		mtgban.NewSellerFromInventory(inventory, info),
+		mtgban.NewVendorFromBuylist(buylist, info),
+	}
+
+	for _, format := range []string{"ndjson", "csv"} {
+		dir := t.TempDir()
+		for _, kind := range []string{"retail", "buylist"} {
+			err := os.Mkdir(filepath.Join(dir, kind), 0755)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		errs := dump(&simplecloud.FileBucket{}, scrapers, dir, format, true)
+		if len(errs) != 0 {
+			t.Fatalf("%s: %v", format, errs)
+		}
+
+		seller, err := mtgban.ReadSellerFromFile(filepath.Join(dir, "retail", "TST."+format))
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if seller.Info().Name != info.Name || seller.Info().CountryFlag != info.CountryFlag {
+			t.Errorf("%s: the seller sidecar was not read back: %+v", format, seller.Info())
+		}
+		if !reflect.DeepEqual(seller.Inventory(), inventory) {
+			t.Errorf("%s: expected %v, got %v", format, inventory, seller.Inventory())
+		}
+
+		vendor, err := mtgban.ReadVendorFromFile(filepath.Join(dir, "buylist", "TST."+format))
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if vendor.Info().Name != info.Name || vendor.Info().CreditMultiplier != info.CreditMultiplier {
+			t.Errorf("%s: the vendor sidecar was not read back: %+v", format, vendor.Info())
+		}
+		if len(vendor.Buylist()["x|A|SET|1"]) != 1 {
+			t.Errorf("%s: expected %v, got %v", format, buylist, vendor.Buylist())
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Command matchExplain matches one card description and prints every stage
+// the matcher went through, to find which one lost the card.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+	_ "github.com/mtgban/go-mtgban/mtgmatcher/games"
+)
+
+func run() int {
+	datastoreOpt := flag.String("datastore", "allprintings5.json", "Path to the datastore file, of any game")
+	idOpt := flag.String("id", "", "Identifier of the card")
+	nameOpt := flag.String("name", "", "Name of the card")
+	editionOpt := flag.String("edition", "", "Edition of the card")
+	variantOpt := flag.String("variant", "", "Variation of the card")
+	foilOpt := flag.Bool("foil", false, "Whether the card is foil")
+	finishOpt := flag.String("finish", "", "Finish of the card, as the store names it")
+	languageOpt := flag.String("language", "", "Language of the card")
+	jsonOpt := flag.Bool("json", false, "Print the trace as JSON")
+
+	flag.Parse()
+
+	if *nameOpt == "" && *idOpt == "" {
+		flag.PrintDefaults()
+		return 1
+	}
+
+	envDatastore := os.Getenv("ALLPRINTINGS5_PATH")
+	if envDatastore != "" {
+		datastoreOpt = &envDatastore
+	}
+
+	err := mtgmatcher.LoadDatastoreFile(*datastoreOpt)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	inCard := &mtgmatcher.InputCard{
+		ID:        *idOpt,
+		Name:      *nameOpt,
+		Edition:   *editionOpt,
+		Variation: *variantOpt,
+		Foil:      *foilOpt,
+		Finish:    *finishOpt,
+		Language:  *languageOpt,
+	}
+	cardID, trace, err := mtgmatcher.MatchExplain(inCard)
+
+	if *jsonOpt {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(trace)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		printTrace(trace)
+	}
+
+	if err != nil {
+		return 2
+	}
+	co, err := mtgmatcher.GetUUID(cardID)
+	if err == nil {
+		fmt.Println(cardID, co)
+	}
+	return 0
+}
+
+func printTrace(trace *mtgmatcher.MatchTrace) {
+	fmt.Println("Input:", describe(trace.Input))
+	for _, step := range trace.Steps {
+		fmt.Printf("%-22s %s\n", step.Stage, describe(step.Input))
+		if step.Output != nil {
+			fmt.Printf("%-22s -> %s\n", "", describe(*step.Output))
+		}
+		if len(step.Printings) > 0 {
+			fmt.Printf("%-22s printings: %s\n", "", strings.Join(step.Printings, " "))
+		}
+		for _, card := range step.Candidates {
+			fmt.Printf("%-22s - %s\n", "", card)
+		}
+		if step.Result != "" {
+			fmt.Printf("%-22s = %s\n", "", step.Result)
+		}
+	}
+}
+
+// describe prints every field of the input, internal state included, since
+// the hooks may change any of them.
+func describe(inCard mtgmatcher.InputCard) string {
+	var fields []string
+	for _, field := range []struct {
+		key, value string
+	}{
+		{"id", inCard.ID},
+		{"name", inCard.Name},
+		{"edition", inCard.Edition},
+		{"variant", inCard.Variation},
+		{"finish", inCard.Finish},
+		{"language", inCard.Language},
+		{"original", inCard.OriginalName},
+	} {
+		if field.value != "" {
+			fields = append(fields, fmt.Sprintf("%s=%q", field.key, field.value))
+		}
+	}
+	for _, option := range []struct {
+		key   string
+		value bool
+	}{
+		{"foil", inCard.Foil},
+		{"promo-wildcard", inCard.PromoWildcard},
+		{"beyond-base-set", inCard.BeyondBaseSet},
+	} {
+		if option.value {
+			fields = append(fields, option.key)
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+func main() {
+	os.Exit(run())
+}
@@ -12,7 +12,7 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/hashicorp/go-cleanhttp"
+	"github.com/mtgban/go-mtgban/mtgban"
 )
 
 // CSICard is one card in the price list.
@@ -47,7 +47,7 @@ type CSIClient struct {
 // NewCSIClient returns a client using the given key.
 func NewCSIClient(key string) *CSIClient {
 	csi := CSIClient{}
-	csi.client = cleanhttp.DefaultClient()
+	csi.client = mtgban.NewHTTPClient()
 	csi.key = key
 	return &csi
 }
@@ -145,7 +145,7 @@ func fetchBuylist(ctx context.Context, link string) ([]CSIPriceEntry, error) {
 	// Disable gzip compression
 	req.Header.Set("Accept-Encoding", "identity")
 
-	resp, err := cleanhttp.DefaultClient().Do(req)
+	resp, err := mtgban.NewHTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +172,7 @@ func LoadBuylistEditions(ctx context.Context, game string) (map[string]string, e
 	if err != nil {
 		return nil, err
 	}
-	resp, err := cleanhttp.DefaultClient().Do(req)
+	resp, err := mtgban.NewHTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -258,7 +258,7 @@ func Search(ctx context.Context, game, itemName string, skipOOS bool) (*SearchRe
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
 	req.Header.Set("User-Agent", "curl/8.6.0")
 
-	resp, err := cleanhttp.DefaultClient().Do(req)
+	resp, err := mtgban.NewHTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -16,7 +16,6 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/hashicorp/go-retryablehttp"
 
 	"github.com/mtgban/go-mtgban/mtgban"
 	"github.com/mtgban/go-mtgban/mtgmatcher"
@@ -75,6 +74,8 @@ type Coolstuffinc struct {
 
 	client *http.Client
 	game   string
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a singles scraper for one game.
@@ -82,7 +83,7 @@ func NewScraper(game string) *Coolstuffinc {
 	csi := Coolstuffinc{}
 	csi.inventory = mtgban.InventoryRecord{}
 	csi.buylist = mtgban.BuylistRecord{}
-	client := retryablehttp.NewClient()
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	csi.client = client.StandardClient()
 	csi.MaxConcurrency = defaultConcurrency
@@ -255,7 +256,7 @@ func (csi *Coolstuffinc) processSearch(ctx context.Context, results chan<- respo
 					return
 				}
 
-				cardID, err := mtgmatcher.Match(theCard)
+				cardID, err := csi.matchStats.Match(theCard)
 				if errors.Is(err, mtgmatcher.ErrUnsupported) {
 					return
 				} else if err != nil {
@@ -458,7 +459,7 @@ func (csi *Coolstuffinc) parseBL(ctx context.Context) error {
 			return errors.New("unsupported game")
 		}
 
-		cardID, err := mtgmatcher.Match(theCard)
+		cardID, err := csi.matchStats.Match(theCard)
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			continue
 		} else if err != nil {
@@ -572,6 +573,11 @@ func (csi *Coolstuffinc) InfoForScraper(name string) mtgban.ScraperInfo {
 	return info
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (csi *Coolstuffinc) MatchStats() *mtgban.MatchStats {
+	return &csi.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (csi *Coolstuffinc) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Cool Stuff Inc"
@@ -17,7 +17,6 @@ import (
 	"github.com/mtgban/go-mtgban/mtgmatcher"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/hashicorp/go-retryablehttp"
 )
 
 const (
@@ -47,6 +46,11 @@ type Hareruya struct {
 	TargetEdition string
 
 	client *http.Client
+
+	// Source of the exchange rates, the default one when nil
+	RateProvider mtgban.ExchangeRateProvider
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a singles scraper.
@@ -55,7 +59,9 @@ func NewScraper() *Hareruya {
 	ha.inventory = mtgban.InventoryRecord{}
 	ha.buylist = mtgban.BuylistRecord{}
 	ha.MaxConcurrency = defaultConcurrency
-	client := retryablehttp.NewClient()
+	// Hareruya answers 403 once queried too much
+	mtgban.ThrottleOnForbidden("www.hareruyamtg.com")
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	ha.client = client.StandardClient()
 	return &ha
@@ -159,6 +165,7 @@ func (ha *Hareruya) processBuylistPage(ctx context.Context, channel chan<- respo
 			ha.printf("page %d entry %d: %s", page, i, err.Error())
 			return false
 		}
+		nativePrice := price
 		price *= ha.exchangeRate
 
 		// Since we're sorting by price, as soon as we found an item that is not
@@ -174,7 +181,7 @@ func (ha *Hareruya) processBuylistPage(ctx context.Context, channel chan<- respo
 			return true
 		}
 
-		cardID, err := mtgmatcher.Match(theCard)
+		cardID, err := ha.matchStats.Match(theCard)
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			return true
 		} else if err != nil {
@@ -212,11 +219,13 @@ func (ha *Hareruya) processBuylistPage(ctx context.Context, channel chan<- respo
 			out := responseChan{
 				cardID: cardID,
 				buyEntry: &mtgban.BuylistEntry{
-					Conditions: mtgban.DefaultGradeTags[i],
-					BuyPrice:   price * deduction,
-					PriceRatio: priceRatio,
-					URL:        "https://www.hareruyamtg.com" + link,
-					OriginalID: id,
+					Conditions:  mtgban.DefaultGradeTags[i],
+					BuyPrice:    price * deduction,
+					PriceRatio:  priceRatio,
+					URL:         "https://www.hareruyamtg.com" + link,
+					OriginalID:  id,
+					Currency:    "JPY",
+					NativePrice: nativePrice * deduction,
 				},
 			}
 
@@ -258,7 +267,7 @@ func (ha *Hareruya) processSet(ctx context.Context, channel chan<- responseChan,
 				continue
 			}
 
-			cardID, err := mtgmatcher.Match(theCard)
+			cardID, err := ha.matchStats.Match(theCard)
 			if errors.Is(err, mtgmatcher.ErrUnsupported) {
 				continue
 			} else if err != nil {
@@ -299,12 +308,14 @@ func (ha *Hareruya) processSet(ctx context.Context, channel chan<- responseChan,
 					out := responseChan{
 						cardID: cardID,
 						invEntry: &mtgban.InventoryEntry{
-							Price:      price,
-							Conditions: cond,
-							Quantity:   qty,
-							URL:        link,
-							OriginalID: product.Product,
-							InstanceID: product.ProductClass,
+							Price:       price,
+							Conditions:  cond,
+							Quantity:    qty,
+							URL:         link,
+							OriginalID:  product.Product,
+							InstanceID:  product.ProductClass,
+							Currency:    "JPY",
+							NativePrice: row.Price,
 						},
 					}
 
@@ -373,13 +384,18 @@ func (ha *Hareruya) getLazy(ctx context.Context, products []Product, attempt int
 	defer resp.Body.Close()
 
 	// The system stops returning results after querying ~60 consecutive results
-	// So sleep for 5 minutes, and try again for 5 times
+	// The host throttle slows down on the 403, but the block lasts longer than
+	// that, so sleep for 5 minutes, and try again for 5 times
 	if resp.StatusCode == 403 {
 		if attempt > 5 {
 			return nil, errors.New("timeout exceeded")
 		}
 		ha.printf("lazy request returned forbidden, trying again in a bit (%d)", attempt)
-		time.Sleep(5 * 60 * time.Second)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Minute):
+		}
 		return ha.getLazy(ctx, products, attempt)
 	}
 
@@ -512,7 +528,7 @@ func (ha *Hareruya) getCardSets(ctx context.Context) ([]string, error) {
 }
 
 func (ha *Hareruya) scrape(ctx context.Context, mode string) error {
-	rate, err := mtgban.GetExchangeRate(ctx, "JPY")
+	rate, err := mtgban.ExchangeRateFrom(ctx, ha.RateProvider, "JPY")
 	if err != nil {
 		return err
 	}
@@ -621,6 +637,11 @@ func (ha *Hareruya) Buylist() mtgban.BuylistRecord {
 	return ha.buylist
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (ha *Hareruya) MatchStats() *mtgban.MatchStats {
+	return &ha.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (ha *Hareruya) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Hareruya"
@@ -628,5 +649,9 @@ func (ha *Hareruya) Info() (info mtgban.ScraperInfo) {
 	info.CountryFlag = "JP"
 	info.InventoryTimestamp = &ha.inventoryDate
 	info.BuylistTimestamp = &ha.buylistDate
+	info.Currency = "JPY"
+	if ha.exchangeRate != 0 {
+		info.ExchangeRates = map[string]float64{"jpy": ha.exchangeRate}
+	}
 	return
 }
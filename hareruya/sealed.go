@@ -14,7 +14,6 @@ import (
 	"github.com/mtgban/go-mtgban/mtgmatcher"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/hashicorp/go-retryablehttp"
 )
 
 // Sealed prices Hareruya's sealed product.
@@ -29,6 +28,9 @@ type Sealed struct {
 	buylist   mtgban.BuylistRecord
 
 	client *http.Client
+
+	// Source of the exchange rates, the default one when nil
+	RateProvider mtgban.ExchangeRateProvider
 }
 
 // NewScraperSealed returns a sealed scraper.
@@ -36,7 +38,7 @@ func NewScraperSealed() *Sealed {
 	ha := Sealed{}
 	ha.inventory = mtgban.InventoryRecord{}
 	ha.buylist = mtgban.BuylistRecord{}
-	client := retryablehttp.NewClient()
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	ha.client = client.StandardClient()
 	return &ha
@@ -137,7 +139,7 @@ func (ha *Sealed) buylistPrices(ctx context.Context) (map[string]float64, error)
 
 // Load fetches everything this scraper offers. See mtgban.Scraper.
 func (ha *Sealed) Load(ctx context.Context) error {
-	rate, err := mtgban.GetExchangeRate(ctx, "JPY")
+	rate, err := mtgban.ExchangeRateFrom(ctx, ha.RateProvider, "JPY")
 	if err != nil {
 		return err
 	}
@@ -191,11 +193,13 @@ func (ha *Sealed) Load(ctx context.Context) error {
 				link := "https://www.hareruyamtg.com/en/products/detail/" + product.Product + "?lang=EN&class=" + product.ProductClass
 
 				out := &mtgban.InventoryEntry{
-					Conditions: "NM",
-					Price:      price * ha.exchangeRate,
-					URL:        link,
-					OriginalID: product.Product,
-					InstanceID: product.ProductClass,
+					Conditions:  "NM",
+					Price:       price * ha.exchangeRate,
+					URL:         link,
+					OriginalID:  product.Product,
+					InstanceID:  product.ProductClass,
+					Currency:    "JPY",
+					NativePrice: price,
 				}
 				err = ha.inventory.Add(sealedProduct.UUID, out)
 				if err != nil {
@@ -212,11 +216,13 @@ func (ha *Sealed) Load(ctx context.Context) error {
 				}
 
 				out := &mtgban.BuylistEntry{
-					Conditions: "NM",
-					BuyPrice:   buyPrice * ha.exchangeRate,
-					PriceRatio: priceRatio,
-					URL:        "https://www.hareruyamtg.com/ja/purchase/detail/" + haID,
-					OriginalID: haID,
+					Conditions:  "NM",
+					BuyPrice:    buyPrice * ha.exchangeRate,
+					PriceRatio:  priceRatio,
+					URL:         "https://www.hareruyamtg.com/ja/purchase/detail/" + haID,
+					OriginalID:  haID,
+					Currency:    "JPY",
+					NativePrice: buyPrice,
 				}
 				err = ha.buylist.Add(sealedProduct.UUID, out)
 				if err != nil {
@@ -252,6 +258,10 @@ func (ha *Sealed) Info() (info mtgban.ScraperInfo) {
 	info.InventoryTimestamp = &ha.inventoryDate
 	info.BuylistTimestamp = &ha.buylistDate
 	info.SealedMode = true
+	info.Currency = "JPY"
+	if ha.exchangeRate != 0 {
+		info.ExchangeRates = map[string]float64{"jpy": ha.exchangeRate}
+	}
 	// The unisearch API only exposes an unreliable aggregate stock count, so
 	// per-item quantity is not reported.
 	info.NoQuantityInventory = true
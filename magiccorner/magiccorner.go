@@ -33,6 +33,11 @@ type Magiccorner struct {
 	inventory mtgban.InventoryRecord
 	buylist   mtgban.BuylistRecord
 	client    *MCClient
+
+	// Source of the exchange rates, the default one when nil
+	RateProvider mtgban.ExchangeRateProvider
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a scraper, failing if the edition list cannot be read.
@@ -128,7 +133,7 @@ func (mc *Magiccorner) processEntry(ctx context.Context, channel chan<- resultCh
 				continue
 			}
 
-			cardID, err := mtgmatcher.Match(theCard)
+			cardID, err := mc.matchStats.Match(theCard)
 			if errors.Is(err, mtgmatcher.ErrUnsupported) {
 				continue
 			} else if err != nil {
@@ -156,12 +161,14 @@ func (mc *Magiccorner) processEntry(ctx context.Context, channel chan<- resultCh
 			channel <- resultChan{
 				cardID: cardID,
 				invEntry: &mtgban.InventoryEntry{
-					Conditions: cond,
-					Price:      v.Price * mc.exchangeRate,
-					Quantity:   v.Quantity,
-					URL:        "https://www.magiccorner.it" + card.URL,
-					OriginalID: fmt.Sprint(card.ID),
-					InstanceID: fmt.Sprint(v.ID),
+					Conditions:  cond,
+					Price:       v.Price * mc.exchangeRate,
+					Quantity:    v.Quantity,
+					URL:         "https://www.magiccorner.it" + card.URL,
+					OriginalID:  fmt.Sprint(card.ID),
+					InstanceID:  fmt.Sprint(v.ID),
+					Currency:    "EUR",
+					NativePrice: v.Price,
 				},
 			}
 
@@ -211,7 +218,7 @@ func (mc *Magiccorner) Load(ctx context.Context) error {
 	// Both sides price in euro, so the rate has to be in place before either
 	// runs: fetched from the retail path alone it stayed zero whenever retail
 	// was disabled, and every buy price came out at zero with it
-	rate, err := mtgban.GetExchangeRate(ctx, "EUR")
+	rate, err := mtgban.ExchangeRateFrom(ctx, mc.RateProvider, "EUR")
 	if err != nil {
 		return err
 	}
@@ -277,7 +284,7 @@ func (mc *Magiccorner) parseBL(ctx context.Context, channel chan<- resultChan, e
 				continue
 			}
 
-			cardID, err := mtgmatcher.Match(theCard)
+			cardID, err := mc.matchStats.Match(theCard)
 			if errors.Is(err, mtgmatcher.ErrUnsupported) {
 				continue
 			} else if err != nil {
@@ -314,11 +321,13 @@ func (mc *Magiccorner) parseBL(ctx context.Context, channel chan<- resultChan, e
 				channel <- resultChan{
 					cardID: cardID,
 					buyEntry: &mtgban.BuylistEntry{
-						Quantity:   quantity,
-						Conditions: grade,
-						BuyPrice:   price * mc.exchangeRate * factor,
-						URL:        link,
-						OriginalID: product.ID,
+						Quantity:    quantity,
+						Conditions:  grade,
+						BuyPrice:    price * mc.exchangeRate * factor,
+						URL:         link,
+						OriginalID:  product.ID,
+						Currency:    "EUR",
+						NativePrice: price * factor,
 					},
 				}
 			}
@@ -366,6 +375,11 @@ func (mc *Magiccorner) scrapeBL(ctx context.Context) error {
 	return nil
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (mc *Magiccorner) MatchStats() *mtgban.MatchStats {
+	return &mc.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (mc *Magiccorner) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Magic Corner"
@@ -373,5 +387,9 @@ func (mc *Magiccorner) Info() (info mtgban.ScraperInfo) {
 	info.CountryFlag = "EU"
 	info.InventoryTimestamp = &mc.inventoryDate
 	info.BuylistTimestamp = &mc.buylistDate
+	info.Currency = "EUR"
+	if mc.exchangeRate != 0 {
+		info.ExchangeRates = map[string]float64{"eur": mc.exchangeRate}
+	}
 	return
 }
@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/hashicorp/go-cleanhttp"
 	"github.com/mtgban/go-mtgban/mtgban"
 	"github.com/mtgban/go-mtgban/mtgmatcher"
 )
@@ -88,7 +87,7 @@ func (mm *Miniaturemarket) processPage(ctx context.Context, channel chan<- respC
 	if err != nil {
 		return err
 	}
-	resp, err := cleanhttp.DefaultClient().Do(req)
+	resp, err := mtgban.NewHTTPClient().Do(req)
 	if err != nil {
 		return err
 	}
@@ -159,7 +158,7 @@ func (mm *Miniaturemarket) NumberOfProducts(ctx context.Context) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	resp, err := cleanhttp.DefaultClient().Do(req)
+	resp, err := mtgban.NewHTTPClient().Do(req)
 	if err != nil {
 		return 0, err
 	}
@@ -27,6 +27,8 @@ type MTGMintCard struct {
 	buylist   mtgban.BuylistRecord
 
 	SKUsData tcgplayer.SKUMap
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a scraper.
@@ -75,7 +77,7 @@ func (mint *MTGMintCard) processEntry(sku2uuid map[int]string, card Card, condit
 			return
 		}
 
-		cardID, err = mtgmatcher.Match(theCard)
+		cardID, err = mint.matchStats.Match(theCard)
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			return
 		} else if err != nil {
@@ -260,6 +262,11 @@ func grading(cardID string, price float64) map[string]float64 {
 	}
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (mint *MTGMintCard) MatchStats() *mtgban.MatchStats {
+	return &mint.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (mint *MTGMintCard) Info() (info mtgban.ScraperInfo) {
 	info.Name = "MTG Mint Card"
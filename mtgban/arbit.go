@@ -58,6 +58,10 @@ type ArbitOpts struct {
 	// List of editions (or set codes) to select
 	OnlyEditions []string
 
+	// List of card uuids to select, such as the result of a mtgmatcher/query
+	// search
+	OnlyUUIDs []string
+
 	// List of per-edition collector numbers to select
 	OnlyCollectorNumberRanges map[string][2]int
 
@@ -92,6 +96,19 @@ type ArbitOpts struct {
 
 	// List of languages to select
 	OnlyLanguages []string
+
+	// Fees and shipping costs used to compute the net numbers of Arbit,
+	// which are the same as the gross ones when nil
+	Fees *FeeModel
+
+	// Whether Arbit should estimate the buylist offer of a condition the
+	// vendor does not list, scaling its best listed one by a grade ladder
+	CrossCondition bool
+
+	// Ratio of each condition to NM, keyed by VendorName or vendor
+	// Shorthand, with the ladder under "" used for the vendors missing, and
+	// a generic ladder when that is missing too
+	GradeLadders map[string]map[string]float64
 }
 
 // ArbitEntry is one card worth acting on, carrying both sides of the
@@ -120,9 +137,26 @@ type ArbitEntry struct {
 	// Difference of the prices accounting for quantities available
 	AbsoluteDifference float64
 
+	// Difference of the prices after fees and shipping, per card
+	NetDifference float64
+
+	// Spread between what the card brings in and what it costs after fees
+	// and shipping
+	NetSpread float64
+
+	// Net difference of the prices accounting for quantities available
+	NetAbsoluteDifference float64
+
+	// Whether the order of the entry falls short of the MinOrder of its fee
+	// schedule, on either side
+	BelowMinimum bool
+
 	// Amount of cards that can be applied
 	Quantity int
 
+	// Whether the buylist price was estimated from another condition
+	Estimated bool
+
 	// The higher the number the better the arbit is. Using this formula
 	// Profitability Index (PI) =
 	//   (Difference / (Sell Price + k)) * log10(1 + Spread) * sqrt(Units)
@@ -168,12 +202,18 @@ type resolvedOpts struct {
 	filterEditions         []string
 	filterSelectedEditions []string
 	filterSelectedCNRange  map[string][2]int
+	filterSelectedUUIDs    map[string]bool
 	filterSellers          []string
 	filterFunc             func(co *mtgmatcher.CardObject) (float64, bool)
 	filterPriceFunc        func(string, InventoryEntry) (float64, bool)
 
 	filterLanguages         []string
 	filterSelectedLanguages []string
+
+	fees *FeeModel
+
+	crossCondition bool
+	gradeLadders   map[string]map[string]float64
 }
 
 func resolveOpts(opts *ArbitOpts) resolvedOpts {
@@ -216,7 +256,16 @@ func resolveOpts(opts *ArbitOpts) resolvedOpts {
 	r.filterLanguages = opts.Languages
 	r.filterSelectedLanguages = opts.OnlyLanguages
 	r.filterSelectedCNRange = opts.OnlyCollectorNumberRanges
+	if opts.OnlyUUIDs != nil {
+		r.filterSelectedUUIDs = make(map[string]bool, len(opts.OnlyUUIDs))
+		for _, uuid := range opts.OnlyUUIDs {
+			r.filterSelectedUUIDs[uuid] = true
+		}
+	}
 	r.filterSellers = opts.Sellers
+	r.fees = opts.Fees
+	r.crossCondition = opts.CrossCondition
+	r.gradeLadders = opts.GradeLadders
 
 	return r
 }
@@ -224,6 +273,9 @@ func resolveOpts(opts *ArbitOpts) resolvedOpts {
 // filterCard checks whether a card should be skipped based on the resolved
 // options. Returns the custom factor and true if the card should be kept.
 func (r *resolvedOpts) filterCard(cardID string) (*mtgmatcher.CardObject, float64, bool) {
+	if r.filterSelectedUUIDs != nil && !r.filterSelectedUUIDs[cardID] {
+		return nil, 0, false
+	}
 	co, err := mtgmatcher.GetUUID(cardID)
 	if err != nil {
 		return nil, 0, false
@@ -280,8 +332,11 @@ func (r *resolvedOpts) filterCard(cardID string) (*mtgmatcher.CardObject, float6
 // opts filters the rest.
 func Arbit(opts *ArbitOpts, vendor Vendor, seller Seller) []ArbitEntry {
 	var result []ArbitEntry
+	// Inventory prices after the rate and custom factors, needed for fees
+	var prices []float64
 
 	r := resolveOpts(opts)
+	shorthand := vendor.Info().Shorthand
 
 	for cardID, blEntries := range vendor.Buylist() {
 		invEntries, found := seller.Inventory()[cardID]
@@ -312,6 +367,7 @@ func Arbit(opts *ArbitOpts, vendor Vendor, seller Seller) []ArbitEntry {
 			// so a value carried over from a previous iteration would
 			// price this entry against another entry's grade
 			blEntry := nmEntry
+			estimated := false
 
 			if slices.Contains(r.filterConditions, invEntry.Conditions) {
 				continue
@@ -351,9 +407,16 @@ func Arbit(opts *ArbitOpts, vendor Vendor, seller Seller) []ArbitEntry {
 				}
 				blEntry = blEntries[i]
 				// If, after looping, a matching condition was not found,
-				// just skip the current invEntry
+				// skip the current invEntry, unless asked to estimate it
 				if blEntry.Conditions != invEntry.Conditions {
-					continue
+					if !r.crossCondition {
+						continue
+					}
+					blEntry, ok = r.estimateGrade(shorthand, nmEntry, invEntry.Conditions)
+					if !ok {
+						continue
+					}
+					estimated = true
 				}
 			}
 
@@ -400,22 +463,61 @@ func Arbit(opts *ArbitOpts, vendor Vendor, seller Seller) []ArbitEntry {
 			}
 
 			res := ArbitEntry{
-				CardID:             cardID,
-				BuylistEntry:       blEntry,
-				InventoryEntry:     invEntry,
-				Difference:         difference,
-				AbsoluteDifference: difference * float64(qty),
-				Spread:             spread,
-				Quantity:           qty,
-				Profitability:      profitability,
+				CardID:                cardID,
+				BuylistEntry:          blEntry,
+				InventoryEntry:        invEntry,
+				Difference:            difference,
+				AbsoluteDifference:    difference * float64(qty),
+				NetDifference:         difference,
+				NetAbsoluteDifference: difference * float64(qty),
+				Spread:                spread,
+				NetSpread:             spread,
+				Quantity:              qty,
+				Profitability:         profitability,
+				Estimated:             estimated,
 			}
 			result = append(result, res)
+			prices = append(prices, price)
 		}
 	}
 
+	if r.fees != nil {
+		result = r.fees.apply(result, prices, vendor.Info(), seller.Info())
+	}
+
 	return result
 }
 
+// estimateGrade derives the offer for conditions from the best graded one a
+// vendor lists. Only worse conditions are estimated, as a vendor not listing
+// a better grade is no sign it would pay more for it.
+func (r *resolvedOpts) estimateGrade(shorthand string, best BuylistEntry, conditions string) (BuylistEntry, bool) {
+	if slices.Index(FullGradeTags, conditions) <= slices.Index(FullGradeTags, best.Conditions) {
+		return BuylistEntry{}, false
+	}
+
+	ladder, found := r.gradeLadders[best.VendorName]
+	if !found {
+		ladder, found = r.gradeLadders[shorthand]
+	}
+	if !found {
+		ladder, found = r.gradeLadders[""]
+	}
+	if !found {
+		ladder = defaultGradeMap
+	}
+
+	bestGrade := ladder[best.Conditions]
+	grade := ladder[conditions]
+	if bestGrade <= 0 || grade <= 0 {
+		return BuylistEntry{}, false
+	}
+
+	best.Conditions = conditions
+	best.BuyPrice *= grade / bestGrade
+	return best, true
+}
+
 // A generic grading map that estimates common deductions
 var defaultGradeMap = map[string]float64{
 	"NM": 1, "SP": 0.8, "MP": 0.6, "HP": 0.4, "PO": 0,
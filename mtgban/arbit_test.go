@@ -0,0 +1,49 @@
+package mtgban
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateGrade(t *testing.T) {
+	r := resolveOpts(&ArbitOpts{
+		CrossCondition: true,
+		GradeLadders: map[string]map[string]float64{
+			"CK": {"NM": 1, "SP": 0.8, "MP": 0.7, "HP": 0.5},
+		},
+	})
+	best := BuylistEntry{Conditions: "NM", BuyPrice: 10, VendorName: "Card Kingdom"}
+
+	tests := []struct {
+		shorthand  string
+		best       BuylistEntry
+		conditions string
+		price      float64
+		ok         bool
+	}{
+		// Ladder selected by shorthand
+		{"CK", best, "MP", 7, true},
+		// Generic ladder
+		{"XX", best, "MP", 6, true},
+		// Scaled from the best listed grade rather than from NM
+		{"CK", BuylistEntry{Conditions: "SP", BuyPrice: 8}, "HP", 5, true},
+		// Better grades are never estimated
+		{"CK", BuylistEntry{Conditions: "SP", BuyPrice: 8}, "NM", 0, false},
+		// The generic ladder does not buy PO
+		{"XX", best, "PO", 0, false},
+	}
+
+	for _, test := range tests {
+		entry, ok := r.estimateGrade(test.shorthand, test.best, test.conditions)
+		if ok != test.ok {
+			t.Errorf("%s %s: expected %v, got %v", test.shorthand, test.conditions, test.ok, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if entry.Conditions != test.conditions || math.Abs(entry.BuyPrice-test.price) > 1e-9 {
+			t.Errorf("%s %s: expected %f, got %s %f", test.shorthand, test.conditions, test.price, entry.Conditions, entry.BuyPrice)
+		}
+	}
+}
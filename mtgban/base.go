@@ -56,23 +56,29 @@ func (inv InventoryRecord) add(cardID string, entry *InventoryEntry, strict int)
 	inv[cardID] = append(inv[cardID], *entry)
 
 	// Keep array sorted
-	sort.Slice(inv[cardID], func(i, j int) bool {
-		iIdx := slices.Index(FullGradeTags, inv[cardID][i].Conditions)
-		jIdx := slices.Index(FullGradeTags, inv[cardID][j].Conditions)
+	sortInventoryEntries(inv[cardID])
+
+	return nil
+}
+
+// sortInventoryEntries orders entries the way an InventoryRecord keeps them:
+// by grade, then cheapest first, then largest quantity first.
+func sortInventoryEntries(entries []InventoryEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		iIdx := slices.Index(FullGradeTags, entries[i].Conditions)
+		jIdx := slices.Index(FullGradeTags, entries[j].Conditions)
 
 		if iIdx == jIdx {
-			if inv[cardID][i].Price == inv[cardID][j].Price {
+			if entries[i].Price == entries[j].Price {
 				// Prioritize higher quantity for same price and same condition
-				return inv[cardID][i].Quantity > inv[cardID][j].Quantity
+				return entries[i].Quantity > entries[j].Quantity
 			}
 			// Prioritize lower prices first for the same condition
-			return inv[cardID][i].Price < inv[cardID][j].Price
+			return entries[i].Price < entries[j].Price
 		}
 
 		return iIdx < jIdx
 	})
-
-	return nil
 }
 
 // AddRelaxed adds a record to the inventory, always merging into an existing
@@ -136,23 +142,29 @@ func (bl BuylistRecord) add(cardID string, entry *BuylistEntry, strict bool) err
 
 	bl[cardID] = append(bl[cardID], *entry)
 
-	sort.Slice(bl[cardID], func(i, j int) bool {
-		iIdx := slices.Index(FullGradeTags, bl[cardID][i].Conditions)
-		jIdx := slices.Index(FullGradeTags, bl[cardID][j].Conditions)
+	sortBuylistEntries(bl[cardID])
+
+	return nil
+}
+
+// sortBuylistEntries orders entries the way a BuylistRecord keeps them: by
+// grade, then highest offer first, then largest quantity first.
+func sortBuylistEntries(entries []BuylistEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		iIdx := slices.Index(FullGradeTags, entries[i].Conditions)
+		jIdx := slices.Index(FullGradeTags, entries[j].Conditions)
 
 		if iIdx == jIdx {
-			if bl[cardID][i].BuyPrice == bl[cardID][j].BuyPrice {
+			if entries[i].BuyPrice == entries[j].BuyPrice {
 				// Prioritize higher quantity for same price and same condition
-				return bl[cardID][i].Quantity > bl[cardID][j].Quantity
+				return entries[i].Quantity > entries[j].Quantity
 			}
 			// Prioritize higher prices first for the same condition
-			return bl[cardID][i].BuyPrice > bl[cardID][j].BuyPrice
+			return entries[i].BuyPrice > entries[j].BuyPrice
 		}
 
 		return iIdx < jIdx
 	})
-
-	return nil
 }
 
 // BaseSeller holds an inventory that has already been collected. It is what
@@ -0,0 +1,89 @@
+package mtgban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrCartNotActive is returned by a Carter asked to Add before Activate.
+var ErrCartNotActive = errors.New("cart not activated")
+
+// CartFailure is one entry a Carter refused, with the reason it gave.
+type CartFailure struct {
+	Entry InventoryEntry
+	Err   error
+}
+
+// CartError reports the entries AddToCart could not place. The others made it
+// into the cart, so a caller can retry the failures alone instead of the
+// whole order.
+type CartError struct {
+	// Number of entries that were attempted
+	Total int
+
+	// The entries that were not added, in the order they were attempted
+	Failures []CartFailure
+}
+
+// Error summarizes the failures, quoting the first one.
+func (e *CartError) Error() string {
+	if len(e.Failures) == 0 {
+		return "no cart failures"
+	}
+	first := e.Failures[0]
+	return fmt.Sprintf("%d of %d entries not added to cart, first: %s %s: %v",
+		len(e.Failures), e.Total, first.Entry.InstanceID, first.Entry.SellerName, first.Err)
+}
+
+// Unwrap exposes every per-entry error to errors.Is and errors.As.
+func (e *CartError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		errs = append(errs, failure.Err)
+	}
+	return errs
+}
+
+// AddToCart pushes each entry through carter, carrying on past the ones it
+// refuses. The result is nil when every entry was added, a *CartError listing
+// the ones that were not otherwise. A cancelled ctx stops the loop, and the
+// entries never attempted are reported as failed with the context error.
+func AddToCart(ctx context.Context, carter Carter, entries []InventoryEntry) error {
+	cartErr := CartError{
+		Total: len(entries),
+	}
+
+	for _, entry := range entries {
+		err := ctx.Err()
+		if err == nil {
+			err = carter.Add(ctx, entry)
+		}
+		if err != nil {
+			cartErr.Failures = append(cartErr.Failures, CartFailure{
+				Entry: entry,
+				Err:   err,
+			})
+		}
+	}
+
+	if len(cartErr.Failures) == 0 {
+		return nil
+	}
+	return &cartErr
+}
+
+// AddArbitToCart pushes the inventory side of an Arbit or Mismatch result to
+// carter, buying the tradable Quantity of each entry rather than everything
+// the seller has listed.
+func AddArbitToCart(ctx context.Context, carter Carter, arbit []ArbitEntry) error {
+	entries := make([]InventoryEntry, 0, len(arbit))
+	for _, ae := range arbit {
+		entry := ae.InventoryEntry
+		if ae.Quantity > 0 {
+			entry.Quantity = ae.Quantity
+		}
+		entries = append(entries, entry)
+	}
+	return AddToCart(ctx, carter, entries)
+}
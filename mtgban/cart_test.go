@@ -0,0 +1,78 @@
+package mtgban
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// refusingCarter adds everything except the instance ids it is told to refuse.
+type refusingCarter struct {
+	refuse map[string]bool
+	added  []InventoryEntry
+}
+
+func (c *refusingCarter) Activate(ctx context.Context, cuser, pass string) error {
+	return nil
+}
+
+func (c *refusingCarter) Add(ctx context.Context, entry InventoryEntry) error {
+	if c.refuse[entry.InstanceID] {
+		return errors.New("out of stock")
+	}
+	c.added = append(c.added, entry)
+	return nil
+}
+
+func TestAddToCartReportsEachFailure(t *testing.T) {
+	carter := &refusingCarter{refuse: map[string]bool{"2": true}}
+	entries := []InventoryEntry{
+		{InstanceID: "1", Quantity: 1},
+		{InstanceID: "2", Quantity: 1},
+		{InstanceID: "3", Quantity: 1},
+	}
+
+	err := AddToCart(context.Background(), carter, entries)
+	var cartErr *CartError
+	if !errors.As(err, &cartErr) {
+		t.Fatalf("expected a CartError, got %v", err)
+	}
+	if cartErr.Total != 3 || len(cartErr.Failures) != 1 || cartErr.Failures[0].Entry.InstanceID != "2" {
+		t.Errorf("unexpected failures: %+v", cartErr)
+	}
+	if len(carter.added) != 2 {
+		t.Errorf("a refused entry stopped the rest, %d added", len(carter.added))
+	}
+
+	carter.refuse = nil
+	if err := AddToCart(context.Background(), carter, entries); err != nil {
+		t.Errorf("a fully added cart reported %v", err)
+	}
+}
+
+func TestAddToCartStopsOnCancel(t *testing.T) {
+	carter := &refusingCarter{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := AddToCart(ctx, carter, []InventoryEntry{{InstanceID: "1"}, {InstanceID: "2"}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancellation to be reported, got %v", err)
+	}
+	if len(carter.added) != 0 {
+		t.Errorf("%d entries added after cancellation", len(carter.added))
+	}
+}
+
+func TestAddArbitToCartUsesTradableQuantity(t *testing.T) {
+	carter := &refusingCarter{}
+	arbit := []ArbitEntry{
+		{InventoryEntry: InventoryEntry{InstanceID: "1", Quantity: 12}, Quantity: 3},
+	}
+	if err := AddArbitToCart(context.Background(), carter, arbit); err != nil {
+		t.Fatal(err)
+	}
+	if len(carter.added) != 1 || carter.added[0].Quantity != 3 {
+		t.Errorf("expected 3 copies in the cart, got %+v", carter.added)
+	}
+}
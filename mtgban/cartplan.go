@@ -0,0 +1,394 @@
+package mtgban
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// WishlistItem is a card to buy, in any condition as good as Conditions or
+// better, or in any condition at all when Conditions is empty.
+type WishlistItem struct {
+	CardID     string
+	Conditions string
+	Quantity   int
+}
+
+// CartOpts sets the shipping costs OptimizeCart weighs the prices against.
+type CartOpts struct {
+	// Shipping cost of an order, keyed by SubSellerName or SellerName
+	Shipping map[string]float64
+
+	// Shipping cost of the orders missing from Shipping
+	DefaultShipping float64
+
+	// Shipping cost of an order of Bundle entries, which ship together no
+	// matter who listed them, and the total above which it is waived
+	BundleShipping         float64
+	BundleFreeShippingOver float64
+
+	// List of seller names that will be considered, all when empty
+	Sellers []string
+}
+
+// CartEntry is a listing picked by OptimizeCart, with Quantity set to the
+// amount to buy rather than the amount available.
+type CartEntry struct {
+	CardID string
+	Entry  InventoryEntry
+}
+
+// CartOrder is what a CartPlan buys from one seller, or from the bundle of a
+// seller name.
+type CartOrder struct {
+	SellerName string
+
+	// The seller inside the market, from CustomFields["SubSellerName"]
+	SubSellerName string
+
+	// Whether the order is made of Bundle entries
+	Bundle bool
+
+	Entries  []CartEntry
+	Subtotal float64
+	Shipping float64
+}
+
+// CartPlan is the set of orders that fills a wishlist at the lowest cost
+// OptimizeCart found, shipping included.
+type CartPlan struct {
+	Orders []CartOrder
+
+	// The part of the wishlist that no listing could fill
+	Missing []WishlistItem
+
+	Subtotal float64
+	Shipping float64
+	Total    float64
+}
+
+// InventoryEntries returns the entries of every order, ready for AddToCart.
+func (plan *CartPlan) InventoryEntries() []InventoryEntry {
+	var entries []InventoryEntry
+	for _, order := range plan.Orders {
+		for _, entry := range order.Entries {
+			entries = append(entries, entry.Entry)
+		}
+	}
+	return entries
+}
+
+// AddPlanToCart pushes every entry of plan to carter, see AddToCart.
+func AddPlanToCart(ctx context.Context, carter Carter, plan *CartPlan) error {
+	return AddToCart(ctx, carter, plan.InventoryEntries())
+}
+
+// OptimizeCart looks for the cheapest way to buy wishlist from the sellers of
+// market, weighing the price of every listing against the shipping cost of
+// the order it would add to. A wishlist item whose Conditions is neither
+// empty nor one of FullGradeTags fails with ErrInvalidCondition.
+func OptimizeCart(opts *CartOpts, wishlist []WishlistItem, market Market) (*CartPlan, error) {
+	inventory := InventoryRecord{}
+	for _, name := range market.MarketNames() {
+		if opts != nil && len(opts.Sellers) > 0 && !slices.Contains(opts.Sellers, name) {
+			continue
+		}
+		for cardID, entries := range InventoryForSeller(market, name) {
+			inventory[cardID] = append(inventory[cardID], entries...)
+		}
+	}
+	return OptimizeInventoryCart(opts, wishlist, inventory)
+}
+
+// OptimizeInventoryCart is OptimizeCart over an inventory of several sellers.
+//
+// Picking the sellers is a facility location problem, so the result is a
+// good plan rather than the best one: every unit starts at its cheapest
+// listing, then orders are dropped one at a time, moving their units to the
+// orders left, for as long as that lowers the total. A plan never trades a
+// wishlist unit for a lower total.
+func OptimizeInventoryCart(opts *CartOpts, wishlist []WishlistItem, inventory InventoryRecord) (*CartPlan, error) {
+	for _, item := range wishlist {
+		if item.Conditions != "" && !slices.Contains(FullGradeTags, item.Conditions) {
+			return nil, fmt.Errorf("wishlist item %s: %w", item.CardID, ErrInvalidCondition)
+		}
+	}
+
+	var o CartOpts
+	if opts != nil {
+		o = *opts
+	}
+
+	solver := cartSolver{
+		opts:     &o,
+		wishlist: wishlist,
+		orders:   map[cartOrderKey]bool{},
+	}
+	solver.collect(inventory)
+
+	allowed := map[cartOrderKey]bool{}
+	for key := range solver.orders {
+		allowed[key] = true
+	}
+	best := solver.solve(allowed)
+
+	for {
+		improved := false
+		for _, key := range best.used() {
+			delete(allowed, key)
+			candidate := solver.solve(allowed)
+			if candidate.better(best) {
+				best = candidate
+				improved = true
+				break
+			}
+			allowed[key] = true
+		}
+		if !improved {
+			break
+		}
+		allowed = map[cartOrderKey]bool{}
+		for _, key := range best.used() {
+			allowed[key] = true
+		}
+	}
+
+	return solver.plan(best), nil
+}
+
+type cartOrderKey struct {
+	sellerName    string
+	subSellerName string
+	bundle        bool
+}
+
+type cartListing struct {
+	cardID string
+	entry  InventoryEntry
+	order  cartOrderKey
+}
+
+type cartSolver struct {
+	opts     *CartOpts
+	wishlist []WishlistItem
+	orders   map[cartOrderKey]bool
+
+	// All the listings, and the ones usable by each wishlist item sorted by
+	// price, as indexes of the former
+	listings   []cartListing
+	candidates [][]int
+}
+
+type cartSolution struct {
+	// Units bought of each listing, and missing of each wishlist item
+	bought       map[int]int
+	missing      []int
+	missingUnits int
+	total        float64
+
+	subtotals map[cartOrderKey]float64
+	shipping  map[cartOrderKey]float64
+}
+
+func (solver *cartSolver) collect(inventory InventoryRecord) {
+	seen := map[string]bool{}
+	for _, item := range solver.wishlist {
+		if seen[item.CardID] {
+			continue
+		}
+		seen[item.CardID] = true
+
+		for _, entry := range inventory[item.CardID] {
+			if entry.Price <= 0 || entry.Quantity <= 0 {
+				continue
+			}
+			if len(solver.opts.Sellers) > 0 && !slices.Contains(solver.opts.Sellers, entry.SellerName) {
+				continue
+			}
+			key := cartOrderKey{
+				sellerName: entry.SellerName,
+				bundle:     entry.Bundle,
+			}
+			if !entry.Bundle {
+				key.subSellerName = entry.CustomFields["SubSellerName"]
+			}
+			solver.orders[key] = true
+			solver.listings = append(solver.listings, cartListing{
+				cardID: item.CardID,
+				entry:  entry,
+				order:  key,
+			})
+		}
+	}
+
+	solver.candidates = make([][]int, len(solver.wishlist))
+	for i, item := range solver.wishlist {
+		worst := len(FullGradeTags)
+		if item.Conditions != "" {
+			worst = slices.Index(FullGradeTags, item.Conditions)
+		}
+		for j, listing := range solver.listings {
+			if listing.cardID != item.CardID {
+				continue
+			}
+			grade := slices.Index(FullGradeTags, listing.entry.Conditions)
+			if grade < 0 || grade > worst {
+				continue
+			}
+			solver.candidates[i] = append(solver.candidates[i], j)
+		}
+		sort.SliceStable(solver.candidates[i], func(a, b int) bool {
+			return solver.listings[solver.candidates[i][a]].entry.Price < solver.listings[solver.candidates[i][b]].entry.Price
+		})
+	}
+}
+
+// solve fills the wishlist from the cheapest listings of the allowed orders.
+func (solver *cartSolver) solve(allowed map[cartOrderKey]bool) *cartSolution {
+	solution := cartSolution{
+		bought:    map[int]int{},
+		missing:   make([]int, len(solver.wishlist)),
+		subtotals: map[cartOrderKey]float64{},
+		shipping:  map[cartOrderKey]float64{},
+	}
+
+	for i, item := range solver.wishlist {
+		needed := item.Quantity
+		for _, j := range solver.candidates[i] {
+			if needed <= 0 {
+				break
+			}
+			listing := solver.listings[j]
+			if !allowed[listing.order] {
+				continue
+			}
+			qty := min(needed, listing.entry.Quantity-solution.bought[j])
+			if qty <= 0 {
+				continue
+			}
+			solution.bought[j] += qty
+			solution.subtotals[listing.order] += listing.entry.Price * float64(qty)
+			needed -= qty
+		}
+		solution.missing[i] = max(needed, 0)
+		solution.missingUnits += solution.missing[i]
+	}
+
+	for key, subtotal := range solution.subtotals {
+		shipping := solver.opts.shippingFor(key, subtotal)
+		solution.shipping[key] = shipping
+		solution.total += subtotal + shipping
+	}
+
+	return &solution
+}
+
+func (o *CartOpts) shippingFor(key cartOrderKey, subtotal float64) float64 {
+	if key.bundle {
+		if o.BundleFreeShippingOver > 0 && subtotal >= o.BundleFreeShippingOver {
+			return 0
+		}
+		return o.BundleShipping
+	}
+	shipping, found := o.Shipping[key.subSellerName]
+	if found && key.subSellerName != "" {
+		return shipping
+	}
+	shipping, found = o.Shipping[key.sellerName]
+	if found {
+		return shipping
+	}
+	return o.DefaultShipping
+}
+
+// used returns the orders of the solution, sorted from the most expensive to
+// ship per unit of subtotal, the first worth trying to drop.
+func (solution *cartSolution) used() []cartOrderKey {
+	keys := make([]cartOrderKey, 0, len(solution.subtotals))
+	for key := range solution.subtotals {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ri := solution.shipping[keys[i]] / solution.subtotals[keys[i]]
+		rj := solution.shipping[keys[j]] / solution.subtotals[keys[j]]
+		if ri != rj {
+			return ri > rj
+		}
+		return keys[i].less(keys[j])
+	})
+	return keys
+}
+
+func (key cartOrderKey) less(other cartOrderKey) bool {
+	if key.sellerName != other.sellerName {
+		return key.sellerName < other.sellerName
+	}
+	if key.subSellerName != other.subSellerName {
+		return key.subSellerName < other.subSellerName
+	}
+	return !key.bundle && other.bundle
+}
+
+func (solution *cartSolution) better(other *cartSolution) bool {
+	if solution.missingUnits != other.missingUnits {
+		return solution.missingUnits < other.missingUnits
+	}
+	return solution.total < other.total-1e-9
+}
+
+func (solver *cartSolver) plan(solution *cartSolution) *CartPlan {
+	var plan CartPlan
+
+	orders := map[cartOrderKey]*CartOrder{}
+	for j, qty := range solution.bought {
+		listing := solver.listings[j]
+		order, found := orders[listing.order]
+		if !found {
+			order = &CartOrder{
+				SellerName:    listing.order.sellerName,
+				SubSellerName: listing.order.subSellerName,
+				Bundle:        listing.order.bundle,
+				Subtotal:      solution.subtotals[listing.order],
+				Shipping:      solution.shipping[listing.order],
+			}
+			orders[listing.order] = order
+		}
+		entry := listing.entry
+		entry.Quantity = qty
+		order.Entries = append(order.Entries, CartEntry{
+			CardID: listing.cardID,
+			Entry:  entry,
+		})
+	}
+
+	keys := make([]cartOrderKey, 0, len(orders))
+	for key := range orders {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].less(keys[j])
+	})
+	for _, key := range keys {
+		order := orders[key]
+		sort.Slice(order.Entries, func(i, j int) bool {
+			if order.Entries[i].CardID != order.Entries[j].CardID {
+				return order.Entries[i].CardID < order.Entries[j].CardID
+			}
+			return order.Entries[i].Entry.Price < order.Entries[j].Entry.Price
+		})
+		plan.Orders = append(plan.Orders, *order)
+		plan.Subtotal += order.Subtotal
+		plan.Shipping += order.Shipping
+	}
+	plan.Total = plan.Subtotal + plan.Shipping
+
+	for i, item := range solver.wishlist {
+		if solution.missing[i] > 0 {
+			item.Quantity = solution.missing[i]
+			plan.Missing = append(plan.Missing, item)
+		}
+	}
+
+	return &plan
+}
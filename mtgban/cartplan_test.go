@@ -0,0 +1,122 @@
+package mtgban
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestOptimizeCartConsolidates(t *testing.T) {
+	inventory := InventoryRecord{
+		"A": {
+			{Conditions: "NM", Price: 1.0, Quantity: 1, SellerName: "X"},
+			{Conditions: "NM", Price: 1.2, Quantity: 1, SellerName: "Y"},
+		},
+		"B": {
+			{Conditions: "NM", Price: 2.0, Quantity: 2, SellerName: "Y"},
+		},
+	}
+	wishlist := []WishlistItem{
+		{CardID: "A", Quantity: 1},
+		{CardID: "B", Quantity: 1},
+	}
+
+	plan, err := OptimizeInventoryCart(&CartOpts{DefaultShipping: 1}, wishlist, inventory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Buying A from X saves 0.2 but costs a whole extra shipping
+	if len(plan.Orders) != 1 || plan.Orders[0].SellerName != "Y" {
+		t.Fatalf("expected a single order from Y, got %+v", plan.Orders)
+	}
+	if math.Abs(plan.Total-4.2) > 1e-9 {
+		t.Errorf("expected a total of 4.2, got %f", plan.Total)
+	}
+	if len(plan.Missing) != 0 {
+		t.Errorf("unexpected missing items %+v", plan.Missing)
+	}
+}
+
+func TestOptimizeCartBundle(t *testing.T) {
+	inventory := InventoryRecord{
+		"A": {
+			{Conditions: "NM", Price: 30, Quantity: 1, SellerName: "Direct", Bundle: true},
+			{Conditions: "NM", Price: 29, Quantity: 1, SellerName: "Market"},
+		},
+		"B": {
+			{Conditions: "NM", Price: 25, Quantity: 1, SellerName: "Direct", Bundle: true},
+		},
+	}
+	wishlist := []WishlistItem{
+		{CardID: "A", Quantity: 1},
+		{CardID: "B", Quantity: 1},
+	}
+	opts := &CartOpts{
+		DefaultShipping:        1,
+		BundleShipping:         4,
+		BundleFreeShippingOver: 50,
+	}
+
+	plan, err := OptimizeInventoryCart(opts, wishlist, inventory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Moving A to the bundle pushes it past the free shipping threshold
+	if len(plan.Orders) != 1 || !plan.Orders[0].Bundle {
+		t.Fatalf("expected a single bundle order, got %+v", plan.Orders)
+	}
+	if plan.Shipping != 0 || plan.Total != 55 {
+		t.Errorf("expected 55 with free shipping, got %f + %f", plan.Subtotal, plan.Shipping)
+	}
+}
+
+func TestOptimizeCartConditionsAndMissing(t *testing.T) {
+	inventory := InventoryRecord{
+		"A": {
+			{Conditions: "NM", Price: 5, Quantity: 1, SellerName: "X"},
+			{Conditions: "HP", Price: 1, Quantity: 5, SellerName: "X"},
+			{Conditions: "SP", Price: 3, Quantity: 1, SellerName: "X"},
+		},
+	}
+	wishlist := []WishlistItem{
+		{CardID: "A", Conditions: "SP", Quantity: 3},
+	}
+
+	plan, err := OptimizeInventoryCart(nil, wishlist, inventory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plan.Subtotal != 8 {
+		t.Errorf("expected the NM and SP copies only, got subtotal %f", plan.Subtotal)
+	}
+	if len(plan.Missing) != 1 || plan.Missing[0].Quantity != 1 {
+		t.Errorf("expected one copy missing, got %+v", plan.Missing)
+	}
+
+	carter := &refusingCarter{}
+	err = AddPlanToCart(context.Background(), carter, plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(carter.added) != 2 {
+		t.Errorf("expected 2 entries in the cart, got %d", len(carter.added))
+	}
+}
+
+func TestOptimizeCartInvalidCondition(t *testing.T) {
+	inventory := InventoryRecord{
+		"A": {{Conditions: "NM", Price: 5, Quantity: 1, SellerName: "X"}},
+	}
+	wishlist := []WishlistItem{
+		{CardID: "A", Conditions: "EX", Quantity: 1},
+	}
+
+	_, err := OptimizeInventoryCart(nil, wishlist, inventory)
+	if !errors.Is(err, ErrInvalidCondition) {
+		t.Errorf("expected ErrInvalidCondition, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package mtgban
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Checkpoint keeps the work items a CheckpointedWorkerPool completed along
+// with the results they produced, so that a load dying midway resumes from
+// where it stopped instead of from zero. It is an append-only file of JSON
+// lines, one per completed item; a line torn by a crash is dropped on open.
+//
+// A nil Checkpoint is valid and keeps nothing.
+type Checkpoint struct {
+	mu   sync.Mutex
+	file *os.File
+	done map[string]json.RawMessage
+}
+
+type checkpointLine struct {
+	Item    json.RawMessage `json:"item"`
+	Results json.RawMessage `json:"results"`
+}
+
+// OpenCheckpoint opens the checkpoint called name in dir, creating both if
+// needed. An empty dir disables checkpointing, returning a nil Checkpoint.
+func OpenCheckpoint(dir, name string) (*Checkpoint, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, name+".ndjson")
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	// Only whole lines made it to disk
+	data = data[:bytes.LastIndexByte(data, '\n')+1]
+
+	cp := Checkpoint{
+		done: map[string]json.RawMessage{},
+	}
+	for i, line := range bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry checkpointLine
+		err := json.Unmarshal(line, &entry)
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint %s line %d: %w", path, i+1, err)
+		}
+		cp.done[string(entry.Item)] = entry.Results
+	}
+
+	cp.file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	// Drop the torn line, if any, so that new lines start clean
+	err = cp.file.Truncate(int64(len(data)))
+	if err == nil {
+		_, err = cp.file.Seek(0, io.SeekEnd)
+	}
+	if err != nil {
+		cp.file.Close()
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// Len returns the number of completed items the checkpoint holds.
+func (cp *Checkpoint) Len() int {
+	if cp == nil {
+		return 0
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return len(cp.done)
+}
+
+// Close closes the checkpoint file, leaving it in place for the next run.
+func (cp *Checkpoint) Close() error {
+	if cp == nil {
+		return nil
+	}
+	return cp.file.Close()
+}
+
+func (cp *Checkpoint) lookup(key []byte) (json.RawMessage, bool) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	results, found := cp.done[string(key)]
+	return results, found
+}
+
+func (cp *Checkpoint) save(key []byte, results any) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(&checkpointLine{Item: key, Results: data})
+	if err != nil {
+		return err
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	_, err = cp.file.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+	cp.done[string(key)] = data
+	return nil
+}
+
+// CheckpointedWorkerPool is WorkerPool resuming from cp. The items cp holds
+// as completed are not worked again: their saved results are consumed first,
+// in item order. Every other item whose worker returns no error before ctx is
+// cancelled is saved to cp with the results it pushed. Items and results are
+// saved as JSON, so an item is known by its encoding and only the exported
+// fields of a result survive. A nil cp runs a plain WorkerPool.
+func CheckpointedWorkerPool[T any, R any](
+	ctx context.Context,
+	cp *Checkpoint,
+	concurrency int,
+	items []T,
+	worker func(context.Context, T, chan<- R) error,
+	consume func(R),
+	logErr func(string, ...any),
+) {
+	if cp == nil {
+		WorkerPool(ctx, concurrency, items, worker, consume, logErr)
+		return
+	}
+	logf := func(format string, a ...any) {
+		if logErr != nil {
+			logErr(format, a...)
+		}
+	}
+
+	var pending []T
+	for _, item := range items {
+		key, err := json.Marshal(item)
+		if err != nil {
+			logf("checkpoint: %v", err)
+			pending = append(pending, item)
+			continue
+		}
+		saved, found := cp.lookup(key)
+		if !found {
+			pending = append(pending, item)
+			continue
+		}
+		var results []R
+		err = json.Unmarshal(saved, &results)
+		if err != nil {
+			logf("checkpoint: %s: %v", key, err)
+			pending = append(pending, item)
+			continue
+		}
+		for _, result := range results {
+			consume(result)
+		}
+	}
+
+	WorkerPool(ctx, concurrency, pending,
+		func(ctx context.Context, item T, results chan<- R) error {
+			// Run the worker on a channel of its own, to know which results
+			// belong to this item
+			local := make(chan R)
+			done := make(chan error, 1)
+			go func() {
+				done <- worker(ctx, item, local)
+				close(local)
+			}()
+
+			var produced []R
+			for result := range local {
+				produced = append(produced, result)
+				results <- result
+			}
+			err := <-done
+			if err != nil || ctx.Err() != nil {
+				return err
+			}
+
+			key, err := json.Marshal(item)
+			if err == nil {
+				err = cp.save(key, produced)
+			}
+			if err != nil {
+				return fmt.Errorf("checkpoint: %w", err)
+			}
+			return nil
+		},
+		consume,
+		logErr,
+	)
+}
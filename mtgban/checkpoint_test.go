@@ -0,0 +1,147 @@
+package mtgban
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+type checkpointResult struct {
+	Page  int
+	Value string
+}
+
+// runCheckpointed loads pages through a checkpoint in dir, failing the pages
+// in fail, and returns the pages worked and the values consumed.
+func runCheckpointed(t *testing.T, dir string, pages []int, fail map[int]bool) ([]int, []string) {
+	t.Helper()
+
+	cp, err := OpenCheckpoint(dir, "pages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Close()
+
+	var mu sync.Mutex
+	var worked []int
+	var values []string
+	CheckpointedWorkerPool(context.Background(), cp, 3, pages,
+		func(ctx context.Context, page int, results chan<- checkpointResult) error {
+			mu.Lock()
+			worked = append(worked, page)
+			mu.Unlock()
+
+			results <- checkpointResult{Page: page, Value: "a"}
+			if fail[page] {
+				return errors.New("page failed")
+			}
+			results <- checkpointResult{Page: page, Value: "b"}
+			return nil
+		},
+		func(result checkpointResult) {
+			values = append(values, result.Value)
+		},
+		nil,
+	)
+
+	slices.Sort(worked)
+	slices.Sort(values)
+	return worked, values
+}
+
+func TestCheckpointedWorkerPool(t *testing.T) {
+	dir := t.TempDir()
+	pages := []int{1, 2, 3, 4}
+
+	worked, values := runCheckpointed(t, dir, pages, map[int]bool{2: true, 4: true})
+	if !slices.Equal(worked, pages) || len(values) != 6 {
+		t.Fatalf("first run worked %v and consumed %v", worked, values)
+	}
+
+	// Only the failed pages are worked again, the others come from the
+	// checkpoint with everything they produced
+	worked, values = runCheckpointed(t, dir, pages, nil)
+	if !slices.Equal(worked, []int{2, 4}) {
+		t.Errorf("second run worked %v", worked)
+	}
+	if !slices.Equal(values, []string{"a", "a", "a", "a", "b", "b", "b", "b"}) {
+		t.Errorf("second run consumed %v", values)
+	}
+
+	// A line torn by a crash is dropped, and its page worked again
+	path := filepath.Join(dir, "pages.ndjson")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, data[:len(data)-5])
+	cp, err := OpenCheckpoint(dir, "pages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Len() != 3 {
+		t.Errorf("expected 3 pages after a torn line, got %d", cp.Len())
+	}
+	cp.Close()
+
+	worked, _ = runCheckpointed(t, dir, pages, nil)
+	if len(worked) != 1 {
+		t.Errorf("third run worked %v", worked)
+	}
+	worked, values = runCheckpointed(t, dir, pages, nil)
+	if len(worked) != 0 || len(values) != 8 {
+		t.Errorf("fourth run worked %v and consumed %v", worked, values)
+	}
+}
+
+func TestCheckpointDisabled(t *testing.T) {
+	cp, err := OpenCheckpoint("", "pages")
+	if err != nil || cp != nil {
+		t.Fatalf("expected no checkpoint, got %v %v", cp, err)
+	}
+	if cp.Len() != 0 || cp.Close() != nil {
+		t.Error("a nil checkpoint is not empty")
+	}
+
+	var got []int
+	CheckpointedWorkerPool(context.Background(), cp, 2, []int{1, 2},
+		func(ctx context.Context, item int, results chan<- int) error {
+			results <- item
+			return nil
+		},
+		func(result int) {
+			got = append(got, result)
+		},
+		nil,
+	)
+	if len(got) != 2 {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestCheckpointCancelled(t *testing.T) {
+	dir := t.TempDir()
+	cp, err := OpenCheckpoint(dir, "pages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Close()
+
+	// Work cut short by a cancellation is not complete
+	ctx, cancel := context.WithCancel(context.Background())
+	CheckpointedWorkerPool(ctx, cp, 1, []int{1},
+		func(ctx context.Context, item int, results chan<- int) error {
+			cancel()
+			return nil
+		},
+		func(int) {},
+		nil,
+	)
+	if cp.Len() != 0 {
+		t.Errorf("a cancelled item was saved")
+	}
+}
@@ -0,0 +1,584 @@
+package mtgban
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The columnar format stores a record one field at a time rather than one
+// entry at a time, so that what repeats across entries is written once.
+//
+// A file starts with columnarMagic, a byte telling an inventory from a
+// buylist, and the info as length-prefixed JSON. Then come the columns, each
+// one length-prefixed, made of a dictionary of strings followed by the values
+// of every entry, in the order of the entries. Strings are stored as indexes
+// in the dictionary of their column, prices as cents whenever that is exact,
+// and URLs as a template, the URL with its digits taken out, plus the digits.
+// The first column lists the card ids in its dictionary, and the number of
+// entries of each card as values.
+const columnarMagic = "BANCOL\x01"
+
+const (
+	columnarInventory byte = 'I'
+	columnarBuylist   byte = 'B'
+)
+
+var columnarKinds = map[byte]string{
+	columnarInventory: "inventory",
+	columnarBuylist:   "buylist",
+}
+
+// urlDigits is the placeholder of a run of digits in a URL template.
+const urlDigits = "\x00"
+
+// WriteSellerToColumnar writes the inventory of a seller and its info in the
+// columnar format, cards sorted by id and entries in record order.
+func WriteSellerToColumnar(seller Seller, w io.Writer) error {
+	inventory := seller.Inventory()
+	info := seller.Info()
+	info.BuylistTimestamp = nil
+
+	var cards, conditions, prices, quantities, urls, sellers, bundles,
+		originalIDs, instanceIDs, customFields, extraValues, currencies,
+		nativePrices colBuffer
+
+	for _, cardID := range sortedKeys(inventory) {
+		cards.words = append(cards.words, cardID)
+		cards.uvarint(uint64(len(inventory[cardID])))
+
+		for _, entry := range inventory[cardID] {
+			conditions.str(entry.Conditions)
+			prices.price(entry.Price)
+			quantities.varint(int64(entry.Quantity))
+			urls.url(entry.URL)
+			sellers.str(entry.SellerName)
+			bundles.flag(entry.Bundle)
+			originalIDs.str(entry.OriginalID)
+			instanceIDs.str(entry.InstanceID)
+			customFields.strMap(entry.CustomFields)
+			extraValues.floatMap(entry.ExtraValues)
+			currencies.str(entry.Currency)
+			nativePrices.price(entry.NativePrice)
+		}
+	}
+
+	return writeColumnar(w, columnarInventory, info, []*colBuffer{
+		&cards, &conditions, &prices, &quantities, &urls, &sellers, &bundles,
+		&originalIDs, &instanceIDs, &customFields, &extraValues, &currencies,
+		&nativePrices,
+	})
+}
+
+// WriteVendorToColumnar writes the buylist of a vendor and its info in the
+// columnar format, like WriteSellerToColumnar does for inventories.
+func WriteVendorToColumnar(vendor Vendor, w io.Writer) error {
+	buylist := vendor.Buylist()
+	info := vendor.Info()
+	info.InventoryTimestamp = nil
+
+	var cards, conditions, prices, ratios, quantities, urls, vendors,
+		originalIDs, instanceIDs, customFields, currencies, nativePrices colBuffer
+
+	for _, cardID := range sortedKeys(buylist) {
+		cards.words = append(cards.words, cardID)
+		cards.uvarint(uint64(len(buylist[cardID])))
+
+		for _, entry := range buylist[cardID] {
+			conditions.str(entry.Conditions)
+			prices.price(entry.BuyPrice)
+			ratios.price(entry.PriceRatio)
+			quantities.varint(int64(entry.Quantity))
+			urls.url(entry.URL)
+			vendors.str(entry.VendorName)
+			originalIDs.str(entry.OriginalID)
+			instanceIDs.str(entry.InstanceID)
+			customFields.strMap(entry.CustomFields)
+			currencies.str(entry.Currency)
+			nativePrices.price(entry.NativePrice)
+		}
+	}
+
+	return writeColumnar(w, columnarBuylist, info, []*colBuffer{
+		&cards, &conditions, &prices, &ratios, &quantities, &urls, &vendors,
+		&originalIDs, &instanceIDs, &customFields, &currencies, &nativePrices,
+	})
+}
+
+// ReadSellerFromColumnar rebuilds a seller from what WriteSellerToColumnar
+// emits, with the same caveat as ReadSellerFromJSON: prices only, no scraper
+// behind them.
+func ReadSellerFromColumnar(r io.Reader) (Seller, error) {
+	info, cols, err := readColumnar(r, columnarInventory, 13)
+	if err != nil {
+		return nil, err
+	}
+	cards, conditions, prices, quantities, urls, sellers, bundles,
+		originalIDs, instanceIDs, customFields, extraValues, currencies,
+		nativePrices := cols[0], cols[1], cols[2], cols[3], cols[4], cols[5], cols[6],
+		cols[7], cols[8], cols[9], cols[10], cols[11], cols[12]
+
+	inventory := make(InventoryRecord, len(cards.words))
+	for _, cardID := range cards.words {
+		n := cards.uvarint()
+		entries := make([]InventoryEntry, 0, min(n, 64))
+		for range n {
+			if conditions.err != nil {
+				break
+			}
+			entries = append(entries, InventoryEntry{
+				Conditions:   conditions.str(),
+				Price:        prices.price(),
+				Quantity:     int(quantities.varint()),
+				URL:          urls.url(),
+				SellerName:   sellers.str(),
+				Bundle:       bundles.flag(),
+				OriginalID:   originalIDs.str(),
+				InstanceID:   instanceIDs.str(),
+				CustomFields: customFields.strMap(),
+				ExtraValues:  extraValues.floatMap(),
+				Currency:     currencies.str(),
+				NativePrice:  nativePrices.price(),
+			})
+		}
+		inventory[cardID] = entries
+	}
+
+	err = columnsErr(cols)
+	if err != nil {
+		return nil, err
+	}
+	return NewSellerFromInventory(inventory, info), nil
+}
+
+// ReadVendorFromColumnar rebuilds a vendor from what WriteVendorToColumnar
+// emits.
+func ReadVendorFromColumnar(r io.Reader) (Vendor, error) {
+	info, cols, err := readColumnar(r, columnarBuylist, 12)
+	if err != nil {
+		return nil, err
+	}
+	cards, conditions, prices, ratios, quantities, urls, vendors,
+		originalIDs, instanceIDs, customFields, currencies, nativePrices := cols[0], cols[1], cols[2], cols[3], cols[4], cols[5],
+		cols[6], cols[7], cols[8], cols[9], cols[10], cols[11]
+
+	buylist := make(BuylistRecord, len(cards.words))
+	for _, cardID := range cards.words {
+		n := cards.uvarint()
+		entries := make([]BuylistEntry, 0, min(n, 64))
+		for range n {
+			if conditions.err != nil {
+				break
+			}
+			entries = append(entries, BuylistEntry{
+				Conditions:   conditions.str(),
+				BuyPrice:     prices.price(),
+				PriceRatio:   ratios.price(),
+				Quantity:     int(quantities.varint()),
+				URL:          urls.url(),
+				VendorName:   vendors.str(),
+				OriginalID:   originalIDs.str(),
+				InstanceID:   instanceIDs.str(),
+				CustomFields: customFields.strMap(),
+				Currency:     currencies.str(),
+				NativePrice:  nativePrices.price(),
+			})
+		}
+		buylist[cardID] = entries
+	}
+
+	err = columnsErr(cols)
+	if err != nil {
+		return nil, err
+	}
+	return NewVendorFromBuylist(buylist, info), nil
+}
+
+func writeColumnar(w io.Writer, kind byte, info ScraperInfo, cols []*colBuffer) error {
+	infoData, err := json.Marshal(&info)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	header := append([]byte(columnarMagic), kind)
+	header = binary.AppendUvarint(header, uint64(len(infoData)))
+	header = append(header, infoData...)
+	header = binary.AppendUvarint(header, uint64(len(cols)))
+	bw.Write(header)
+
+	for _, col := range cols {
+		if col.err != nil {
+			return col.err
+		}
+		var dict []byte
+		dict = binary.AppendUvarint(dict, uint64(len(col.words)))
+		for _, word := range col.words {
+			dict = binary.AppendUvarint(dict, uint64(len(word)))
+			dict = append(dict, word...)
+		}
+
+		var size []byte
+		size = binary.AppendUvarint(size, uint64(len(dict)+len(col.data)))
+		bw.Write(size)
+		bw.Write(dict)
+		bw.Write(col.data)
+	}
+
+	return bw.Flush()
+}
+
+func readColumnar(r io.Reader, kind byte, count int) (ScraperInfo, []*colReader, error) {
+	var info ScraperInfo
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return info, nil, err
+	}
+	if !bytes.HasPrefix(data, []byte(columnarMagic)) {
+		return info, nil, errors.New("not a columnar file")
+	}
+	file := colReader{data: data[len(columnarMagic):]}
+
+	if file.byte() != kind {
+		return info, nil, fmt.Errorf("not a columnar %s file", columnarKinds[kind])
+	}
+	err = json.Unmarshal(file.bytes(file.uvarint()), &info)
+	if err != nil {
+		return info, nil, fmt.Errorf("error reading info: %v", err)
+	}
+	if n := file.uvarint(); file.err == nil && n != uint64(count) {
+		return info, nil, fmt.Errorf("expected %d columns, found %d", count, n)
+	}
+
+	cols := make([]*colReader, count)
+	for i := range cols {
+		col := colReader{data: file.bytes(file.uvarint())}
+		n := col.uvarint()
+		for j := uint64(0); j < n && col.err == nil; j++ {
+			col.words = append(col.words, string(col.bytes(col.uvarint())))
+		}
+		if col.err != nil {
+			return info, nil, fmt.Errorf("error reading column %d: %v", i, col.err)
+		}
+		cols[i] = &col
+	}
+	if file.err != nil {
+		return info, nil, file.err
+	}
+
+	return info, cols, nil
+}
+
+func columnsErr(cols []*colReader) error {
+	for i, col := range cols {
+		if col.err != nil {
+			return fmt.Errorf("error reading column %d: %v", i, col.err)
+		}
+		// The last byte of a column of flags may be partly used
+		if len(col.data) > 1 || (len(col.data) == 1 && col.bits == 0) {
+			return fmt.Errorf("error reading column %d: %d trailing bytes", i, len(col.data))
+		}
+	}
+	return nil
+}
+
+// colBuffer accumulates the dictionary and the values of a column.
+type colBuffer struct {
+	words []string
+	index map[string]uint64
+	data  []byte
+	bits  int
+	err   error
+
+	template []byte
+	runs     [][2]int
+}
+
+func (c *colBuffer) uvarint(v uint64) {
+	c.data = binary.AppendUvarint(c.data, v)
+}
+
+func (c *colBuffer) varint(v int64) {
+	c.data = binary.AppendVarint(c.data, v)
+}
+
+func (c *colBuffer) str(s string) {
+	idx, found := c.index[s]
+	if !found {
+		idx = c.add(s)
+	}
+	c.uvarint(idx)
+}
+
+// strBytes is str for a string being built, which is only copied the first
+// time it is seen.
+func (c *colBuffer) strBytes(b []byte) {
+	idx, found := c.index[string(b)]
+	if !found {
+		idx = c.add(string(b))
+	}
+	c.uvarint(idx)
+}
+
+func (c *colBuffer) add(s string) uint64 {
+	if c.index == nil {
+		c.index = map[string]uint64{}
+	}
+	idx := uint64(len(c.words))
+	c.index[s] = idx
+	c.words = append(c.words, s)
+	return idx
+}
+
+// price writes the zigzagged cents shifted left by one when they represent
+// the price exactly, or a set low bit followed by the bits of the float.
+func (c *colBuffer) price(p float64) {
+	cents := math.Round(p * 100)
+	if math.Abs(cents) < 1<<52 && float64(int64(cents))/100 == p {
+		v := int64(cents)
+		c.uvarint((uint64(v<<1) ^ uint64(v>>63)) << 1)
+		return
+	}
+	c.uvarint(1)
+	c.data = binary.LittleEndian.AppendUint64(c.data, math.Float64bits(p))
+}
+
+// flag packs booleans eight to a byte.
+func (c *colBuffer) flag(b bool) {
+	if c.bits%8 == 0 {
+		c.data = append(c.data, 0)
+	}
+	if b {
+		c.data[len(c.data)-1] |= 1 << (c.bits % 8)
+	}
+	c.bits++
+}
+
+// url writes the template of u, then the length of every run of digits
+// taken out of it, and the runs themselves as numbers of up to 18 digits.
+func (c *colBuffer) url(u string) {
+	if strings.Contains(u, urlDigits) {
+		c.err = fmt.Errorf("unsupported NUL byte in url %q", u)
+		return
+	}
+
+	// Both buffers are reused across calls, as this runs once per entry
+	c.template = c.template[:0]
+	c.runs = c.runs[:0]
+	for i := 0; i < len(u); {
+		j := i
+		for j < len(u) && u[j] >= '0' && u[j] <= '9' {
+			j++
+		}
+		if j > i {
+			c.template = append(c.template, urlDigits...)
+			c.runs = append(c.runs, [2]int{i, j})
+			i = j
+			continue
+		}
+		c.template = append(c.template, u[i])
+		i++
+	}
+
+	c.strBytes(c.template)
+	for _, bounds := range c.runs {
+		run := u[bounds[0]:bounds[1]]
+		c.uvarint(uint64(len(run)))
+		for len(run) > 0 {
+			chunk := run[:min(len(run), 18)]
+			v, _ := strconv.ParseUint(chunk, 10, 64)
+			c.uvarint(v)
+			run = run[len(chunk):]
+		}
+	}
+}
+
+func (c *colBuffer) strMap(m map[string]string) {
+	c.uvarint(uint64(len(m)))
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		c.str(key)
+		c.str(m[key])
+	}
+}
+
+func (c *colBuffer) floatMap(m map[string]float64) {
+	c.uvarint(uint64(len(m)))
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		c.str(key)
+		c.price(m[key])
+	}
+}
+
+// colReader decodes a column written by a colBuffer. Like a bufio.Scanner,
+// it keeps the first error and returns zero values from then on.
+type colReader struct {
+	words []string
+	data  []byte
+	bits  int
+	err   error
+}
+
+func (c *colReader) fail(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+	c.data = nil
+}
+
+func (c *colReader) byte() byte {
+	if len(c.data) == 0 {
+		c.fail(io.ErrUnexpectedEOF)
+		return 0
+	}
+	b := c.data[0]
+	c.data = c.data[1:]
+	return b
+}
+
+func (c *colReader) bytes(n uint64) []byte {
+	if uint64(len(c.data)) < n {
+		c.fail(io.ErrUnexpectedEOF)
+		return nil
+	}
+	b := c.data[:n]
+	c.data = c.data[n:]
+	return b
+}
+
+func (c *colReader) uvarint() uint64 {
+	v, n := binary.Uvarint(c.data)
+	if n <= 0 {
+		c.fail(io.ErrUnexpectedEOF)
+		return 0
+	}
+	c.data = c.data[n:]
+	return v
+}
+
+func (c *colReader) varint() int64 {
+	v, n := binary.Varint(c.data)
+	if n <= 0 {
+		c.fail(io.ErrUnexpectedEOF)
+		return 0
+	}
+	c.data = c.data[n:]
+	return v
+}
+
+func (c *colReader) str() string {
+	idx := c.uvarint()
+	if c.err != nil {
+		return ""
+	}
+	if idx >= uint64(len(c.words)) {
+		c.fail(fmt.Errorf("index %d out of a dictionary of %d", idx, len(c.words)))
+		return ""
+	}
+	return c.words[idx]
+}
+
+func (c *colReader) price() float64 {
+	v := c.uvarint()
+	if v&1 == 1 {
+		b := c.bytes(8)
+		if c.err != nil {
+			return 0
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+	v >>= 1
+	cents := int64(v>>1) ^ -int64(v&1)
+	return float64(cents) / 100
+}
+
+func (c *colReader) flag() bool {
+	if len(c.data) == 0 {
+		c.fail(io.ErrUnexpectedEOF)
+		return false
+	}
+	b := c.data[0]&(1<<c.bits) != 0
+	c.bits++
+	if c.bits == 8 {
+		c.bits = 0
+		c.data = c.data[1:]
+	}
+	return b
+}
+
+func (c *colReader) url() string {
+	template := c.str()
+	if !strings.Contains(template, urlDigits) {
+		return template
+	}
+
+	var u strings.Builder
+	for i, part := range strings.Split(template, urlDigits) {
+		if i > 0 {
+			n := int(c.uvarint())
+			for n > 0 && c.err == nil {
+				size := min(n, 18)
+				digits := strconv.FormatUint(c.uvarint(), 10)
+				if len(digits) > size {
+					c.fail(fmt.Errorf("run of %d digits holding %s", size, digits))
+					break
+				}
+				u.WriteString(strings.Repeat("0", size-len(digits)))
+				u.WriteString(digits)
+				n -= size
+			}
+		}
+		u.WriteString(part)
+	}
+	return u.String()
+}
+
+func (c *colReader) strMap() map[string]string {
+	n := c.uvarint()
+	if n == 0 {
+		return nil
+	}
+	m := map[string]string{}
+	for range n {
+		if c.err != nil {
+			break
+		}
+		key := c.str()
+		m[key] = c.str()
+	}
+	return m
+}
+
+func (c *colReader) floatMap() map[string]float64 {
+	n := c.uvarint()
+	if n == 0 {
+		return nil
+	}
+	m := map[string]float64{}
+	for range n {
+		if c.err != nil {
+			break
+		}
+		key := c.str()
+		m[key] = c.price()
+	}
+	return m
+}
@@ -0,0 +1,146 @@
+package mtgban
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestColumnarRoundTrip(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	inventory := InventoryRecord{
+		"x|A|SET|1": {
+			{Conditions: "NM", Price: 2.99, Quantity: 1, URL: "https://example.com/product/0012?page=3", SellerName: "S", Bundle: true},
+			{Conditions: "SP", Price: 1.0 / 3, Quantity: 3, URL: "https://example.com/product/1234567890123456789012345", OriginalID: "10", InstanceID: "20"},
+		},
+		"x|B|SET|2": {
+			{Conditions: "MP", Price: -5, Quantity: 1, CustomFields: map[string]string{"lang": "JP"}, ExtraValues: map[string]float64{"low": 4.5, "odd": math.Pi}, Currency: "EUR", NativePrice: 4.6},
+		},
+	}
+	buylist := BuylistRecord{
+		"x|A|SET|1": {{Conditions: "NM", BuyPrice: 1.25, Quantity: 4, PriceRatio: 50.5, URL: "https://example.com/buy?id=1", VendorName: "V"}},
+	}
+	info := ScraperInfo{Name: "Test", Shorthand: "TST", InventoryTimestamp: &now, BuylistTimestamp: &now}
+
+	var buf bytes.Buffer
+	err := WriteSellerToColumnar(NewSellerFromInventory(inventory, info), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	seller, err := ReadSellerFromColumnar(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(seller.Inventory(), inventory) {
+		t.Errorf("expected %v, got %v", inventory, seller.Inventory())
+	}
+	if seller.Info().Shorthand != "TST" || seller.Info().BuylistTimestamp != nil || !seller.Info().InventoryTimestamp.Equal(now) {
+		t.Errorf("unexpected info %+v", seller.Info())
+	}
+
+	_, err = ReadVendorFromColumnar(bytes.NewReader(data))
+	if err == nil {
+		t.Error("an inventory was read as a buylist")
+	}
+	_, err = ReadSellerFromColumnar(bytes.NewReader(data[:len(data)-3]))
+	if err == nil {
+		t.Error("a truncated file was read")
+	}
+
+	buf.Reset()
+	err = WriteVendorToColumnar(NewVendorFromBuylist(buylist, info), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vendor, err := ReadVendorFromColumnar(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(vendor.Buylist(), buylist) {
+		t.Errorf("expected %v, got %v", buylist, vendor.Buylist())
+	}
+}
+
+// benchmarkInventory resembles a marketplace dump: many sellers, a handful
+// of grades, and URLs differing only by their ids.
+func benchmarkInventory() InventoryRecord {
+	inventory := InventoryRecord{}
+	for i := 0; i < 20000; i++ {
+		cardID := fmt.Sprintf("%08x-0000-0000-0000-%012x", i, i)
+		for j := 0; j < 5; j++ {
+			inventory[cardID] = append(inventory[cardID], InventoryEntry{
+				Conditions: FullGradeTags[j%4],
+				Price:      float64(i%5000)/100 + float64(j),
+				Quantity:   1 + j,
+				URL:        fmt.Sprintf("https://www.tcgplayer.com/product/%d?Language=English&seller=%d", 100000+i, j),
+				SellerName: fmt.Sprintf("Seller %d", (i*7+j)%900),
+			})
+		}
+	}
+	return inventory
+}
+
+func benchmarkWrite(b *testing.B, write func(Seller, *bytes.Buffer) error) {
+	seller := NewSellerFromInventory(benchmarkInventory(), ScraperInfo{Shorthand: "TCG"})
+	var buf bytes.Buffer
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		buf.Reset()
+		err := write(seller, &buf)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(buf.Len()), "filebytes")
+}
+
+func benchmarkRead(b *testing.B, write func(Seller, *bytes.Buffer) error, read func(*bytes.Reader) (Seller, error)) {
+	seller := NewSellerFromInventory(benchmarkInventory(), ScraperInfo{Shorthand: "TCG"})
+	var buf bytes.Buffer
+	err := write(seller, &buf)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		_, err := read(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func writeInventoryJSON(seller Seller, buf *bytes.Buffer) error {
+	return WriteSellerToJSON(seller, buf)
+}
+
+func writeInventoryColumnar(seller Seller, buf *bytes.Buffer) error {
+	return WriteSellerToColumnar(seller, buf)
+}
+
+func BenchmarkWriteInventoryJSON(b *testing.B) {
+	benchmarkWrite(b, writeInventoryJSON)
+}
+
+func BenchmarkWriteInventoryColumnar(b *testing.B) {
+	benchmarkWrite(b, writeInventoryColumnar)
+}
+
+func BenchmarkReadInventoryJSON(b *testing.B) {
+	benchmarkRead(b, writeInventoryJSON, func(r *bytes.Reader) (Seller, error) {
+		return ReadSellerFromJSON(r)
+	})
+}
+
+func BenchmarkReadInventoryColumnar(b *testing.B) {
+	benchmarkRead(b, writeInventoryColumnar, func(r *bytes.Reader) (Seller, error) {
+		return ReadSellerFromColumnar(r)
+	})
+}
@@ -0,0 +1,291 @@
+package mtgban
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ConsensusMethod is how ConsensusIndex turns the prices of its sources into
+// one.
+type ConsensusMethod int
+
+const (
+	// ConsensusMedian takes the weighted median of the prices.
+	ConsensusMedian ConsensusMethod = iota
+
+	// ConsensusTrimmedMean takes the weighted mean of the prices left once
+	// TrimFraction of the total weight is cut from either end.
+	ConsensusTrimmedMean
+)
+
+// madScale turns a median absolute deviation into an estimate of the standard
+// deviation of normally distributed prices.
+const madScale = 1.4826
+
+// madFloor is the smallest deviation, as a fraction of the median, outliers
+// are measured in, so that sources agreeing on one price do not reject every
+// other price however close.
+const madFloor = 0.05
+
+// ConsensusOpts configures ConsensusIndex. The zero value takes the plain
+// median of the NM prices of every source, rejecting nothing.
+type ConsensusOpts struct {
+	// Name and Shorthand of the index, "Consensus" if empty
+	Name      string
+	Shorthand string
+
+	// How prices are aggregated
+	Method ConsensusMethod
+
+	// Weight of each source by Shorthand, 1 for sources not listed; a
+	// source weighing zero or less is left out
+	Weights map[string]float64
+
+	// Fraction of the total weight cut from either end by
+	// ConsensusTrimmedMean, capped below one half
+	TrimFraction float64
+
+	// Prices farther from the weighted median than this many scaled median
+	// absolute deviations, taken as at least 5% of the median, are rejected
+	// before aggregating, zero rejects none
+	OutlierThreshold float64
+
+	// Sources needed to price a card, counted after rejecting outliers; a
+	// card with fewer is left out of the index
+	MinSources int
+
+	// Grade read from each source, NM if empty
+	Conditions string
+}
+
+// consensusQuote is the price one source gives to a card.
+type consensusQuote struct {
+	source string
+	price  float64
+	weight float64
+}
+
+// ConsensusIndex builds a price index out of sellers and vendors, one
+// MetadataOnly entry per card in the grade of opts. Each source quotes a card
+// at its lowest ask, or highest bid for vendors, in that grade, and the quotes
+// are aggregated as opts says. Prices are compared as they are, so the
+// sources should all be in USD and on the same side of the book unless the
+// weights account for it. Finishes have uuids of their own and are priced
+// separately. A nil opts is the zero ConsensusOpts.
+//
+// The entries carry the number of sources kept in ExtraValues["sources"] and
+// their shorthands in CustomFields["sources"]. The info is dated by the
+// oldest source, and keeps the game and sealed mode of the first one.
+func ConsensusIndex(opts *ConsensusOpts, sellers []Seller, vendors []Vendor) Seller {
+	if opts == nil {
+		opts = &ConsensusOpts{}
+	}
+	conditions := opts.Conditions
+	if conditions == "" {
+		conditions = "NM"
+	}
+	weight := func(shorthand string) float64 {
+		w, found := opts.Weights[shorthand]
+		if !found {
+			return 1
+		}
+		return w
+	}
+
+	var infos []ScraperInfo
+	quotes := map[string][]consensusQuote{}
+	for _, seller := range sellers {
+		info := seller.Info()
+		w := weight(info.Shorthand)
+		if w <= 0 {
+			continue
+		}
+		infos = append(infos, info)
+		for cardID, entries := range seller.Inventory() {
+			price := bestQuote(entries, conditions, func(a, b float64) bool {
+				return a < b
+			})
+			if price > 0 {
+				quotes[cardID] = append(quotes[cardID], consensusQuote{info.Shorthand, price, w})
+			}
+		}
+	}
+	for _, vendor := range vendors {
+		info := vendor.Info()
+		w := weight(info.Shorthand)
+		if w <= 0 {
+			continue
+		}
+		infos = append(infos, info)
+		for cardID, entries := range vendor.Buylist() {
+			price := bestQuote(entries, conditions, func(a, b float64) bool {
+				return a > b
+			})
+			if price > 0 {
+				quotes[cardID] = append(quotes[cardID], consensusQuote{info.Shorthand, price, w})
+			}
+		}
+	}
+
+	inventory := InventoryRecord{}
+	for cardID, cardQuotes := range quotes {
+		sort.SliceStable(cardQuotes, func(i, j int) bool {
+			return cardQuotes[i].price < cardQuotes[j].price
+		})
+		if opts.OutlierThreshold > 0 {
+			cardQuotes = rejectOutliers(cardQuotes, opts.OutlierThreshold)
+		}
+		if len(cardQuotes) == 0 || len(cardQuotes) < opts.MinSources {
+			continue
+		}
+
+		var price float64
+		switch opts.Method {
+		case ConsensusTrimmedMean:
+			price = trimmedMean(cardQuotes, opts.TrimFraction)
+		default:
+			price = weightedMedian(cardQuotes)
+		}
+
+		var names []string
+		for _, quote := range cardQuotes {
+			names = append(names, quote.source)
+		}
+		slices.Sort(names)
+
+		inventory[cardID] = []InventoryEntry{{
+			Conditions: conditions,
+			Price:      math.Round(price*100) / 100,
+			Quantity:   1,
+			CustomFields: map[string]string{
+				"sources": strings.Join(names, ","),
+			},
+			ExtraValues: map[string]float64{
+				"sources": float64(len(cardQuotes)),
+			},
+		}}
+	}
+
+	return NewSellerFromInventory(inventory, consensusInfo(opts, infos))
+}
+
+// bestQuote returns the price of the entry in conditions that better prefers,
+// zero if there is none.
+func bestQuote[E GenericEntry](entries []E, conditions string, better func(a, b float64) bool) float64 {
+	var price float64
+	for _, entry := range entries {
+		if entry.Condition() != conditions || entry.Pricing() <= 0 {
+			continue
+		}
+		if price == 0 || better(entry.Pricing(), price) {
+			price = entry.Pricing()
+		}
+	}
+	return price
+}
+
+// weightedMedian returns the price splitting the total weight of quotes,
+// sorted by price, in two halves, averaging the two prices on either side of
+// an exact split.
+func weightedMedian(quotes []consensusQuote) float64 {
+	var total float64
+	for _, quote := range quotes {
+		total += quote.weight
+	}
+	half := total / 2
+
+	var cumulative float64
+	for i, quote := range quotes {
+		cumulative += quote.weight
+		if cumulative < half {
+			continue
+		}
+		if cumulative == half && i+1 < len(quotes) {
+			return (quote.price + quotes[i+1].price) / 2
+		}
+		return quote.price
+	}
+	return quotes[len(quotes)-1].price
+}
+
+// trimmedMean returns the weighted mean of quotes, sorted by price, once
+// fraction of the total weight is cut from either end. A quote straddling a
+// cut counts for the part of its weight that is left.
+func trimmedMean(quotes []consensusQuote, fraction float64) float64 {
+	fraction = min(max(fraction, 0), 0.49)
+
+	var total float64
+	for _, quote := range quotes {
+		total += quote.weight
+	}
+	lo := total * fraction
+	hi := total - lo
+
+	var sum, kept, cumulative float64
+	for _, quote := range quotes {
+		start := cumulative
+		cumulative += quote.weight
+		overlap := min(cumulative, hi) - max(start, lo)
+		if overlap <= 0 {
+			continue
+		}
+		sum += quote.price * overlap
+		kept += overlap
+	}
+	return sum / kept
+}
+
+// rejectOutliers drops the quotes, sorted by price, lying farther than
+// threshold scaled median absolute deviations from the weighted median. The
+// deviation is at least madFloor of the median, as it is zero when most
+// quotes agree on one price.
+func rejectOutliers(quotes []consensusQuote, threshold float64) []consensusQuote {
+	median := weightedMedian(quotes)
+
+	deviations := make([]consensusQuote, len(quotes))
+	for i, quote := range quotes {
+		deviations[i] = consensusQuote{price: math.Abs(quote.price - median), weight: quote.weight}
+	}
+	sort.SliceStable(deviations, func(i, j int) bool {
+		return deviations[i].price < deviations[j].price
+	})
+	mad := max(weightedMedian(deviations)*madScale, median*madFloor)
+
+	var kept []consensusQuote
+	for _, quote := range quotes {
+		if math.Abs(quote.price-median) <= threshold*mad {
+			kept = append(kept, quote)
+		}
+	}
+	return kept
+}
+
+// consensusInfo describes an index built out of the sources in infos.
+func consensusInfo(opts *ConsensusOpts, infos []ScraperInfo) ScraperInfo {
+	var info ScraperInfo
+	info.Name = opts.Name
+	if info.Name == "" {
+		info.Name = "Consensus"
+	}
+	info.Shorthand = opts.Shorthand
+	if info.Shorthand == "" {
+		info.Shorthand = info.Name
+	}
+	info.MetadataOnly = true
+	info.NoQuantityInventory = true
+
+	now := time.Now()
+	info.InventoryTimestamp = &now
+	for i, source := range infos {
+		if i == 0 {
+			info.Game = source.Game
+			info.SealedMode = source.SealedMode
+		}
+		info.InventoryTimestamp = oldestTimestamp(info.InventoryTimestamp, source.InventoryTimestamp)
+		info.InventoryTimestamp = oldestTimestamp(info.InventoryTimestamp, source.BuylistTimestamp)
+	}
+	return info
+}
@@ -0,0 +1,87 @@
+package mtgban
+
+import (
+	"testing"
+	"time"
+)
+
+func consensusSources() ([]Seller, []Vendor) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	sellers := []Seller{
+		NewSellerFromInventory(InventoryRecord{
+			"A": {{Conditions: "NM", Price: 10}, {Conditions: "SP", Price: 8}},
+			"B": {{Conditions: "NM", Price: 2}},
+		}, ScraperInfo{Shorthand: "S1", InventoryTimestamp: &newer}),
+		NewSellerFromInventory(InventoryRecord{
+			"A": {{Conditions: "NM", Price: 11, SellerName: "x"}, {Conditions: "NM", Price: 12, SellerName: "y"}},
+		}, ScraperInfo{Shorthand: "S2", InventoryTimestamp: &older}),
+		NewSellerFromInventory(InventoryRecord{
+			"A": {{Conditions: "NM", Price: 100}},
+		}, ScraperInfo{Shorthand: "S3", InventoryTimestamp: &newer}),
+	}
+	vendors := []Vendor{
+		NewVendorFromBuylist(BuylistRecord{
+			"A": {{Conditions: "NM", BuyPrice: 9}, {Conditions: "NM", BuyPrice: 7}},
+		}, ScraperInfo{Shorthand: "V1", BuylistTimestamp: &newer}),
+	}
+	return sellers, vendors
+}
+
+func TestConsensusIndex(t *testing.T) {
+	sellers, vendors := consensusSources()
+
+	// Quotes for A are 9, 10, 11 and 100, and B only has one
+	index := ConsensusIndex(nil, sellers, vendors)
+	inventory := index.Inventory()
+	if len(inventory) != 2 || inventory["A"][0].Price != 10.5 || inventory["B"][0].Price != 2 {
+		t.Errorf("unexpected median index %v", inventory)
+	}
+	if inventory["A"][0].CustomFields["sources"] != "S1,S2,S3,V1" || inventory["A"][0].ExtraValues["sources"] != 4 {
+		t.Errorf("unexpected sources %v", inventory["A"][0])
+	}
+
+	info := index.Info()
+	if info.Shorthand != "Consensus" || !info.MetadataOnly || !info.NoQuantityInventory ||
+		!info.InventoryTimestamp.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected info %+v", info)
+	}
+
+	tests := []struct {
+		name     string
+		opts     ConsensusOpts
+		expected float64
+	}{
+		{"weighted median", ConsensusOpts{Weights: map[string]float64{"S3": 4}}, 100},
+		{"excluded source", ConsensusOpts{Weights: map[string]float64{"S3": 0}}, 10},
+		{"mean", ConsensusOpts{Method: ConsensusTrimmedMean}, 32.5},
+		{"trimmed mean", ConsensusOpts{Method: ConsensusTrimmedMean, TrimFraction: 0.25}, 10.5},
+		{"partial trim", ConsensusOpts{Method: ConsensusTrimmedMean, TrimFraction: 0.125}, 25.17},
+		{"outliers", ConsensusOpts{Method: ConsensusTrimmedMean, OutlierThreshold: 3}, 10},
+		{"other grade", ConsensusOpts{Conditions: "SP"}, 8},
+	}
+	for _, test := range tests {
+		entries := ConsensusIndex(&test.opts, sellers, vendors).Inventory()["A"]
+		if len(entries) != 1 || entries[0].Price != test.expected {
+			t.Errorf("%s: expected %.2f, got %v", test.name, test.expected, entries)
+		}
+	}
+
+	inventory = ConsensusIndex(&ConsensusOpts{MinSources: 2}, sellers, vendors).Inventory()
+	if len(inventory) != 1 || inventory["A"] == nil {
+		t.Errorf("expected cards with too few sources to be dropped, got %v", inventory)
+	}
+	inventory = ConsensusIndex(&ConsensusOpts{OutlierThreshold: 3, MinSources: 4}, sellers, vendors).Inventory()
+	if len(inventory) != 0 {
+		t.Errorf("expected the sources to be counted after rejecting outliers, got %v", inventory)
+	}
+}
+
+func TestRejectOutliersAgreement(t *testing.T) {
+	// Most sources agree, leaving no deviation: a close price is still kept
+	quotes := []consensusQuote{{price: 10, weight: 1}, {price: 10, weight: 1}, {price: 10, weight: 1}, {price: 11, weight: 1}, {price: 30, weight: 1}}
+	kept := rejectOutliers(quotes, 3)
+	if len(kept) != 4 || kept[3].price != 11 {
+		t.Errorf("expected only the far price rejected, got %v", kept)
+	}
+}
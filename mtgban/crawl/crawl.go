@@ -0,0 +1,263 @@
+// Package crawl walks HTML storefronts on top of mtgban.WorkerPool, for the
+// scrapers that have no API to talk to.
+//
+// A crawl starts from a few seed pages and proceeds in waves: every page of a
+// wave is fetched and parsed as a goquery document, handed to a visit
+// function that pushes results and returns the links to follow, and the links
+// not seen yet make up the next wave. Pagination is either discovered that
+// way, one "next" link at a time, or listed upfront by the visit of the first
+// page when the store tells how many there are:
+//
+//	crawl.Run(ctx, cr, 8, []string{startURL},
+//		func(ctx context.Context, page *crawl.Page, results chan<- entry) ([]string, error) {
+//			page.Doc.Find("tr.product").Each(func(_ int, s *goquery.Selection) {
+//				results <- parseRow(s)
+//			})
+//			next, found := page.Doc.Find("a.next").Attr("href")
+//			if !found {
+//				return nil, nil
+//			}
+//			return []string{page.Abs(next)}, nil
+//		},
+//		consume,
+//		printf,
+//	)
+package crawl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/mtgban/go-mtgban/mtgban"
+)
+
+const (
+	defaultRetries   = 3
+	defaultRetryWait = 2 * time.Second
+)
+
+// Crawler fetches pages for Run, retrying the ones that fail.
+type Crawler struct {
+	// Client sending every request
+	Client *http.Client
+
+	// Attempts made after the first one failed
+	Retries int
+
+	// Pause before the first retry, growing linearly with each attempt
+	RetryWait time.Duration
+
+	// Upper bound of a random pause before each request, on top of what
+	// mtgban.DefaultHostThrottle asks
+	RandomDelay time.Duration
+}
+
+// NewCrawler returns a Crawler going through mtgban.NewHTTPClient, so that
+// crawls are recorded, replayed and throttled like any other client.
+func NewCrawler() *Crawler {
+	return &Crawler{
+		Client:    mtgban.NewHTTPClient(),
+		Retries:   defaultRetries,
+		RetryWait: defaultRetryWait,
+	}
+}
+
+// Page is a fetched page.
+type Page struct {
+	URL *url.URL
+	Doc *goquery.Document
+}
+
+// Abs resolves a link found in the page, returning it as is if it does not
+// parse.
+func (page *Page) Abs(link string) string {
+	u, err := page.URL.Parse(link)
+	if err != nil {
+		return link
+	}
+	return u.String()
+}
+
+// StatusError is returned for a page answered with a status other than 200.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (err *StatusError) Error() string {
+	return fmt.Sprintf("%s: unexpected status %d", err.URL, err.StatusCode)
+}
+
+// Fetch downloads and parses the page at link. Network errors, 429 and 5xx
+// responses other than 501 are retried, other statuses fail right away with a
+// StatusError.
+func (cr *Crawler) Fetch(ctx context.Context, link string) (*Page, error) {
+	var err error
+	for attempt := 0; attempt <= cr.Retries; attempt++ {
+		wait := cr.RetryWait * time.Duration(attempt)
+		if cr.RandomDelay > 0 {
+			wait += rand.N(cr.RandomDelay)
+		}
+		if wait > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		var page *Page
+		page, err = cr.fetch(ctx, link)
+		if err == nil {
+			return page, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && !retryStatus(statusErr.StatusCode) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// retryStatus tells whether a response with status code may go better on a
+// new attempt. A 501 says the server will never do it, and retryablehttp
+// gives up on it too.
+func retryStatus(code int) bool {
+	return code == http.StatusTooManyRequests ||
+		(code >= http.StatusInternalServerError && code != http.StatusNotImplemented)
+}
+
+func (cr *Crawler) fetch(ctx context.Context, link string) (*Page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cr.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{URL: link, StatusCode: resp.StatusCode}
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", link, err)
+	}
+	return &Page{URL: resp.Request.URL, Doc: doc}, nil
+}
+
+// crawlOutput carries either a result of visit or the links it returned.
+type crawlOutput[R any] struct {
+	result R
+	links  []string
+	isLink bool
+}
+
+// Run crawls from seeds with up to concurrency pages in flight. Every page is
+// fetched once with cr, and visit pushes its results, which are consumed on
+// the calling goroutine, and returns the absolute links to follow. Pages that
+// fail to load or to visit are reported to logErr and not followed. Once ctx
+// is cancelled, the pages in flight finish and no new one is fetched.
+func Run[R any](
+	ctx context.Context,
+	cr *Crawler,
+	concurrency int,
+	seeds []string,
+	visit func(context.Context, *Page, chan<- R) ([]string, error),
+	consume func(R),
+	logErr func(string, ...any),
+) {
+	seen := map[string]bool{}
+	var frontier []string
+	follow := func(links []string) {
+		for _, link := range links {
+			u, err := url.Parse(link)
+			if err == nil {
+				u.Fragment = ""
+				link = u.String()
+			}
+			if seen[link] {
+				continue
+			}
+			seen[link] = true
+			frontier = append(frontier, link)
+		}
+	}
+	follow(seeds)
+
+	for len(frontier) > 0 && ctx.Err() == nil {
+		wave := frontier
+		frontier = nil
+
+		mtgban.WorkerPool(ctx, concurrency, wave,
+			func(ctx context.Context, link string, out chan<- crawlOutput[R]) error {
+				page, err := cr.Fetch(ctx, link)
+				if err != nil {
+					return err
+				}
+
+				// Run visit on a channel of its own, to pass its results
+				// along with the links it returns
+				results := make(chan R)
+				var links []string
+				go func() {
+					links, err = visit(ctx, page, results)
+					close(results)
+				}()
+				for result := range results {
+					out <- crawlOutput[R]{result: result}
+				}
+				if err != nil {
+					return fmt.Errorf("%s: %w", link, err)
+				}
+				if len(links) > 0 {
+					out <- crawlOutput[R]{links: links, isLink: true}
+				}
+				return nil
+			},
+			func(output crawlOutput[R]) {
+				if output.isLink {
+					follow(output.links)
+					return
+				}
+				consume(output.result)
+			},
+			logErr,
+		)
+	}
+}
+
+// Text returns the trimmed text of the elements under s matching selector.
+func Text(s *goquery.Selection, selector string) string {
+	return strings.TrimSpace(s.Find(selector).Text())
+}
+
+// Texts returns the trimmed text of each element under s matching selector.
+func Texts(s *goquery.Selection, selector string) []string {
+	var texts []string
+	s.Find(selector).Each(func(_ int, el *goquery.Selection) {
+		texts = append(texts, strings.TrimSpace(el.Text()))
+	})
+	return texts
+}
+
+// Attr returns the trimmed attribute name of the first element under s
+// matching selector, or an empty string.
+func Attr(s *goquery.Selection, selector, name string) string {
+	value, _ := s.Find(selector).Attr(name)
+	return strings.TrimSpace(value)
+}
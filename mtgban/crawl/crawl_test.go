@@ -0,0 +1,134 @@
+package crawl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestRun(t *testing.T) {
+	var mu sync.Mutex
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		count := hits[r.URL.Path]
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<a href="/a">a</a> <a href="b">b</a> <a href="/missing">x</a>`)
+		case "/a":
+			fmt.Fprint(w, `<p>one</p><p>two</p> <a href="/b#top">b</a> <a href="/">home</a>`)
+		case "/b":
+			// Flaky at first
+			if count == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			fmt.Fprint(w, `<p>three</p> <a href="/a">a</a>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cr := NewCrawler()
+	cr.RetryWait = 0
+
+	var got, failures []string
+	Run(context.Background(), cr, 4, []string{server.URL + "/"},
+		func(ctx context.Context, page *Page, results chan<- string) ([]string, error) {
+			page.Doc.Find("p").Each(func(_ int, s *goquery.Selection) {
+				results <- s.Text()
+			})
+			var links []string
+			page.Doc.Find("a").Each(func(_ int, s *goquery.Selection) {
+				href, _ := s.Attr("href")
+				links = append(links, page.Abs(href))
+			})
+			return links, nil
+		},
+		func(result string) {
+			got = append(got, result)
+		},
+		func(format string, a ...any) {
+			failures = append(failures, fmt.Sprintf(format, a...))
+		},
+	)
+
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"one", "three", "two"}) {
+		t.Errorf("unexpected results %v", got)
+	}
+	for path, expected := range map[string]int{"/": 1, "/a": 1, "/b": 2, "/missing": 1} {
+		if hits[path] != expected {
+			t.Errorf("%s: expected %d requests, got %d", path, expected, hits[path])
+		}
+	}
+	if len(failures) != 1 || !strings.Contains(failures[0], "/missing: unexpected status 404") {
+		t.Errorf("unexpected failures %q", failures)
+	}
+}
+
+func TestFetchGivesUp(t *testing.T) {
+	var mu sync.Mutex
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits++
+		mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cr := NewCrawler()
+	cr.RetryWait = 0
+	cr.Retries = 2
+
+	_, err := cr.Fetch(context.Background(), server.URL)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected a 503 status error, got %v", err)
+	}
+	if hits != 3 {
+		t.Errorf("expected 3 attempts, got %d", hits)
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<a href="/%d">next</a>`, len(r.URL.Path))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	visited := 0
+	Run(ctx, NewCrawler(), 1, []string{server.URL + "/"},
+		func(ctx context.Context, page *Page, results chan<- int) ([]string, error) {
+			results <- 1
+			href, _ := page.Doc.Find("a").Attr("href")
+			return []string{page.Abs(href)}, nil
+		},
+		func(int) {
+			visited++
+			if visited == 2 {
+				cancel()
+			}
+		},
+		nil,
+	)
+	if visited != 2 {
+		t.Errorf("expected the crawl to stop after 2 pages, got %d", visited)
+	}
+}
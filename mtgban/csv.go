@@ -35,6 +35,9 @@ var (
 
 	// MismatchHeader is the header for the mismatch reports
 	MismatchHeader = append(CardHeader, "Conditions", "Price", "Reference", "Difference", "Spread")
+
+	// DiffHeader is the header for the snapshot diff reports
+	DiffHeader = append(CardHeader, "Conditions", "Name", "Old Price", "New Price", "Old Quantity", "New Quantity", "Difference", "Spread")
 )
 
 func record2entry(record []string) (*InventoryEntry, error) {
@@ -586,3 +589,41 @@ func WritePennyToCSV(penny []ArbitEntry, w io.Writer) error {
 
 	return csvWriter.Error()
 }
+
+// WriteDiffToCSV writes the entry changes of a SnapshotDiff, usually its
+// PriceMoves or QuantityChanges.
+func WriteDiffToCSV(changes []EntryChange, w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+	defer csvWriter.Flush()
+
+	err := csvWriter.Write(DiffHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		record, err := cardID2record(change.CardID)
+		if err != nil {
+			continue
+		}
+
+		record = append(record,
+			change.Conditions,
+			change.Name,
+			fmt.Sprintf("%0.2f", change.OldPrice),
+			fmt.Sprintf("%0.2f", change.NewPrice),
+			fmt.Sprint(change.OldQuantity),
+			fmt.Sprint(change.NewQuantity),
+			fmt.Sprintf("%0.2f", change.Difference),
+			fmt.Sprintf("%0.2f", change.Spread),
+		)
+		err = csvWriter.Write(record)
+		if err != nil {
+			return err
+		}
+
+		csvWriter.Flush()
+	}
+
+	return csvWriter.Error()
+}
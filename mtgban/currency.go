@@ -0,0 +1,78 @@
+package mtgban
+
+import (
+	"maps"
+	"strings"
+)
+
+// RepriceInventory returns a copy of inventory with every entry that carries
+// a native price converted again at rates, keyed like GetExchangeRates keys
+// them. Entries quoted in USD from the start, or in a currency rates does not
+// have, keep the price they were scraped at.
+func RepriceInventory(inventory InventoryRecord, rates map[string]float64) InventoryRecord {
+	result := make(InventoryRecord, len(inventory))
+	for cardID, entries := range inventory {
+		repriced := make([]InventoryEntry, len(entries))
+		for i, entry := range entries {
+			rate, found := rates[strings.ToLower(entry.Currency)]
+			if entry.Currency != "" && found {
+				entry.Price = entry.NativePrice * rate
+			}
+			repriced[i] = entry
+		}
+		result[cardID] = repriced
+	}
+	return result
+}
+
+// RepriceBuylist returns a copy of buylist with every entry that carries a
+// native price converted again at rates, with the same rules as
+// RepriceInventory.
+func RepriceBuylist(buylist BuylistRecord, rates map[string]float64) BuylistRecord {
+	result := make(BuylistRecord, len(buylist))
+	for cardID, entries := range buylist {
+		repriced := make([]BuylistEntry, len(entries))
+		for i, entry := range entries {
+			rate, found := rates[strings.ToLower(entry.Currency)]
+			if entry.Currency != "" && found {
+				entry.BuyPrice = entry.NativePrice * rate
+			}
+			repriced[i] = entry
+		}
+		result[cardID] = repriced
+	}
+	return result
+}
+
+// RepriceSeller returns a copy of seller with its inventory repriced at
+// rates, and the rates recorded in its info in place of the ones it was
+// scraped at.
+func RepriceSeller(seller Seller, rates map[string]float64) Seller {
+	info := seller.Info()
+	info.ExchangeRates = repriceRates(info.ExchangeRates, rates)
+	return NewSellerFromInventory(RepriceInventory(seller.Inventory(), rates), info)
+}
+
+// RepriceVendor returns a copy of vendor with its buylist repriced at rates,
+// and the rates recorded in its info in place of the ones it was scraped at.
+func RepriceVendor(vendor Vendor, rates map[string]float64) Vendor {
+	info := vendor.Info()
+	info.ExchangeRates = repriceRates(info.ExchangeRates, rates)
+	return NewVendorFromBuylist(RepriceBuylist(vendor.Buylist(), rates), info)
+}
+
+// repriceRates updates the currencies a scraper recorded with their new
+// rates, leaving the ones rates does not have as they were.
+func repriceRates(old, rates map[string]float64) map[string]float64 {
+	if old == nil {
+		return nil
+	}
+	result := maps.Clone(old)
+	for currency := range result {
+		rate, found := rates[currency]
+		if found {
+			result[currency] = rate
+		}
+	}
+	return result
+}
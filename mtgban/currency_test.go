@@ -0,0 +1,58 @@
+package mtgban
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRepriceInventory(t *testing.T) {
+	inventory := InventoryRecord{
+		"A": {
+			{Conditions: "NM", Price: 11, Quantity: 1, Currency: "EUR", NativePrice: 10},
+			{Conditions: "SP", Price: 5, Quantity: 1},
+			{Conditions: "MP", Price: 3, Quantity: 1, Currency: "JPY", NativePrice: 450},
+		},
+	}
+
+	repriced := RepriceInventory(inventory, map[string]float64{"eur": 1.2})
+
+	if math.Abs(repriced["A"][0].Price-12) > 1e-9 {
+		t.Errorf("EUR entry repriced to %f, expected 12", repriced["A"][0].Price)
+	}
+	if repriced["A"][1].Price != 5 {
+		t.Errorf("USD entry repriced to %f, expected it untouched", repriced["A"][1].Price)
+	}
+	if repriced["A"][2].Price != 3 {
+		t.Errorf("JPY entry repriced to %f without a rate", repriced["A"][2].Price)
+	}
+	if inventory["A"][0].Price != 11 {
+		t.Error("the original inventory was modified")
+	}
+}
+
+func TestRepriceVendor(t *testing.T) {
+	buylist := BuylistRecord{
+		"A": {
+			{Conditions: "NM", BuyPrice: 7.5, Quantity: 1, Currency: "CAD", NativePrice: 10},
+		},
+	}
+	info := ScraperInfo{
+		Shorthand:     "X",
+		Currency:      "CAD",
+		ExchangeRates: map[string]float64{"cad": 0.75},
+	}
+	vendor := NewVendorFromBuylist(buylist, info)
+
+	repriced := RepriceVendor(vendor, map[string]float64{"cad": 0.7, "eur": 1.1})
+
+	if math.Abs(repriced.Buylist()["A"][0].BuyPrice-7) > 1e-9 {
+		t.Errorf("CAD entry repriced to %f, expected 7", repriced.Buylist()["A"][0].BuyPrice)
+	}
+	rates := repriced.Info().ExchangeRates
+	if len(rates) != 1 || rates["cad"] != 0.7 {
+		t.Errorf("unexpected rates recorded: %v", rates)
+	}
+	if vendor.Info().ExchangeRates["cad"] != 0.75 {
+		t.Error("the original rates were modified")
+	}
+}
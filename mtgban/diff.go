@@ -0,0 +1,248 @@
+package mtgban
+
+import (
+	"math"
+	"slices"
+	"sort"
+)
+
+// DiffOpts sets what DiffInventory and DiffBuylist consider a significant
+// price move. Every field is a threshold whose zero value means "do not
+// filter", so the empty struct marks every move as significant.
+type DiffOpts struct {
+	// Minimum absolute difference between the old and new price
+	MinDiff float64
+
+	// Minimum spread % between the old and new price, in either direction
+	MinSpread float64
+
+	// Minimum old or new price, whichever is higher, to be considered
+	MinPrice float64
+}
+
+// EntryChange is one condition of one card from one seller or vendor whose
+// price or quantity differs between two snapshots. An entry that is only in
+// one of the two has a zero price and quantity on the other side.
+type EntryChange struct {
+	// ID of the card
+	CardID string
+
+	// The grade of the entries compared
+	Conditions string
+
+	// SellerName or VendorName of the entries compared
+	Name string
+
+	// Prices before and after
+	OldPrice float64
+	NewPrice float64
+
+	// Quantities before and after
+	OldQuantity int
+	NewQuantity int
+
+	// Difference of the prices, new minus old
+	Difference float64
+
+	// Spread % between the prices, relative to the old one
+	Spread float64
+
+	// Whether the move passes the thresholds set in DiffOpts
+	Significant bool
+}
+
+// SnapshotDiff is what changed between two snapshots of the same scraper.
+// Card ids and names are sorted, changes are sorted by card id, condition
+// and name.
+type SnapshotDiff struct {
+	// Cards only present in the new snapshot
+	Added []string
+
+	// Cards only present in the old snapshot
+	Removed []string
+
+	// Entries of cards present in both snapshots whose price changed
+	PriceMoves []EntryChange
+
+	// Entries of cards present in both snapshots whose quantity changed,
+	// including the conditions or sellers that appeared or disappeared
+	QuantityChanges []EntryChange
+
+	// Seller or vendor names only present in the new snapshot
+	NamesAdded []string
+
+	// Seller or vendor names only present in the old snapshot
+	NamesRemoved []string
+}
+
+// SignificantMoves returns the price moves that passed the DiffOpts
+// thresholds.
+func (diff *SnapshotDiff) SignificantMoves() []EntryChange {
+	var result []EntryChange
+	for _, move := range diff.PriceMoves {
+		if move.Significant {
+			result = append(result, move)
+		}
+	}
+	return result
+}
+
+// DiffInventory compares two inventories of the same seller, usually two runs
+// apart, keying entries by condition and SellerName.
+func DiffInventory(opts *DiffOpts, oldInv, newInv InventoryRecord) *SnapshotDiff {
+	return diffRecords(opts, oldInv, newInv, func(entry InventoryEntry) string {
+		return entry.SellerName
+	})
+}
+
+// DiffBuylist compares two buylists of the same vendor, usually two runs
+// apart, keying entries by condition and VendorName.
+func DiffBuylist(opts *DiffOpts, oldBl, newBl BuylistRecord) *SnapshotDiff {
+	return diffRecords(opts, oldBl, newBl, func(entry BuylistEntry) string {
+		return entry.VendorName
+	})
+}
+
+type diffKey struct {
+	conditions string
+	name       string
+}
+
+type diffValue struct {
+	price float64
+	qty   int
+}
+
+// flattenEntries folds the entries of a card into one value per condition and
+// name. The record sort puts the best price for a key first, so that is the
+// price kept, while the quantities of every entry sharing the key add up.
+func flattenEntries[E GenericEntry](entries []E, nameOf func(E) string) map[diffKey]diffValue {
+	out := make(map[diffKey]diffValue, len(entries))
+	for _, entry := range entries {
+		key := diffKey{entry.Condition(), nameOf(entry)}
+		value, found := out[key]
+		if !found {
+			value.price = entry.Pricing()
+		}
+		value.qty += entry.Qty()
+		out[key] = value
+	}
+	return out
+}
+
+func diffRecords[E GenericEntry](opts *DiffOpts, oldRecord, newRecord map[string][]E, nameOf func(E) string) *SnapshotDiff {
+	var o DiffOpts
+	if opts != nil {
+		o = *opts
+	}
+
+	diff := SnapshotDiff{}
+	oldNames := map[string]bool{}
+	newNames := map[string]bool{}
+
+	for cardID, entries := range oldRecord {
+		for _, entry := range entries {
+			oldNames[nameOf(entry)] = true
+		}
+		_, found := newRecord[cardID]
+		if !found {
+			diff.Removed = append(diff.Removed, cardID)
+		}
+	}
+
+	for cardID, newEntries := range newRecord {
+		for _, entry := range newEntries {
+			newNames[nameOf(entry)] = true
+		}
+		oldEntries, found := oldRecord[cardID]
+		if !found {
+			diff.Added = append(diff.Added, cardID)
+			continue
+		}
+
+		oldValues := flattenEntries(oldEntries, nameOf)
+		newValues := flattenEntries(newEntries, nameOf)
+
+		for key, newValue := range newValues {
+			oldValue, found := oldValues[key]
+			change := EntryChange{
+				CardID:      cardID,
+				Conditions:  key.conditions,
+				Name:        key.name,
+				OldPrice:    oldValue.price,
+				NewPrice:    newValue.price,
+				OldQuantity: oldValue.qty,
+				NewQuantity: newValue.qty,
+			}
+			if found && oldValue.price != newValue.price {
+				change.Difference = newValue.price - oldValue.price
+				if oldValue.price != 0 {
+					change.Spread = 100 * change.Difference / oldValue.price
+				}
+				change.Significant = o.isSignificant(change)
+				diff.PriceMoves = append(diff.PriceMoves, change)
+			}
+			if oldValue.qty != newValue.qty {
+				diff.QuantityChanges = append(diff.QuantityChanges, change)
+			}
+		}
+		for key, oldValue := range oldValues {
+			_, found := newValues[key]
+			if found {
+				continue
+			}
+			diff.QuantityChanges = append(diff.QuantityChanges, EntryChange{
+				CardID:      cardID,
+				Conditions:  key.conditions,
+				Name:        key.name,
+				OldPrice:    oldValue.price,
+				OldQuantity: oldValue.qty,
+			})
+		}
+	}
+
+	for name := range newNames {
+		if name != "" && !oldNames[name] {
+			diff.NamesAdded = append(diff.NamesAdded, name)
+		}
+	}
+	for name := range oldNames {
+		if name != "" && !newNames[name] {
+			diff.NamesRemoved = append(diff.NamesRemoved, name)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.NamesAdded)
+	sort.Strings(diff.NamesRemoved)
+	sortChanges(diff.PriceMoves)
+	sortChanges(diff.QuantityChanges)
+
+	return &diff
+}
+
+func (o DiffOpts) isSignificant(change EntryChange) bool {
+	if math.Abs(change.Difference) < o.MinDiff {
+		return false
+	}
+	if math.Abs(change.Spread) < o.MinSpread {
+		return false
+	}
+	if math.Max(change.OldPrice, change.NewPrice) < o.MinPrice {
+		return false
+	}
+	return true
+}
+
+func sortChanges(changes []EntryChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].CardID != changes[j].CardID {
+			return changes[i].CardID < changes[j].CardID
+		}
+		if changes[i].Conditions != changes[j].Conditions {
+			return slices.Index(FullGradeTags, changes[i].Conditions) < slices.Index(FullGradeTags, changes[j].Conditions)
+		}
+		return changes[i].Name < changes[j].Name
+	})
+}
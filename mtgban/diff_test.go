@@ -0,0 +1,96 @@
+package mtgban
+
+import (
+	"testing"
+)
+
+func TestDiffInventory(t *testing.T) {
+	oldInv := InventoryRecord{
+		"gone": {{Conditions: "NM", Price: 1, Quantity: 1, SellerName: "A"}},
+		"kept": {
+			{Conditions: "NM", Price: 10, Quantity: 2, SellerName: "A"},
+			{Conditions: "NM", Price: 12, Quantity: 1, SellerName: "B"},
+			{Conditions: "SP", Price: 8, Quantity: 1, SellerName: "A"},
+		},
+	}
+	newInv := InventoryRecord{
+		"kept": {
+			{Conditions: "NM", Price: 15, Quantity: 2, SellerName: "A"},
+			{Conditions: "NM", Price: 12.10, Quantity: 3, SellerName: "C"},
+			{Conditions: "SP", Price: 8, Quantity: 4, SellerName: "A"},
+		},
+		"new": {{Conditions: "NM", Price: 1, Quantity: 1, SellerName: "A"}},
+	}
+
+	diff := DiffInventory(&DiffOpts{MinDiff: 1, MinSpread: 10}, oldInv, newInv)
+
+	if len(diff.Added) != 1 || diff.Added[0] != "new" {
+		t.Errorf("unexpected added cards %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0] != "gone" {
+		t.Errorf("unexpected removed cards %v", diff.Removed)
+	}
+	if len(diff.NamesAdded) != 1 || diff.NamesAdded[0] != "C" {
+		t.Errorf("unexpected added names %v", diff.NamesAdded)
+	}
+	if len(diff.NamesRemoved) != 1 || diff.NamesRemoved[0] != "B" {
+		t.Errorf("unexpected removed names %v", diff.NamesRemoved)
+	}
+
+	if len(diff.PriceMoves) != 1 {
+		t.Fatalf("expected one price move, got %+v", diff.PriceMoves)
+	}
+	move := diff.PriceMoves[0]
+	if move.Name != "A" || move.Conditions != "NM" || move.Difference != 5 || move.Spread != 50 || !move.Significant {
+		t.Errorf("unexpected price move %+v", move)
+	}
+
+	// SP from A changed quantity, B left and C arrived
+	if len(diff.QuantityChanges) != 3 {
+		t.Fatalf("expected three quantity changes, got %+v", diff.QuantityChanges)
+	}
+	for _, change := range diff.QuantityChanges {
+		switch change.Name {
+		case "B":
+			if change.NewQuantity != 0 || change.OldQuantity != 1 {
+				t.Errorf("a departed seller was reported as %+v", change)
+			}
+		case "C":
+			if change.OldQuantity != 0 || change.NewQuantity != 3 {
+				t.Errorf("an arrived seller was reported as %+v", change)
+			}
+		case "A":
+			if change.Conditions != "SP" || change.NewQuantity != 4 {
+				t.Errorf("unexpected quantity change %+v", change)
+			}
+		}
+	}
+}
+
+func TestDiffBuylistThresholds(t *testing.T) {
+	oldBl := BuylistRecord{
+		"a": {{Conditions: "NM", BuyPrice: 100, Quantity: 4}},
+		"b": {{Conditions: "NM", BuyPrice: 1, Quantity: 4}},
+	}
+	newBl := BuylistRecord{
+		"a": {{Conditions: "NM", BuyPrice: 105, Quantity: 4}},
+		"b": {{Conditions: "NM", BuyPrice: 2, Quantity: 4}},
+	}
+
+	diff := DiffBuylist(nil, oldBl, newBl)
+	if len(diff.SignificantMoves()) != 2 {
+		t.Errorf("without thresholds every move should be significant, got %+v", diff.PriceMoves)
+	}
+
+	// a moved 5% and b moved a dollar, neither passes both thresholds
+	diff = DiffBuylist(&DiffOpts{MinDiff: 2, MinSpread: 20}, oldBl, newBl)
+	if len(diff.PriceMoves) != 2 {
+		t.Fatalf("expected two price moves, got %+v", diff.PriceMoves)
+	}
+	if len(diff.SignificantMoves()) != 0 {
+		t.Errorf("moves below threshold were significant: %+v", diff.SignificantMoves())
+	}
+	if len(diff.QuantityChanges) != 0 {
+		t.Errorf("unexpected quantity changes %+v", diff.QuantityChanges)
+	}
+}
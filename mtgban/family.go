@@ -0,0 +1,224 @@
+package mtgban
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// FamilyPolicy decides how the entries of the members of a family are merged.
+type FamilyPolicy int
+
+const (
+	// FamilyKeepAll keeps every entry of every member. Entries with no
+	// SellerName or VendorName get the Shorthand of their member, so that
+	// where each price comes from is not lost.
+	FamilyKeepAll FamilyPolicy = iota
+
+	// FamilyBestPrice keeps a single entry per card and condition, the one
+	// with the lowest price for inventories and the highest for buylists,
+	// with the same naming as FamilyKeepAll.
+	FamilyBestPrice
+)
+
+// CoalesceSellers merges the sellers belonging to family into one, with the
+// info of the first member renamed after the family. It returns nil when no
+// seller is part of family. Entries with an invalid condition are left out
+// and reported in the error, which does not prevent the merge.
+func CoalesceSellers(policy FamilyPolicy, family string, sellers []Seller) (Seller, error) {
+	var infos []ScraperInfo
+	var errs []error
+	inventory := InventoryRecord{}
+	for _, seller := range sellers {
+		info := seller.Info()
+		if info.Family != family {
+			continue
+		}
+		infos = append(infos, info)
+
+		for cardID, entries := range seller.Inventory() {
+			for _, entry := range entries {
+				if entry.Conditions == "" {
+					entry.Conditions = "NM"
+				}
+				if !slices.Contains(FullGradeTags, entry.Conditions) {
+					errs = append(errs, fmt.Errorf("%s: %s %q: %w", info.Shorthand, cardID, entry.Conditions, ErrInvalidCondition))
+					continue
+				}
+				if entry.SellerName == "" {
+					entry.SellerName = info.Shorthand
+				}
+				if policy == FamilyBestPrice {
+					keepBest(inventory, cardID, entry, func(a, b InventoryEntry) bool {
+						return a.Price < b.Price
+					})
+					continue
+				}
+				inventory[cardID] = append(inventory[cardID], entry)
+			}
+		}
+	}
+	if len(infos) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	for _, entries := range inventory {
+		sortInventoryEntries(entries)
+	}
+
+	return NewSellerFromInventory(inventory, familyInfo(family, infos)), errors.Join(errs...)
+}
+
+// CoalesceVendors merges the vendors belonging to family into one, like
+// CoalesceSellers does for sellers.
+func CoalesceVendors(policy FamilyPolicy, family string, vendors []Vendor) (Vendor, error) {
+	var infos []ScraperInfo
+	var errs []error
+	buylist := BuylistRecord{}
+	for _, vendor := range vendors {
+		info := vendor.Info()
+		if info.Family != family {
+			continue
+		}
+		infos = append(infos, info)
+
+		for cardID, entries := range vendor.Buylist() {
+			for _, entry := range entries {
+				if entry.Conditions == "" {
+					entry.Conditions = "NM"
+				}
+				if !slices.Contains(FullGradeTags, entry.Conditions) {
+					errs = append(errs, fmt.Errorf("%s: %s %q: %w", info.Shorthand, cardID, entry.Conditions, ErrInvalidCondition))
+					continue
+				}
+				if entry.VendorName == "" {
+					entry.VendorName = info.Shorthand
+				}
+				if policy == FamilyBestPrice {
+					keepBest(buylist, cardID, entry, func(a, b BuylistEntry) bool {
+						return a.BuyPrice > b.BuyPrice
+					})
+					continue
+				}
+				buylist[cardID] = append(buylist[cardID], entry)
+			}
+		}
+	}
+	if len(infos) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	for _, entries := range buylist {
+		sortBuylistEntries(entries)
+	}
+
+	return NewVendorFromBuylist(buylist, familyInfo(family, infos)), errors.Join(errs...)
+}
+
+// CoalesceScrapers unfolds scrapers like UnfoldScrapers does, then replaces
+// the members of each family with the one seller or vendor merging them.
+// Members of a family are only merged with the ones sharing their game and
+// sealed mode. The merged scrapers take the place of the first member. The
+// entries left out of a merge are reported in the error.
+func CoalesceScrapers(policy FamilyPolicy, scrapers []Scraper) ([]Seller, []Vendor, error) {
+	sellers, vendors := UnfoldScrapers(scrapers)
+
+	var errs []error
+	sellers = coalesceFamilies(sellers, func(members []Seller, family string) Seller {
+		seller, err := CoalesceSellers(policy, family, members)
+		errs = append(errs, err)
+		return seller
+	})
+	vendors = coalesceFamilies(vendors, func(members []Vendor, family string) Vendor {
+		vendor, err := CoalesceVendors(policy, family, members)
+		errs = append(errs, err)
+		return vendor
+	})
+
+	return sellers, vendors, errors.Join(errs...)
+}
+
+type familyKey struct {
+	family string
+	game   string
+	sealed bool
+}
+
+func coalesceFamilies[S Scraper](scrapers []S, coalesce func([]S, string) S) []S {
+	groups := map[familyKey][]S{}
+	for _, scraper := range scrapers {
+		info := scraper.Info()
+		if info.Family == "" {
+			continue
+		}
+		key := familyKey{info.Family, info.Game, info.SealedMode}
+		groups[key] = append(groups[key], scraper)
+	}
+
+	var result []S
+	done := map[familyKey]bool{}
+	for _, scraper := range scrapers {
+		info := scraper.Info()
+		if info.Family == "" {
+			result = append(result, scraper)
+			continue
+		}
+		key := familyKey{info.Family, info.Game, info.SealedMode}
+		if done[key] {
+			continue
+		}
+		done[key] = true
+		result = append(result, coalesce(groups[key], info.Family))
+	}
+	return result
+}
+
+// familyInfo describes the merge of the members of family: a flag is only
+// kept when every member agrees on it, and a timestamp is the oldest among
+// the members, as that is how fresh the merged prices are.
+func familyInfo(family string, infos []ScraperInfo) ScraperInfo {
+	info := infos[0]
+	info.Name = family
+	info.Shorthand = family
+
+	for _, other := range infos[1:] {
+		if other.CountryFlag != info.CountryFlag {
+			info.CountryFlag = ""
+		}
+		if other.CreditMultiplier != info.CreditMultiplier {
+			info.CreditMultiplier = 0
+		}
+		if other.Currency != info.Currency {
+			info.Currency = ""
+		}
+		info.MetadataOnly = info.MetadataOnly && other.MetadataOnly
+		info.NoQuantityInventory = info.NoQuantityInventory || other.NoQuantityInventory
+		info.InventoryTimestamp = oldestTimestamp(info.InventoryTimestamp, other.InventoryTimestamp)
+		info.BuylistTimestamp = oldestTimestamp(info.BuylistTimestamp, other.BuylistTimestamp)
+	}
+	return info
+}
+
+func oldestTimestamp(a, b *time.Time) *time.Time {
+	if a == nil {
+		return b
+	}
+	if b != nil && b.Before(*a) {
+		return b
+	}
+	return a
+}
+
+// keepBest stores entry unless record already has a better one for the same
+// card and condition.
+func keepBest[E GenericEntry](record map[string][]E, cardID string, entry E, better func(a, b E) bool) {
+	for i, other := range record[cardID] {
+		if other.Condition() != entry.Condition() {
+			continue
+		}
+		if better(entry, other) {
+			record[cardID][i] = entry
+		}
+		return
+	}
+	record[cardID] = append(record[cardID], entry)
+}
@@ -0,0 +1,103 @@
+package mtgban
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func familySellers() []Seller {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	return []Seller{
+		NewSellerFromInventory(InventoryRecord{
+			"A": {{Conditions: "NM", Price: 5, Quantity: 1}, {Conditions: "SP", Price: 3, Quantity: 1}},
+		}, ScraperInfo{Shorthand: "CT", Family: "CT", CountryFlag: "EU", InventoryTimestamp: &newer}),
+		NewSellerFromInventory(InventoryRecord{
+			"A": {{Conditions: "NM", Price: 4, Quantity: 2, SellerName: "Card Trader Zero", Bundle: true}},
+			"B": {{Conditions: "NM", Price: 1, Quantity: 1, SellerName: "Card Trader Zero", Bundle: true}},
+		}, ScraperInfo{Shorthand: "CT0", Family: "CT", CountryFlag: "EU", InventoryTimestamp: &older}),
+		NewSellerFromInventory(InventoryRecord{
+			"A": {{Conditions: "NM", Price: 1, Quantity: 1}},
+		}, ScraperInfo{Shorthand: "OTHER", InventoryTimestamp: &older}),
+	}
+}
+
+func TestCoalesceSellers(t *testing.T) {
+	merged, err := CoalesceSellers(FamilyKeepAll, "CT", familySellers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	inventory := merged.Inventory()
+	if len(inventory["A"]) != 3 || len(inventory["B"]) != 1 {
+		t.Fatalf("expected every entry of the family, got %v", inventory)
+	}
+	if inventory["A"][0].Price != 4 || inventory["A"][1].SellerName != "CT" {
+		t.Errorf("unexpected order or provenance: %v", inventory["A"])
+	}
+
+	info := merged.Info()
+	if info.Shorthand != "CT" || info.CountryFlag != "EU" || !info.InventoryTimestamp.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected info %+v", info)
+	}
+
+	merged, err = CoalesceSellers(FamilyBestPrice, "CT", familySellers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	inventory = merged.Inventory()
+	if len(inventory["A"]) != 2 || inventory["A"][0].Price != 4 || inventory["A"][1].Conditions != "SP" {
+		t.Errorf("expected the best NM and SP entries, got %v", inventory["A"])
+	}
+
+	merged, _ = CoalesceSellers(FamilyKeepAll, "MKM", familySellers())
+	if merged != nil {
+		t.Error("a family with no members was coalesced")
+	}
+}
+
+func TestCoalesceInvalidConditions(t *testing.T) {
+	sellers := append(familySellers(), NewSellerFromInventory(InventoryRecord{
+		"A": {{Conditions: "NM", Price: 4, Quantity: 2}, {Conditions: "NM", Price: 4, Quantity: 2}, {Conditions: "MINT", Price: 9}},
+	}, ScraperInfo{Shorthand: "CT1", Family: "CT"}))
+
+	merged, err := CoalesceSellers(FamilyKeepAll, "CT", sellers)
+	if !errors.Is(err, ErrInvalidCondition) {
+		t.Errorf("expected the invalid condition reported, got %v", err)
+	}
+	if merged == nil || len(merged.Inventory()["A"]) != 5 {
+		t.Fatalf("expected every valid entry kept apart, got %v", merged)
+	}
+
+	vendor := NewVendorFromBuylist(BuylistRecord{
+		"A": {{Conditions: "", BuyPrice: 1}, {Conditions: "GEM", BuyPrice: 2}},
+	}, ScraperInfo{Shorthand: "V", Family: "CT"})
+	merged2, err := CoalesceVendors(FamilyBestPrice, "CT", []Vendor{vendor})
+	if !errors.Is(err, ErrInvalidCondition) {
+		t.Errorf("expected the invalid condition reported, got %v", err)
+	}
+	if len(merged2.Buylist()["A"]) != 1 || merged2.Buylist()["A"][0].Conditions != "NM" {
+		t.Errorf("unexpected buylist %v", merged2.Buylist())
+	}
+}
+
+func TestCoalesceScrapers(t *testing.T) {
+	var scrapers []Scraper
+	for _, seller := range familySellers() {
+		scrapers = append(scrapers, seller)
+	}
+	scrapers = append(scrapers, NewVendorFromBuylist(BuylistRecord{
+		"A": {{Conditions: "NM", BuyPrice: 2}},
+	}, ScraperInfo{Shorthand: "V", Family: "CT", BuylistTimestamp: new(time.Time)}))
+
+	sellers, vendors, err := CoalesceScrapers(FamilyKeepAll, scrapers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sellers) != 2 || sellers[0].Info().Shorthand != "CT" || sellers[1].Info().Shorthand != "OTHER" {
+		t.Errorf("expected the family merged in place of its first member, got %d sellers", len(sellers))
+	}
+	if len(vendors) != 1 || vendors[0].Buylist()["A"][0].VendorName != "V" {
+		t.Errorf("unexpected vendors %v", vendors)
+	}
+}
@@ -0,0 +1,168 @@
+package mtgban
+
+// FeeSchedule is what one side of a trade costs on top of the listed prices.
+// Percentages are expressed like spreads, so 12.5 means 12.5%. Fees a scraper
+// already deducts from its prices, like Cardsphere's, should not be repeated.
+type FeeSchedule struct {
+	// Percentage of every price taken as commission
+	Commission float64
+
+	// Fixed amount charged on every card
+	PerItem float64
+
+	// Amount charged on every card depending on its price, for fees that
+	// come in tiers
+	PerItemFunc func(price float64) float64
+
+	// Percentage of the order total taken by the payment processor
+	PaymentPercent float64
+
+	// Fixed amount taken by the payment processor on every order
+	PaymentFixed float64
+
+	// Cost of shipping one order
+	Shipping float64
+
+	// Minimum total an order needs to reach to be accepted
+	MinOrder float64
+}
+
+// FeeModel assigns a FeeSchedule to each side of Arbit. The seller side is
+// what buying the cards costs, the vendor side is what selling them back
+// costs. Every seller or vendor name is assumed to be one order, split further
+// by CustomFields["SubSellerName"] when present, whose fixed costs are split
+// across its cards by their share of the order total.
+type FeeModel struct {
+	// Fees of buying, keyed by SellerName or by the seller Shorthand
+	Sellers map[string]FeeSchedule
+
+	// Fees of selling, keyed by VendorName or by the vendor Shorthand
+	Vendors map[string]FeeSchedule
+
+	// Fees of the sellers and vendors missing from the maps above
+	DefaultSeller FeeSchedule
+	DefaultVendor FeeSchedule
+
+	// Value buylist prices as store credit, using the CreditMultiplier of
+	// the vendor when it has one
+	StoreCredit bool
+}
+
+// unitCost is what buying one card listed at price costs.
+func (fs *FeeSchedule) unitCost(price float64) float64 {
+	cost := price*(1+fs.Commission/100) + fs.PerItem
+	if fs.PerItemFunc != nil {
+		cost += fs.PerItemFunc(price)
+	}
+	return cost * (1 + fs.PaymentPercent/100)
+}
+
+// unitProceeds is what selling one card bought at price brings in.
+func (fs *FeeSchedule) unitProceeds(price float64) float64 {
+	proceeds := price*(1-fs.Commission/100-fs.PaymentPercent/100) - fs.PerItem
+	if fs.PerItemFunc != nil {
+		proceeds -= fs.PerItemFunc(price)
+	}
+	return proceeds
+}
+
+// orderCost is what every order costs regardless of its contents.
+func (fs *FeeSchedule) orderCost() float64 {
+	return fs.Shipping + fs.PaymentFixed
+}
+
+func lookupSchedule(schedules map[string]FeeSchedule, fallback FeeSchedule, name, shorthand string) (string, FeeSchedule) {
+	if name == "" {
+		name = shorthand
+	}
+	schedule, found := schedules[name]
+	if found {
+		return name, schedule
+	}
+	schedule, found = schedules[shorthand]
+	if found {
+		return name, schedule
+	}
+	return name, fallback
+}
+
+type feeOrder struct {
+	schedule FeeSchedule
+	total    float64
+}
+
+// feeOrderKey names the order an entry belongs to, keeping the sellers
+// inside a market apart as OptimizeCart does, unless they ship together.
+func feeOrderKey(name string, customFields map[string]string, bundle bool) string {
+	subSellerName := customFields["SubSellerName"]
+	if bundle || subSellerName == "" {
+		return name
+	}
+	return name + "|" + subSellerName
+}
+
+// apply fills the net numbers of entries, where prices are the inventory
+// prices each entry was compared at, and flags the entries that belong to an
+// order not reaching its minimum.
+func (fm *FeeModel) apply(entries []ArbitEntry, prices []float64, vendorInfo, sellerInfo ScraperInfo) []ArbitEntry {
+	credit := 1.0
+	if fm.StoreCredit && vendorInfo.CreditMultiplier > 0 {
+		credit = vendorInfo.CreditMultiplier
+	}
+
+	buyOrders := map[string]*feeOrder{}
+	sellOrders := map[string]*feeOrder{}
+	buyKeys := make([]string, len(entries))
+	sellKeys := make([]string, len(entries))
+
+	for i, entry := range entries {
+		qty := float64(max(entry.Quantity, 1))
+
+		name, schedule := lookupSchedule(fm.Sellers, fm.DefaultSeller, entry.InventoryEntry.SellerName, sellerInfo.Shorthand)
+		key := feeOrderKey(name, entry.InventoryEntry.CustomFields, entry.InventoryEntry.Bundle)
+		order, found := buyOrders[key]
+		if !found {
+			order = &feeOrder{schedule: schedule}
+			buyOrders[key] = order
+		}
+		order.total += prices[i] * qty
+		buyKeys[i] = key
+
+		name, schedule = lookupSchedule(fm.Vendors, fm.DefaultVendor, entry.BuylistEntry.VendorName, vendorInfo.Shorthand)
+		key = feeOrderKey(name, entry.BuylistEntry.CustomFields, false)
+		order, found = sellOrders[key]
+		if !found {
+			order = &feeOrder{schedule: schedule}
+			sellOrders[key] = order
+		}
+		order.total += entry.BuylistEntry.BuyPrice * credit * qty
+		sellKeys[i] = key
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		buy := buyOrders[buyKeys[i]]
+		sell := sellOrders[sellKeys[i]]
+		entry.BelowMinimum = buy.total < buy.schedule.MinOrder || sell.total < sell.schedule.MinOrder
+
+		qty := float64(max(entry.Quantity, 1))
+		price := prices[i]
+		blPrice := entry.BuylistEntry.BuyPrice * credit
+
+		// Per order costs, split by the share of the order this entry is
+		fixed := buy.schedule.orderCost() * price * qty / buy.total
+		fixed += sell.schedule.orderCost() * blPrice * qty / sell.total
+
+		cost := buy.schedule.unitCost(price) + fixed/qty
+		net := sell.schedule.unitProceeds(blPrice) - cost
+
+		entry.NetDifference = net
+		entry.NetAbsoluteDifference = net * qty
+		entry.NetSpread = 0
+		if cost > 0 {
+			entry.NetSpread = 100 * net / cost
+		}
+	}
+
+	return entries
+}
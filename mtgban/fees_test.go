@@ -0,0 +1,134 @@
+package mtgban
+
+import (
+	"math"
+	"testing"
+)
+
+func feeEntries() ([]ArbitEntry, []float64) {
+	entries := []ArbitEntry{
+		{
+			CardID:         "A",
+			InventoryEntry: InventoryEntry{Price: 10, Quantity: 1, SellerName: "Seller"},
+			BuylistEntry:   BuylistEntry{BuyPrice: 20},
+			Quantity:       1,
+		},
+		{
+			CardID:         "B",
+			InventoryEntry: InventoryEntry{Price: 30, Quantity: 1, SellerName: "Seller"},
+			BuylistEntry:   BuylistEntry{BuyPrice: 40},
+			Quantity:       1,
+		},
+	}
+	return entries, []float64{10, 30}
+}
+
+func TestFeeModel(t *testing.T) {
+	fees := &FeeModel{
+		Sellers: map[string]FeeSchedule{
+			"Seller": {Shipping: 4},
+		},
+		DefaultVendor: FeeSchedule{Commission: 10},
+	}
+
+	entries, prices := feeEntries()
+	result := fees.apply(entries, prices, ScraperInfo{Shorthand: "V"}, ScraperInfo{Shorthand: "S"})
+	if len(result) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(result))
+	}
+
+	// Shipping is split 1 and 3 by order share, and the vendor keeps 10%
+	for i, expected := range []float64{18 - 11, 36 - 33} {
+		if math.Abs(result[i].NetDifference-expected) > 1e-9 {
+			t.Errorf("entry %d: net difference is %f, expected %f", i, result[i].NetDifference, expected)
+		}
+	}
+	if math.Abs(result[0].NetSpread-100*7.0/11) > 1e-9 {
+		t.Errorf("unexpected net spread %f", result[0].NetSpread)
+	}
+}
+
+func TestFeeModelStoreCredit(t *testing.T) {
+	fees := &FeeModel{
+		DefaultVendor: FeeSchedule{Commission: 10},
+		StoreCredit:   true,
+	}
+
+	entries, prices := feeEntries()
+	result := fees.apply(entries, prices, ScraperInfo{CreditMultiplier: 1.5}, ScraperInfo{})
+	if math.Abs(result[0].NetDifference-(27-10)) > 1e-9 {
+		t.Errorf("net difference is %f, expected 17", result[0].NetDifference)
+	}
+}
+
+func TestFeeModelMinOrder(t *testing.T) {
+	fees := &FeeModel{
+		Vendors: map[string]FeeSchedule{
+			"V": {MinOrder: 100},
+		},
+	}
+
+	entries, prices := feeEntries()
+	result := fees.apply(entries, prices, ScraperInfo{Shorthand: "V"}, ScraperInfo{})
+	if len(result) != 2 {
+		t.Fatalf("expected the order below the minimum to be kept, got %d entries", len(result))
+	}
+	for i := range result {
+		if !result[i].BelowMinimum {
+			t.Errorf("entry %d: expected to be flagged below the minimum", i)
+		}
+	}
+
+	fees.Vendors["V"] = FeeSchedule{MinOrder: 60}
+	entries, prices = feeEntries()
+	result = fees.apply(entries, prices, ScraperInfo{Shorthand: "V"}, ScraperInfo{})
+	for i := range result {
+		if result[i].BelowMinimum {
+			t.Errorf("entry %d: expected the order reaching the minimum not to be flagged", i)
+		}
+	}
+}
+
+func TestFeeModelSubSellers(t *testing.T) {
+	fees := &FeeModel{
+		DefaultSeller: FeeSchedule{Shipping: 4, MinOrder: 20},
+	}
+
+	// The same market name holds two sellers, each shipping on their own
+	entries, prices := feeEntries()
+	entries[0].InventoryEntry.CustomFields = map[string]string{"SubSellerName": "a"}
+	entries[1].InventoryEntry.CustomFields = map[string]string{"SubSellerName": "b"}
+	result := fees.apply(entries, prices, ScraperInfo{}, ScraperInfo{})
+	for i, expected := range []float64{20 - 14, 40 - 34} {
+		if math.Abs(result[i].NetDifference-expected) > 1e-9 {
+			t.Errorf("entry %d: net difference is %f, expected %f", i, result[i].NetDifference, expected)
+		}
+	}
+	if !result[0].BelowMinimum || result[1].BelowMinimum {
+		t.Errorf("expected only the first seller below the minimum, got %v and %v", result[0].BelowMinimum, result[1].BelowMinimum)
+	}
+
+	// Unless they ship together
+	entries, prices = feeEntries()
+	for i := range entries {
+		entries[i].InventoryEntry.CustomFields = map[string]string{"SubSellerName": string(rune('a' + i))}
+		entries[i].InventoryEntry.Bundle = true
+	}
+	result = fees.apply(entries, prices, ScraperInfo{}, ScraperInfo{})
+	if math.Abs(result[0].NetDifference-(20-11)) > 1e-9 || result[0].BelowMinimum {
+		t.Errorf("expected one bundled order, got %+v", result[0])
+	}
+}
+
+func TestFeeModelQuantity(t *testing.T) {
+	fees := &FeeModel{}
+
+	// An entry without a quantity counts as one card
+	entries, prices := feeEntries()
+	entries[0].Quantity = 0
+	entries[1].Quantity = 3
+	result := fees.apply(entries, prices, ScraperInfo{}, ScraperInfo{})
+	if result[0].NetAbsoluteDifference != 10 || result[1].NetAbsoluteDifference != 30 {
+		t.Errorf("unexpected net absolute differences %f and %f", result[0].NetAbsoluteDifference, result[1].NetAbsoluteDifference)
+	}
+}
@@ -0,0 +1,243 @@
+package mtgban
+
+import (
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ulikunitz/xz"
+)
+
+// decompressors maps the extensions of the compressed files the
+// Read*FromFile functions open to the function wrapping them.
+var decompressors = map[string]func(io.Reader) (io.Reader, error){
+	".xz": func(r io.Reader) (io.Reader, error) {
+		return xz.NewReader(r)
+	},
+	".gz": func(r io.Reader) (io.Reader, error) {
+		return gzip.NewReader(r)
+	},
+	".bz2": func(r io.Reader) (io.Reader, error) {
+		return bzip2.NewReader(r), nil
+	},
+}
+
+// ReadSeller rebuilds a seller from an inventory in one of the formats
+// bantool writes, "json", "col", "csv" or "ndjson". The info of a csv or
+// ndjson file comes from meta, the sidecar written next to it, when not nil.
+func ReadSeller(format string, data, meta io.Reader) (Seller, error) {
+	switch format {
+	case "json":
+		return ReadSellerFromJSON(data)
+	case "col":
+		return ReadSellerFromColumnar(data)
+	}
+
+	var inventory InventoryRecord
+	var err error
+	switch format {
+	case "csv":
+		inventory, err = LoadInventoryFromCSV(data)
+	case "ndjson":
+		inventory, err = LoadInventoryFromNDJSON(data)
+	default:
+		err = fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := readSidecar(meta)
+	if err != nil {
+		return nil, err
+	}
+	return NewSellerFromInventory(inventory, info), nil
+}
+
+// ReadVendor rebuilds a vendor from a buylist in one of the formats bantool
+// writes, like ReadSeller does for inventories.
+func ReadVendor(format string, data, meta io.Reader) (Vendor, error) {
+	switch format {
+	case "json":
+		return ReadVendorFromJSON(data)
+	case "col":
+		return ReadVendorFromColumnar(data)
+	}
+
+	var buylist BuylistRecord
+	var err error
+	switch format {
+	case "csv":
+		buylist, err = LoadBuylistFromCSV(data)
+	case "ndjson":
+		buylist, err = LoadBuylistFromNDJSON(data)
+	default:
+		err = fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := readSidecar(meta)
+	if err != nil {
+		return nil, err
+	}
+	return NewVendorFromBuylist(buylist, info), nil
+}
+
+// ReadSellerFromFile opens a file written by bantool, such as
+// "retail/CK.csv.xz", decompressing it according to its extension and
+// picking up the "retail/CK.json" sidecar when there is one. Without a
+// sidecar, the info only holds the shorthand found in the file name.
+func ReadSellerFromFile(path string) (Seller, error) {
+	dump, err := openDump(path)
+	if err != nil {
+		return nil, err
+	}
+	defer dump.Close()
+
+	seller, err := ReadSeller(dump.format, dump.data, dump.meta)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if !HasOwnInfo(dump.format) && dump.meta == nil {
+		seller = NewSellerFromInventory(seller.Inventory(), dump.info())
+	}
+	return seller, nil
+}
+
+// ReadVendorFromFile opens a buylist file written by bantool, like
+// ReadSellerFromFile does for inventories.
+func ReadVendorFromFile(path string) (Vendor, error) {
+	dump, err := openDump(path)
+	if err != nil {
+		return nil, err
+	}
+	defer dump.Close()
+
+	vendor, err := ReadVendor(dump.format, dump.data, dump.meta)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if !HasOwnInfo(dump.format) && dump.meta == nil {
+		vendor = NewVendorFromBuylist(vendor.Buylist(), dump.info())
+	}
+	return vendor, nil
+}
+
+// HasOwnInfo tells whether files of format, such as "json" or "csv", hold
+// their info along with the prices, or need a sidecar for it.
+func HasOwnInfo(format string) bool {
+	return format == "json" || format == "col"
+}
+
+// readSidecar returns the info of a file written by WriteSellerToJSON or
+// WriteVendorToJSON, or an empty one when meta is nil.
+func readSidecar(meta io.Reader) (ScraperInfo, error) {
+	if meta == nil {
+		return ScraperInfo{}, nil
+	}
+	data, err := readDump(meta)
+	if err != nil {
+		return ScraperInfo{}, fmt.Errorf("error reading sidecar: %v", err)
+	}
+	return data.Info, nil
+}
+
+// dumpFile is a file opened by openDump, along with its sidecar.
+type dumpFile struct {
+	format    string
+	shorthand string
+
+	data io.Reader
+	meta io.Reader
+
+	files []*os.File
+}
+
+func (df *dumpFile) info() ScraperInfo {
+	return ScraperInfo{
+		Name:      df.shorthand,
+		Shorthand: df.shorthand,
+	}
+}
+
+func (df *dumpFile) Close() error {
+	var errs []error
+	for _, file := range df.files {
+		errs = append(errs, file.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// openDump opens path and, unless its format holds its own info,
+// the json sidecar sharing its name, plain or compressed like path is.
+func openDump(path string) (*dumpFile, error) {
+	stem := path
+	compression := filepath.Ext(stem)
+	if decompressors[compression] != nil {
+		stem = strings.TrimSuffix(stem, compression)
+	} else {
+		compression = ""
+	}
+	format := strings.TrimPrefix(filepath.Ext(stem), ".")
+	stem = strings.TrimSuffix(stem, filepath.Ext(stem))
+
+	df := dumpFile{
+		format:    format,
+		shorthand: filepath.Base(stem),
+	}
+
+	data, err := df.open(path, compression)
+	if err != nil {
+		df.Close()
+		return nil, err
+	}
+	df.data = data
+
+	if HasOwnInfo(format) {
+		return &df, nil
+	}
+	exts := []string{""}
+	if compression != "" {
+		exts = append(exts, compression)
+	}
+	for _, ext := range exts {
+		sidecar := stem + ".json" + ext
+		_, err := os.Stat(sidecar)
+		if err != nil {
+			continue
+		}
+		meta, err := df.open(sidecar, ext)
+		if err != nil {
+			df.Close()
+			return nil, err
+		}
+		df.meta = meta
+		break
+	}
+
+	return &df, nil
+}
+
+func (df *dumpFile) open(path, compression string) (io.Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	df.files = append(df.files, file)
+
+	if compression == "" {
+		return file, nil
+	}
+	r, err := decompressors[compression](file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
@@ -0,0 +1,152 @@
+package mtgban
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ulikunitz/xz"
+)
+
+func TestNDJSONRoundTrip(t *testing.T) {
+	inventory := InventoryRecord{
+		"x|A|SET|1": {{Conditions: "NM", Price: 2, Quantity: 1, URL: "a"}, {Conditions: "SP", Price: 1, Quantity: 3, SellerName: "S"}},
+		"x|B|SET|2": {{Conditions: "MP", Price: 5, Quantity: 1}},
+	}
+	buylist := BuylistRecord{
+		"x|A|SET|1": {{Conditions: "NM", BuyPrice: 1, Quantity: 4, PriceRatio: 50}},
+	}
+
+	var buf bytes.Buffer
+	err := WriteSellerToNDJSON(NewSellerFromInventory(inventory, ScraperInfo{}), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seller, err := ReadSellerFromNDJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(seller.Inventory(), inventory) {
+		t.Errorf("expected %v, got %v", inventory, seller.Inventory())
+	}
+
+	buf.Reset()
+	err = WriteVendorToNDJSON(NewVendorFromBuylist(buylist, ScraperInfo{}), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vendor, err := ReadVendorFromNDJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(vendor.Buylist(), buylist) {
+		t.Errorf("expected %v, got %v", buylist, vendor.Buylist())
+	}
+
+	_, err = ReadSellerFromNDJSON(bytes.NewBufferString(`{"price":1}` + "\n"))
+	if err == nil {
+		t.Error("a line with no UUID was accepted")
+	}
+}
+
+func TestReadFromFile(t *testing.T) {
+	dir := t.TempDir()
+	inventory := InventoryRecord{
+		"x|A|SET|1": {{Conditions: "NM", Price: 2, Quantity: 1, URL: "a"}},
+	}
+	info := ScraperInfo{Name: "Test Store", Shorthand: "TST", CountryFlag: "EU"}
+	seller := NewSellerFromInventory(inventory, info)
+
+	// A compressed csv file and its plain sidecar, as bantool writes them
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	err := WriteInventoryToCSV(inventory, gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz.Close()
+	writeFile(t, filepath.Join(dir, "TST.csv.gz"), buf.Bytes())
+
+	buf.Reset()
+	err = WriteSellerToJSON(NewSellerFromInventory(nil, info), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "TST.json"), buf.Bytes())
+
+	got, err := ReadSellerFromFile(filepath.Join(dir, "TST.csv.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Inventory(), inventory) {
+		t.Errorf("expected %v, got %v", inventory, got.Inventory())
+	}
+	if got.Info().Name != "Test Store" || got.Info().CountryFlag != "EU" {
+		t.Errorf("sidecar not loaded: %+v", got.Info())
+	}
+
+	// An xz ndjson file with no sidecar
+	buf.Reset()
+	xzw, err := xz.NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WriteSellerToNDJSON(seller, xzw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xzw.Close()
+	writeFile(t, filepath.Join(dir, "OTHER.ndjson.xz"), buf.Bytes())
+
+	got, err = ReadSellerFromFile(filepath.Join(dir, "OTHER.ndjson.xz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Inventory(), inventory) {
+		t.Errorf("expected %v, got %v", inventory, got.Inventory())
+	}
+	if got.Info().Shorthand != "OTHER" || got.Info().CountryFlag != "" {
+		t.Errorf("expected the info of the file name, got %+v", got.Info())
+	}
+
+	// The json sidecar is a readable vendor on its own
+	vendor, err := ReadVendorFromFile(filepath.Join(dir, "TST.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vendor.Info().Shorthand != "TST" || len(vendor.Buylist()) != 0 {
+		t.Errorf("unexpected vendor %+v %v", vendor.Info(), vendor.Buylist())
+	}
+
+	// A columnar file holds its own info
+	buf.Reset()
+	err = WriteSellerToColumnar(NewSellerFromInventory(inventory, ScraperInfo{Shorthand: "COL", CountryFlag: "JP"}), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "COL.col"), buf.Bytes())
+
+	got, err = ReadSellerFromFile(filepath.Join(dir, "COL.col"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Inventory(), inventory) || got.Info().CountryFlag != "JP" {
+		t.Errorf("unexpected seller %v %+v", got.Inventory(), got.Info())
+	}
+
+	_, err = ReadSellerFromFile(filepath.Join(dir, "TST.txt"))
+	if err == nil {
+		t.Error("a missing file was read")
+	}
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	err := os.WriteFile(path, data, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
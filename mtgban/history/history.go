@@ -0,0 +1,327 @@
+// Package history keeps the prices of successive scraper snapshots in a local
+// append-only store, so that what a card cost at some point, or over a
+// window, can be answered without keeping and re-reading every dump.
+//
+// Each scraper and side gets one log file. A snapshot is reduced to one price
+// and quantity per card and condition, the best one on offer, and only what
+// changed since the previous snapshot is appended: a new price, a new
+// quantity, or the entry disappearing. Prices are kept in cents.
+package history
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mtgban/go-mtgban/mtgban"
+)
+
+// Side selects which half of a scraper a series records, named like the
+// directories bantool writes them to.
+type Side string
+
+const (
+	Retail  Side = "retail"
+	Buylist Side = "buylist"
+)
+
+const fileExtension = ".hist"
+
+var (
+	// ErrNotNewer is returned when a snapshot is not more recent than the
+	// last one stored for the same scraper and side, as the log only grows
+	// forward in time.
+	ErrNotNewer = errors.New("snapshot is not newer than the stored ones")
+
+	// ErrMissingTimestamp is returned when a scraper is ingested before its
+	// side was ever loaded.
+	ErrMissingTimestamp = errors.New("snapshot has no timestamp")
+
+	// ErrNoData is returned by Stats when no snapshot in the window had the
+	// requested entry.
+	ErrNoData = errors.New("no data in range")
+)
+
+// Point is the price and quantity of an entry as of a snapshot.
+type Point struct {
+	Time     time.Time
+	Price    float64
+	Quantity int
+}
+
+// Stats summarizes the snapshots of a window that had an entry.
+type Stats struct {
+	Min     float64
+	Max     float64
+	Avg     float64
+	Samples int
+}
+
+// Store is a directory of series, one per scraper shorthand and side. It is
+// safe for concurrent use, and it keeps what it has read in memory, so a
+// store should be opened once and shared.
+type Store struct {
+	dir string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// Open returns the store rooted at dir, creating the directory if needed.
+// Series are read the first time they are used.
+func Open(dir string) (*Store, error) {
+	for _, side := range []Side{Retail, Buylist} {
+		err := os.MkdirAll(filepath.Join(dir, string(side)), 0o755)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Store{
+		dir:    dir,
+		series: map[string]*series{},
+	}, nil
+}
+
+// Close closes every series opened so far.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for key, ser := range s.series {
+		errs = append(errs, ser.close())
+		delete(s.series, key)
+	}
+	return errors.Join(errs...)
+}
+
+// IngestSeller appends the inventory of seller, under its shorthand and
+// inventory timestamp.
+func (s *Store) IngestSeller(seller mtgban.Seller) error {
+	info := seller.Info()
+	if info.InventoryTimestamp == nil {
+		return ErrMissingTimestamp
+	}
+	return s.AddInventory(info.Shorthand, *info.InventoryTimestamp, seller.Inventory())
+}
+
+// IngestVendor appends the buylist of vendor, under its shorthand and buylist
+// timestamp.
+func (s *Store) IngestVendor(vendor mtgban.Vendor) error {
+	info := vendor.Info()
+	if info.BuylistTimestamp == nil {
+		return ErrMissingTimestamp
+	}
+	return s.AddBuylist(info.Shorthand, *info.BuylistTimestamp, vendor.Buylist())
+}
+
+// AddInventory appends an inventory snapshot, keeping the lowest price of
+// each card and condition across sellers and the sum of their quantities.
+func (s *Store) AddInventory(shorthand string, ts time.Time, inventory mtgban.InventoryRecord) error {
+	return s.add(Retail, shorthand, ts, reduce(inventory, func(a, b float64) bool {
+		return a < b
+	}))
+}
+
+// AddBuylist appends a buylist snapshot, keeping the highest price of each
+// card and condition across vendors and the sum of their quantities.
+func (s *Store) AddBuylist(shorthand string, ts time.Time, buylist mtgban.BuylistRecord) error {
+	return s.add(Buylist, shorthand, ts, reduce(buylist, func(a, b float64) bool {
+		return a > b
+	}))
+}
+
+// PriceAt returns the point in effect at t: the one from the last snapshot
+// taken at or before t. It reports false when no such snapshot had the entry.
+func (s *Store) PriceAt(side Side, shorthand, cardID, conditions string, t time.Time) (Point, bool, error) {
+	ser, err := s.get(side, shorthand)
+	if err != nil {
+		return Point{}, false, err
+	}
+
+	ser.mu.Lock()
+	defer ser.mu.Unlock()
+
+	id, found := ser.keys[seriesKey(cardID, conditions)]
+	if !found {
+		return Point{}, false, nil
+	}
+	p, found := ser.at(id, t.UnixNano())
+	if !found {
+		return Point{}, false, nil
+	}
+	return p.toPoint(), true, nil
+}
+
+// Range returns the entry as seen by every snapshot taken between from and to
+// included, skipping the ones it was missing from.
+func (s *Store) Range(side Side, shorthand, cardID, conditions string, from, to time.Time) ([]Point, error) {
+	ser, err := s.get(side, shorthand)
+	if err != nil {
+		return nil, err
+	}
+
+	ser.mu.Lock()
+	defer ser.mu.Unlock()
+
+	id, found := ser.keys[seriesKey(cardID, conditions)]
+	if !found {
+		return nil, nil
+	}
+
+	start := sort.Search(len(ser.frames), func(i int) bool {
+		return ser.frames[i] >= from.UnixNano()
+	})
+
+	var result []Point
+	for _, ts := range ser.frames[start:] {
+		if ts > to.UnixNano() {
+			break
+		}
+		p, found := ser.at(id, ts)
+		if !found {
+			continue
+		}
+		result = append(result, p.toPoint())
+	}
+	return result, nil
+}
+
+// Stats returns the minimum, maximum and average price of the entry over the
+// snapshots between from and to included. The average is per snapshot, so a
+// price that held for longer weighs more.
+func (s *Store) Stats(side Side, shorthand, cardID, conditions string, from, to time.Time) (Stats, error) {
+	points, err := s.Range(side, shorthand, cardID, conditions, from, to)
+	if err != nil {
+		return Stats{}, err
+	}
+	if len(points) == 0 {
+		return Stats{}, ErrNoData
+	}
+
+	stats := Stats{
+		Min:     math.Inf(1),
+		Max:     math.Inf(-1),
+		Samples: len(points),
+	}
+	var sum float64
+	for _, p := range points {
+		stats.Min = math.Min(stats.Min, p.Price)
+		stats.Max = math.Max(stats.Max, p.Price)
+		sum += p.Price
+	}
+	stats.Avg = sum / float64(len(points))
+	return stats, nil
+}
+
+// Snapshots returns the timestamps of every snapshot stored for a scraper and
+// side, oldest first.
+func (s *Store) Snapshots(side Side, shorthand string) ([]time.Time, error) {
+	ser, err := s.get(side, shorthand)
+	if err != nil {
+		return nil, err
+	}
+
+	ser.mu.Lock()
+	defer ser.mu.Unlock()
+
+	result := make([]time.Time, 0, len(ser.frames))
+	for _, ts := range ser.frames {
+		result = append(result, time.Unix(0, ts))
+	}
+	return result, nil
+}
+
+// Keys returns the card ids a series has ever recorded, sorted.
+func (s *Store) Keys(side Side, shorthand string) ([]string, error) {
+	ser, err := s.get(side, shorthand)
+	if err != nil {
+		return nil, err
+	}
+
+	ser.mu.Lock()
+	defer ser.mu.Unlock()
+
+	var result []string
+	for _, name := range ser.names {
+		cardID, _, _ := strings.Cut(name, "\x00")
+		result = append(result, cardID)
+	}
+	slices.Sort(result)
+	return slices.Compact(result), nil
+}
+
+func (s *Store) add(side Side, shorthand string, ts time.Time, values map[string]point) error {
+	ser, err := s.get(side, shorthand)
+	if err != nil {
+		return err
+	}
+
+	ser.mu.Lock()
+	defer ser.mu.Unlock()
+
+	return ser.append(ts.UnixNano(), values)
+}
+
+func (s *Store) get(side Side, shorthand string) (*series, error) {
+	if side != Retail && side != Buylist {
+		return nil, fmt.Errorf("unknown side %q", side)
+	}
+	if shorthand == "" || strings.ContainsAny(shorthand, `/\`) || shorthand == "." || shorthand == ".." {
+		return nil, fmt.Errorf("invalid shorthand %q", shorthand)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := filepath.Join(s.dir, string(side), shorthand+fileExtension)
+	ser, found := s.series[name]
+	if found {
+		return ser, nil
+	}
+
+	ser, err := openSeries(name)
+	if err != nil {
+		return nil, err
+	}
+	s.series[name] = ser
+	return ser, nil
+}
+
+func seriesKey(cardID, conditions string) string {
+	if conditions == "" {
+		conditions = "NM"
+	}
+	return cardID + "\x00" + conditions
+}
+
+// reduce folds a record into one point per card and condition, keeping the
+// price better chooses and adding up the quantities.
+func reduce[E mtgban.GenericEntry](record map[string][]E, better func(a, b float64) bool) map[string]point {
+	values := map[string]point{}
+	for cardID, entries := range record {
+		for _, entry := range entries {
+			price := entry.Pricing()
+			if price <= 0 {
+				continue
+			}
+			key := seriesKey(cardID, entry.Condition())
+			cents := int64(math.Round(price * 100))
+
+			value, found := values[key]
+			if !found || better(float64(cents), float64(value.cents)) {
+				value.cents = cents
+			}
+			value.qty += int64(entry.Qty())
+			values[key] = value
+		}
+	}
+	return values
+}
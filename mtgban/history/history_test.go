@@ -0,0 +1,163 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mtgban/go-mtgban/mtgban"
+)
+
+var day0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func day(n int) time.Time {
+	return day0.Add(time.Duration(n) * 24 * time.Hour)
+}
+
+func fill(t *testing.T, store *Store) {
+	snapshots := []mtgban.InventoryRecord{
+		{
+			"a": {{Conditions: "NM", Price: 10, Quantity: 1, SellerName: "X"}, {Conditions: "NM", Price: 12, Quantity: 2, SellerName: "Y"}},
+			"b": {{Conditions: "SP", Price: 1, Quantity: 1}},
+		},
+		{
+			"a": {{Conditions: "NM", Price: 10, Quantity: 3}},
+		},
+		{
+			"a": {{Conditions: "NM", Price: 14, Quantity: 3}},
+			"b": {{Conditions: "SP", Price: 2, Quantity: 1}},
+		},
+	}
+	for i, inv := range snapshots {
+		err := store.AddInventory("TEST", day(i), inv)
+		if err != nil {
+			t.Fatalf("snapshot %d: %v", i, err)
+		}
+	}
+}
+
+func check(t *testing.T, store *Store) {
+	p, found, err := store.PriceAt(Retail, "TEST", "a", "NM", day(1).Add(time.Hour))
+	if err != nil || !found {
+		t.Fatalf("a missing at day 1: %v", err)
+	}
+	if p.Price != 10 || p.Quantity != 3 {
+		t.Errorf("unexpected point at day 1: %+v", p)
+	}
+
+	// The lowest price across sellers is kept, quantities add up
+	p, _, _ = store.PriceAt(Retail, "TEST", "a", "NM", day(0))
+	if p.Price != 10 || p.Quantity != 3 {
+		t.Errorf("unexpected point at day 0: %+v", p)
+	}
+
+	// b was missing from the second snapshot
+	_, found, _ = store.PriceAt(Retail, "TEST", "b", "SP", day(1))
+	if found {
+		t.Error("b found in a snapshot that did not have it")
+	}
+	_, found, _ = store.PriceAt(Retail, "TEST", "a", "NM", day(-1))
+	if found {
+		t.Error("a found before the first snapshot")
+	}
+
+	points, err := store.Range(Retail, "TEST", "b", "SP", day(0), day(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 || points[0].Price != 1 || points[1].Price != 2 {
+		t.Errorf("unexpected range for b: %+v", points)
+	}
+
+	stats, err := store.Stats(Retail, "TEST", "a", "NM", day(0), day(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Min != 10 || stats.Max != 14 || stats.Samples != 3 || stats.Avg != 34.0/3 {
+		t.Errorf("unexpected stats for a: %+v", stats)
+	}
+
+	_, err = store.Stats(Retail, "TEST", "a", "NM", day(5), day(6))
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("expected no data past the last snapshot, got %v", err)
+	}
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(t, store)
+	check(t, store)
+
+	err = store.AddInventory("TEST", day(1), mtgban.InventoryRecord{})
+	if !errors.Is(err, ErrNotNewer) {
+		t.Errorf("an older snapshot was accepted: %v", err)
+	}
+
+	err = store.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Everything survives a reopen
+	store, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	check(t, store)
+
+	snapshots, err := store.Snapshots(Retail, "TEST")
+	if err != nil || len(snapshots) != 3 {
+		t.Errorf("expected three snapshots, got %v (%v)", snapshots, err)
+	}
+}
+
+func TestStoreDropsTornRecord(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(t, store)
+	store.Close()
+
+	name := filepath.Join(dir, string(Retail), "TEST"+fileExtension)
+	info, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Chop the last frame in half, as a crash mid-write would
+	err = os.Truncate(name, info.Size()-3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	snapshots, _ := store.Snapshots(Retail, "TEST")
+	if len(snapshots) != 2 {
+		t.Fatalf("expected the torn snapshot to be dropped, got %d", len(snapshots))
+	}
+	err = store.AddInventory("TEST", day(2), mtgban.InventoryRecord{
+		"a": {{Conditions: "NM", Price: 14, Quantity: 3}},
+	})
+	if err != nil {
+		t.Fatalf("the torn snapshot could not be written again: %v", err)
+	}
+	p, _, _ := store.PriceAt(Retail, "TEST", "a", "NM", day(2))
+	if p.Price != 14 {
+		t.Errorf("unexpected point after rewrite: %+v", p)
+	}
+}
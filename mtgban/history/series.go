@@ -0,0 +1,328 @@
+package history
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// The log starts with fileMagic and is followed by records, each introduced
+// by its kind:
+//
+//	'K' uvarint(len) key               a new card and condition, numbered in
+//	                                   order of appearance
+//	'F' varint(ts) uvarint(n) changes  a snapshot taken at ts (unix nanos),
+//	                                   with n changes of the form
+//	                                   uvarint(key) varint(cents) uvarint(qty)
+//
+// A change with negative cents marks the entry as gone from that snapshot.
+// Records are written whole, so a torn one can only be at the end of the
+// file, where it is dropped the next time the series is opened.
+const fileMagic = "BANHIST\x01"
+
+const (
+	recordKey   = 'K'
+	recordFrame = 'F'
+)
+
+var errTruncated = errors.New("truncated record")
+
+type point struct {
+	ts    int64
+	cents int64
+	qty   int64
+}
+
+func (p point) gone() bool {
+	return p.cents < 0
+}
+
+func (p point) toPoint() Point {
+	return Point{
+		Time:     time.Unix(0, p.ts),
+		Price:    float64(p.cents) / 100,
+		Quantity: int(p.qty),
+	}
+}
+
+type series struct {
+	mu   sync.Mutex
+	file *os.File
+
+	// Key ids, and their names in id order
+	keys  map[string]uint64
+	names []string
+
+	// Timestamps of every snapshot, in order
+	frames []int64
+
+	// Changes of every key, in order, indexed by key id
+	history [][]point
+}
+
+func openSeries(name string) (*series, error) {
+	file, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	ser := &series{
+		file: file,
+		keys: map[string]uint64{},
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		_, err = file.Write([]byte(fileMagic))
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return ser, nil
+	}
+
+	if len(data) < len(fileMagic) || string(data[:len(fileMagic)]) != fileMagic {
+		file.Close()
+		return nil, fmt.Errorf("%s is not a history file", name)
+	}
+
+	good, err := ser.parse(data)
+	if err != nil && !errors.Is(err, errTruncated) {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if good != int64(len(data)) {
+		err = file.Truncate(good)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		_, err = file.Seek(good, io.SeekStart)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	return ser, nil
+}
+
+// parse loads every complete record, returning the offset where the last one
+// ended.
+func (ser *series) parse(data []byte) (int64, error) {
+	offset := len(fileMagic)
+	for offset < len(data) {
+		next, err := ser.parseRecord(data, offset)
+		if err != nil {
+			return int64(offset), err
+		}
+		offset = next
+	}
+	return int64(offset), nil
+}
+
+func (ser *series) parseRecord(data []byte, offset int) (int, error) {
+	kind := data[offset]
+	offset++
+
+	uvarint := func() (uint64, error) {
+		v, n := binary.Uvarint(data[offset:])
+		if n <= 0 {
+			return 0, errTruncated
+		}
+		offset += n
+		return v, nil
+	}
+	varint := func() (int64, error) {
+		v, n := binary.Varint(data[offset:])
+		if n <= 0 {
+			return 0, errTruncated
+		}
+		offset += n
+		return v, nil
+	}
+
+	switch kind {
+	case recordKey:
+		size, err := uvarint()
+		if err != nil {
+			return 0, err
+		}
+		if uint64(len(data)-offset) < size {
+			return 0, errTruncated
+		}
+		ser.addKey(string(data[offset : offset+int(size)]))
+		offset += int(size)
+	case recordFrame:
+		ts, err := varint()
+		if err != nil {
+			return 0, err
+		}
+		count, err := uvarint()
+		if err != nil {
+			return 0, err
+		}
+		changes := make([]point, 0, count)
+		ids := make([]uint64, 0, count)
+		for i := uint64(0); i < count; i++ {
+			id, err := uvarint()
+			if err != nil {
+				return 0, err
+			}
+			if id >= uint64(len(ser.names)) {
+				return 0, fmt.Errorf("unknown key %d", id)
+			}
+			cents, err := varint()
+			if err != nil {
+				return 0, err
+			}
+			qty, err := uvarint()
+			if err != nil {
+				return 0, err
+			}
+			ids = append(ids, id)
+			changes = append(changes, point{ts: ts, cents: cents, qty: int64(qty)})
+		}
+		// Only apply the frame once it was read whole
+		for i, id := range ids {
+			ser.history[id] = append(ser.history[id], changes[i])
+		}
+		ser.frames = append(ser.frames, ts)
+	default:
+		return 0, fmt.Errorf("unknown record kind %q", kind)
+	}
+
+	return offset, nil
+}
+
+func (ser *series) addKey(name string) uint64 {
+	id := uint64(len(ser.names))
+	ser.keys[name] = id
+	ser.names = append(ser.names, name)
+	ser.history = append(ser.history, nil)
+	return id
+}
+
+func (ser *series) last(id uint64) (point, bool) {
+	if id >= uint64(len(ser.history)) || len(ser.history[id]) == 0 {
+		return point{}, false
+	}
+	return ser.history[id][len(ser.history[id])-1], true
+}
+
+// at returns the point of id in effect at ts.
+func (ser *series) at(id uint64, ts int64) (point, bool) {
+	changes := ser.history[id]
+	i := sort.Search(len(changes), func(i int) bool {
+		return changes[i].ts > ts
+	})
+	if i == 0 || changes[i-1].gone() {
+		return point{}, false
+	}
+	return changes[i-1], true
+}
+
+// append writes the changes between the last snapshot and values, then
+// applies them in memory once the write went through.
+func (ser *series) append(ts int64, values map[string]point) error {
+	if len(ser.frames) > 0 && ts <= ser.frames[len(ser.frames)-1] {
+		return ErrNotNewer
+	}
+
+	var buf []byte
+
+	// Name the keys this snapshot is the first to carry
+	var newNames []string
+	newIDs := map[string]uint64{}
+	for name := range values {
+		_, found := ser.keys[name]
+		if !found {
+			newNames = append(newNames, name)
+		}
+	}
+	sort.Strings(newNames)
+	for i, name := range newNames {
+		newIDs[name] = uint64(len(ser.names) + i)
+		buf = append(buf, recordKey)
+		buf = binary.AppendUvarint(buf, uint64(len(name)))
+		buf = append(buf, name...)
+	}
+
+	type change struct {
+		id uint64
+		p  point
+	}
+	var changes []change
+	for name, value := range values {
+		id, found := ser.keys[name]
+		if !found {
+			id = newIDs[name]
+		}
+		value.ts = ts
+		prev, found := ser.last(id)
+		if found && !prev.gone() && prev.cents == value.cents && prev.qty == value.qty {
+			continue
+		}
+		changes = append(changes, change{id, value})
+	}
+	for id, name := range ser.names {
+		_, found := values[name]
+		if found {
+			continue
+		}
+		prev, found := ser.last(uint64(id))
+		if !found || prev.gone() {
+			continue
+		}
+		changes = append(changes, change{uint64(id), point{ts: ts, cents: -1}})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].id < changes[j].id
+	})
+
+	buf = append(buf, recordFrame)
+	buf = binary.AppendVarint(buf, ts)
+	buf = binary.AppendUvarint(buf, uint64(len(changes)))
+	for _, c := range changes {
+		buf = binary.AppendUvarint(buf, c.id)
+		buf = binary.AppendVarint(buf, c.p.cents)
+		buf = binary.AppendUvarint(buf, uint64(c.p.qty))
+	}
+
+	// Drop whatever part of a failed write made it to disk, or the next
+	// record would be appended after a torn one
+	offset, err := ser.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	_, err = ser.file.Write(buf)
+	if err != nil {
+		ser.file.Truncate(offset)
+		ser.file.Seek(offset, io.SeekStart)
+		return err
+	}
+
+	for _, name := range newNames {
+		ser.addKey(name)
+	}
+	for _, c := range changes {
+		ser.history[c.id] = append(ser.history[c.id], c.p)
+	}
+	ser.frames = append(ser.frames, ts)
+
+	return nil
+}
+
+func (ser *series) close() error {
+	return ser.file.Close()
+}
@@ -2,13 +2,17 @@ package mtgban
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"strings"
 )
 
 // scraperJSON is the on-disk shape every Write function emits and every Read
 // function accepts. Both price sides are optional so one file can hold either
 // or both.
 type scraperJSON struct {
+	Version   int             `json:"version"`
 	Info      ScraperInfo     `json:"info"`
 	Inventory InventoryRecord `json:"inventory,omitempty"`
 	Buylist   BuylistRecord   `json:"buylist,omitempty"`
@@ -29,6 +33,7 @@ func WriteScraperToJSON(scraper Scraper, w io.Writer) error {
 	if isVendor {
 		data.Buylist = vendor.Buylist()
 	}
+	data.Version = DumpVersion()
 	data.Info = scraper.Info()
 
 	if len(data.Inventory) == 0 {
@@ -46,6 +51,7 @@ func WriteScraperToJSON(scraper Scraper, w io.Writer) error {
 func WriteSellerToJSON(seller Seller, w io.Writer) error {
 	var data scraperJSON
 
+	data.Version = DumpVersion()
 	data.Inventory = seller.Inventory()
 	data.Info = seller.Info()
 	data.Info.BuylistTimestamp = nil
@@ -58,6 +64,7 @@ func WriteSellerToJSON(seller Seller, w io.Writer) error {
 func WriteVendorToJSON(vendor Vendor, w io.Writer) error {
 	var data scraperJSON
 
+	data.Version = DumpVersion()
 	data.Buylist = vendor.Buylist()
 	data.Info = vendor.Info()
 	data.Info.InventoryTimestamp = nil
@@ -65,13 +72,12 @@ func WriteVendorToJSON(vendor Vendor, w io.Writer) error {
 	return json.NewEncoder(w).Encode(&data)
 }
 
-// ReadSellerFromJSON rebuilds a seller from what the Write functions emit.
-// The result carries prices and the info it was written with, not the scraper
-// that produced them: it never reaches the network and Load is a no-op.
+// ReadSellerFromJSON rebuilds a seller from what the Write functions emit,
+// upgrading the dumps of older versions first. The result carries prices and
+// the info it was written with, not the scraper that produced them: it never
+// reaches the network and Load is a no-op.
 func ReadSellerFromJSON(r io.Reader) (Seller, error) {
-	var data scraperJSON
-
-	err := json.NewDecoder(r).Decode(&data)
+	data, err := readDump(r)
 	if err != nil {
 		return nil, err
 	}
@@ -83,12 +89,142 @@ func ReadSellerFromJSON(r io.Reader) (Seller, error) {
 // with the same caveat as ReadSellerFromJSON: prices only, no scraper behind
 // them.
 func ReadVendorFromJSON(r io.Reader) (Vendor, error) {
+	data, err := readDump(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewVendorFromBuylist(data.Buylist, data.Info), nil
+}
+
+// DumpMigration upgrades the top-level object of a dump by one version,
+// rewriting its fields in place.
+type DumpMigration func(dump map[string]json.RawMessage) error
+
+// dumpMigrations holds the migration from every past version of the dump
+// shape to the next one, in order: the migration at index i upgrades version
+// i to version i+1, so the current version is the number of migrations.
+// A change to the shape of ScraperInfo or of the entries that older dumps
+// would not decode into as they are needs a migration appended here.
+var dumpMigrations = []DumpMigration{
+	// Dumps written before versioning have no "version" field, so they
+	// decode as version 0, and have the same shape as version 1
+	func(dump map[string]json.RawMessage) error {
+		return nil
+	},
+}
+
+// DumpVersion returns the version of the shape the Write functions emit.
+func DumpVersion() int {
+	return len(dumpMigrations)
+}
+
+// readDump decodes a dump of any version up to the current one, upgrading
+// it through dumpMigrations when it is older. The top-level object is walked
+// one field at a time, so that once the version is known to be the current
+// one every field decodes into its place straight from the stream, and only
+// the fields of older dumps are held raw until they are migrated.
+func readDump(r io.Reader) (*scraperJSON, error) {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, errors.New("dump is not a json object")
+	}
+
 	var data scraperJSON
+	dump := map[string]json.RawMessage{}
+	current := false
+	for dec.More() {
+		tok, err = dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		key = dumpFieldName(key)
+
+		switch {
+		case key == "version":
+			err = dec.Decode(&data.Version)
+			if err != nil {
+				return nil, fmt.Errorf("invalid dump version: %v", err)
+			}
+			current = data.Version == DumpVersion()
+		case current:
+			err = decodeDumpField(&data, key, dec.Decode)
+		default:
+			var raw json.RawMessage
+			err = dec.Decode(&raw)
+			dump[key] = raw
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = dec.Token()
+	if err != nil {
+		return nil, err
+	}
 
-	err := json.NewDecoder(r).Decode(&data)
+	// Dumps written before versioning have no "version" field at all
+	err = migrateDump(dump, data.Version)
 	if err != nil {
 		return nil, err
 	}
+	data.Version = DumpVersion()
+
+	for key, raw := range dump {
+		err = decodeDumpField(&data, key, func(v any) error {
+			return json.Unmarshal(raw, v)
+		})
+		if err != nil {
+			return nil, err
+		}
+		delete(dump, key)
+	}
+	return &data, nil
+}
 
-	return NewVendorFromBuylist(data.Buylist, data.Info), nil
+// migrateDump upgrades the top-level fields of a dump of the given version to
+// the current one.
+func migrateDump(dump map[string]json.RawMessage, version int) error {
+	if version < 0 || version > DumpVersion() {
+		return fmt.Errorf("unsupported dump version %d, expected up to %d", version, DumpVersion())
+	}
+	for ; version < DumpVersion(); version++ {
+		err := dumpMigrations[version](dump)
+		if err != nil {
+			return fmt.Errorf("migrating dump from version %d: %v", version, err)
+		}
+	}
+	return nil
+}
+
+// dumpFieldName returns the name of the top-level field of a dump key stands
+// for, matching it regardless of case as json.Unmarshal would, so that the
+// migrations find every field under its own name.
+func dumpFieldName(key string) string {
+	for _, name := range []string{"version", "info", "inventory", "buylist"} {
+		if strings.EqualFold(key, name) {
+			return name
+		}
+	}
+	return key
+}
+
+// decodeDumpField decodes the field of the top-level object of a dump named
+// key into data, skipping the ones it does not know as json.Unmarshal would.
+func decodeDumpField(data *scraperJSON, key string, decode func(v any) error) error {
+	switch dumpFieldName(key) {
+	case "info":
+		return decode(&data.Info)
+	case "inventory":
+		return decode(&data.Inventory)
+	case "buylist":
+		return decode(&data.Buylist)
+	}
+	var skip json.RawMessage
+	return decode(&skip)
 }
@@ -0,0 +1,263 @@
+package mtgban
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// goldenScraper is what every fixture in testdata holds, save for the
+// currency fields the oldest dumps predate.
+func goldenScraper(currency bool) (InventoryRecord, BuylistRecord, ScraperInfo) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	inventory := InventoryRecord{
+		"x|A|SET|1": {
+			{Quantity: 2, Conditions: "NM", Price: 2.5, URL: "https://example.com/a", SellerName: "S"},
+			{Quantity: 1, Conditions: "SP", Price: 1.75, URL: "https://example.com/a"},
+		},
+	}
+	buylist := BuylistRecord{
+		"x|A|SET|1": {
+			{Quantity: 4, Conditions: "NM", BuyPrice: 1.25, PriceRatio: 50, URL: "https://example.com/buy/a", VendorName: "V"},
+		},
+	}
+	info := ScraperInfo{
+		Name:               "Test Store",
+		Shorthand:          "TST",
+		CountryFlag:        "EU",
+		InventoryTimestamp: &ts,
+		BuylistTimestamp:   &ts,
+		CreditMultiplier:   1.3,
+	}
+	if currency {
+		inventory["x|A|SET|1"][0].Currency, inventory["x|A|SET|1"][0].NativePrice = "EUR", 2.27
+		inventory["x|A|SET|1"][1].Currency, inventory["x|A|SET|1"][1].NativePrice = "EUR", 1.59
+		buylist["x|A|SET|1"][0].Currency, buylist["x|A|SET|1"][0].NativePrice = "EUR", 1.14
+		info.Currency = "EUR"
+		info.ExchangeRates = map[string]float64{"eur": 1.1}
+	}
+	return inventory, buylist, info
+}
+
+func TestReadDumpVersions(t *testing.T) {
+	tests := []struct {
+		file     string
+		currency bool
+	}{
+		// Written before the currency fields and before versioning
+		{"testdata/dump_v0.json", false},
+		// Written with the currency fields, still unversioned
+		{"testdata/dump_v0_currency.json", true},
+		// The current shape
+		{"testdata/dump_v1.json", true},
+	}
+
+	for _, test := range tests {
+		data, err := os.ReadFile(test.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inventory, buylist, info := goldenScraper(test.currency)
+
+		seller, err := ReadSellerFromJSON(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %v", test.file, err)
+		}
+		if !reflect.DeepEqual(seller.Inventory(), inventory) {
+			t.Errorf("%s: expected %v, got %v", test.file, inventory, seller.Inventory())
+		}
+		if !reflect.DeepEqual(seller.Info(), info) {
+			t.Errorf("%s: expected %+v, got %+v", test.file, info, seller.Info())
+		}
+
+		vendor, err := ReadVendorFromJSON(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %v", test.file, err)
+		}
+		if !reflect.DeepEqual(vendor.Buylist(), buylist) {
+			t.Errorf("%s: expected %v, got %v", test.file, buylist, vendor.Buylist())
+		}
+	}
+}
+
+func TestWriteDumpGolden(t *testing.T) {
+	golden, err := os.ReadFile("testdata/dump_v1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inventory, buylist, info := goldenScraper(true)
+
+	var buf bytes.Buffer
+	err = WriteScraperToJSON(&testMarket{NewSellerFromInventory(inventory, info), buylist}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), golden) {
+		t.Errorf("the current shape changed, add a migration and a fixture:\n%s", buf.String())
+	}
+}
+
+// addTestMigration appends a version renaming the shorthand field of the
+// info for the duration of the test.
+func addTestMigration(t *testing.T) {
+	migrations := dumpMigrations
+	t.Cleanup(func() {
+		dumpMigrations = migrations
+	})
+	dumpMigrations = append(dumpMigrations[:len(dumpMigrations):len(dumpMigrations)], func(dump map[string]json.RawMessage) error {
+		var info map[string]json.RawMessage
+		err := json.Unmarshal(dump["info"], &info)
+		if err != nil {
+			return err
+		}
+		info["shorthand"] = json.RawMessage(`"MIGRATED"`)
+		dump["info"], err = json.Marshal(info)
+		return err
+	})
+}
+
+func TestDumpMigrations(t *testing.T) {
+	addTestMigration(t)
+
+	for _, file := range []string{"testdata/dump_v0.json", "testdata/dump_v1.json"} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seller, err := ReadSellerFromJSON(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if seller.Info().Shorthand != "MIGRATED" || len(seller.Inventory()) != 1 {
+			t.Errorf("%s: not migrated: %+v", file, seller.Info())
+		}
+	}
+
+	// Dumps of the version being migrated to are left alone
+	var buf bytes.Buffer
+	err := WriteSellerToJSON(NewSellerFromInventory(nil, ScraperInfo{Shorthand: "TST"}), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seller, err := ReadSellerFromJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seller.Info().Shorthand != "TST" {
+		t.Errorf("a current dump was migrated: %+v", seller.Info())
+	}
+}
+
+func TestDumpMigrationRewritesEntries(t *testing.T) {
+	// A version renaming the condition field of every entry, which the
+	// fixture predates
+	migrations := dumpMigrations
+	t.Cleanup(func() {
+		dumpMigrations = migrations
+	})
+	dumpMigrations = append(dumpMigrations[:len(dumpMigrations):len(dumpMigrations)], func(dump map[string]json.RawMessage) error {
+		for _, side := range []string{"inventory", "buylist"} {
+			var record map[string][]map[string]json.RawMessage
+			err := json.Unmarshal(dump[side], &record)
+			if err != nil {
+				return err
+			}
+			for _, entries := range record {
+				for _, entry := range entries {
+					entry["conditions"] = entry["condition"]
+					delete(entry, "condition")
+				}
+			}
+			dump[side], err = json.Marshal(record)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	data, err := os.ReadFile("testdata/dump_v1_condition.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inventory, buylist, _ := goldenScraper(true)
+
+	seller, err := ReadSellerFromJSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(seller.Inventory(), inventory) {
+		t.Errorf("expected %v, got %v", inventory, seller.Inventory())
+	}
+
+	stream := NewBuylistStream(bytes.NewReader(data), nil)
+	got := BuylistRecord{}
+	for cardID, entries := range stream.All() {
+		got[cardID] = entries
+	}
+	if stream.Err() != nil {
+		t.Fatal(stream.Err())
+	}
+	if !reflect.DeepEqual(got, buylist) {
+		t.Errorf("expected %v, got %v", buylist, got)
+	}
+}
+
+func TestReadDumpFieldCase(t *testing.T) {
+	// Field names match regardless of case, as encoding/json does
+	dump := fmt.Sprintf(`{"Version":%d,"INFO":{"shorthand":"TST"},"Inventory":{"A":[{"conditions":"NM","price":1}]}}`, DumpVersion())
+	seller, err := ReadSellerFromJSON(strings.NewReader(dump))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seller.Info().Shorthand != "TST" || len(seller.Inventory()["A"]) != 1 {
+		t.Errorf("fields were lost: %+v %v", seller.Info(), seller.Inventory())
+	}
+
+	stream := NewInventoryStream(strings.NewReader(dump), nil)
+	count := 0
+	for range stream.All() {
+		count++
+	}
+	if stream.Err() != nil || count != 1 || stream.Info().Shorthand != "TST" {
+		t.Errorf("fields were lost: %d %+v %v", count, stream.Info(), stream.Err())
+	}
+}
+
+func TestReadDumpNewerVersion(t *testing.T) {
+	_, err := ReadSellerFromJSON(strings.NewReader(`{"version":999,"info":{"shorthand":"TST"}}`))
+	if err == nil {
+		t.Error("a dump from the future was read")
+	}
+
+	stream := NewInventoryStream(strings.NewReader(`{"version":999,"info":{},"inventory":{"A":[{"conditions":"NM"}]}}`), nil)
+	for range stream.All() {
+		t.Error("a stream from the future yielded")
+	}
+	if stream.Err() == nil {
+		t.Error("a stream from the future was read")
+	}
+}
+
+func TestReadDumpFieldOrder(t *testing.T) {
+	// The version is read wherever it is, and fields read before it are
+	// still decoded
+	dump := fmt.Sprintf(`{"info":{"shorthand":"TST"},"inventory":{"A":[{"conditions":"NM","price":1}]},"extra":[1],"version":%d}`, DumpVersion())
+	seller, err := ReadSellerFromJSON(strings.NewReader(dump))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seller.Info().Shorthand != "TST" || len(seller.Inventory()["A"]) != 1 {
+		t.Errorf("fields before the version were lost: %+v %v", seller.Info(), seller.Inventory())
+	}
+
+	_, err = ReadSellerFromJSON(strings.NewReader(`[]`))
+	if err == nil {
+		t.Error("a dump that is not an object was read")
+	}
+}
@@ -0,0 +1,200 @@
+package mtgban
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+)
+
+// DefaultMatchSamples is how many inputs a MatchStats keeps for each class of
+// error when MaxSamples is not set.
+const DefaultMatchSamples = 10
+
+// MatchReporter is implemented by the scrapers that measure how well their
+// listings are matched.
+type MatchReporter interface {
+	// Retrieve the outcomes of the matches run so far
+	MatchStats() *MatchStats
+}
+
+// MatchStats collects the outcome of every mtgmatcher.Match call of a
+// scraper, in place of free text logs. The zero value is ready to use, and
+// it is safe for concurrent use.
+type MatchStats struct {
+	// Maximum number of inputs kept for each class of error
+	MaxSamples int
+
+	mu     sync.Mutex
+	report MatchReport
+}
+
+// MatchReport is a snapshot of a MatchStats, ready to be serialized.
+type MatchReport struct {
+	// Number of inputs matched
+	Matched int `json:"matched"`
+
+	// Number of inputs reported as unsupported, skipped on purpose
+	Unsupported int `json:"unsupported"`
+
+	// Number of inputs that could not be matched
+	Failed int `json:"failed"`
+
+	// Failures by class of error, as named by MatchErrorClass
+	Classes map[string]*MatchClass `json:"classes,omitempty"`
+
+	// Failures by edition of the input, across all classes
+	Editions map[string]int `json:"editions,omitempty"`
+}
+
+// MatchClass describes the failures sharing a class of error.
+type MatchClass struct {
+	Count int `json:"count"`
+
+	// The first inputs that failed this way
+	Samples []MatchSample `json:"samples,omitempty"`
+
+	// Failures by edition of the input
+	Editions map[string]int `json:"editions,omitempty"`
+}
+
+// MatchSample is one input that could not be matched.
+type MatchSample struct {
+	Input mtgmatcher.InputCard `json:"input"`
+	Error string               `json:"error"`
+}
+
+// MatchErrorClass names the class of an error returned by mtgmatcher.Match,
+// "other" for the ones that are not part of its API.
+func MatchErrorClass(err error) string {
+	var alias *mtgmatcher.AliasingError
+	switch {
+	case errors.Is(err, mtgmatcher.ErrUnsupported):
+		return "unsupported"
+	case errors.As(err, &alias):
+		return "aliasing"
+	case errors.Is(err, mtgmatcher.ErrCardDoesNotExist):
+		return "card_does_not_exist"
+	case errors.Is(err, mtgmatcher.ErrCardNotInEdition):
+		return "card_not_in_edition"
+	case errors.Is(err, mtgmatcher.ErrCardWrongVariant):
+		return "card_wrong_variant"
+	case errors.Is(err, mtgmatcher.ErrCardMissingVariant):
+		return "card_missing_variant"
+	case errors.Is(err, mtgmatcher.ErrCardWrongFinish):
+		return "card_wrong_finish"
+	case errors.Is(err, mtgmatcher.ErrCardUnnamedFinish):
+		return "card_unnamed_finish"
+	case errors.Is(err, mtgmatcher.ErrCardUnknownID):
+		return "card_unknown_id"
+	case errors.Is(err, mtgmatcher.ErrDatastoreEmpty):
+		return "datastore_empty"
+	}
+	return "other"
+}
+
+// Match calls mtgmatcher.Match and records its outcome. The input is recorded
+// as it was passed, before Match adjusts it. A nil MatchStats records nothing.
+func (ms *MatchStats) Match(card *mtgmatcher.InputCard) (string, error) {
+	if ms == nil {
+		return mtgmatcher.Match(card)
+	}
+	input := *card
+	cardID, err := mtgmatcher.Match(card)
+	ms.Record(&input, err)
+	return cardID, err
+}
+
+// Record adds the outcome of matching card, for the callers that retry a
+// failed match before settling on a result.
+func (ms *MatchStats) Record(card *mtgmatcher.InputCard, err error) {
+	if ms == nil {
+		return
+	}
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	report := &ms.report
+	if err == nil {
+		report.Matched++
+		return
+	}
+	class := MatchErrorClass(err)
+	if class == "unsupported" {
+		report.Unsupported++
+		return
+	}
+	report.Failed++
+
+	if report.Classes == nil {
+		report.Classes = map[string]*MatchClass{}
+		report.Editions = map[string]int{}
+	}
+	report.Editions[card.Edition]++
+
+	mc := report.Classes[class]
+	if mc == nil {
+		mc = &MatchClass{
+			Editions: map[string]int{},
+		}
+		report.Classes[class] = mc
+	}
+	mc.Count++
+	mc.Editions[card.Edition]++
+
+	maxSamples := ms.MaxSamples
+	if maxSamples == 0 {
+		maxSamples = DefaultMatchSamples
+	}
+	if len(mc.Samples) < maxSamples {
+		mc.Samples = append(mc.Samples, MatchSample{
+			Input: *card,
+			Error: err.Error(),
+		})
+	}
+}
+
+// Report returns a copy of what was recorded so far, an empty one for a nil
+// MatchStats.
+func (ms *MatchStats) Report() MatchReport {
+	if ms == nil {
+		return MatchReport{}
+	}
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	report := ms.report
+	report.Editions = maps.Clone(report.Editions)
+	if report.Classes != nil {
+		report.Classes = make(map[string]*MatchClass, len(ms.report.Classes))
+		for class, mc := range ms.report.Classes {
+			report.Classes[class] = &MatchClass{
+				Count:    mc.Count,
+				Samples:  slices.Clone(mc.Samples),
+				Editions: maps.Clone(mc.Editions),
+			}
+		}
+	}
+	return report
+}
+
+// Reset drops what was recorded so far. A nil MatchStats has nothing to drop.
+func (ms *MatchStats) Reset() {
+	if ms == nil {
+		return
+	}
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.report = MatchReport{}
+}
+
+// WriteMatchReportToJSON writes the report of a MatchStats, an empty one when
+// stats is nil.
+func WriteMatchReportToJSON(stats *MatchStats, w io.Writer) error {
+	report := stats.Report()
+	return json.NewEncoder(w).Encode(&report)
+}
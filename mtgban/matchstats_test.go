@@ -0,0 +1,92 @@
+package mtgban
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+)
+
+func TestMatchStats(t *testing.T) {
+	stats := MatchStats{MaxSamples: 2}
+
+	stats.Record(&mtgmatcher.InputCard{Name: "Ok", Edition: "Alpha"}, nil)
+	stats.Record(&mtgmatcher.InputCard{Name: "Token", Edition: "Alpha"}, mtgmatcher.ErrUnsupported)
+	for i := 0; i < 3; i++ {
+		stats.Record(&mtgmatcher.InputCard{Name: fmt.Sprint("Missing", i), Edition: "Beta"}, mtgmatcher.ErrCardDoesNotExist)
+	}
+	stats.Record(&mtgmatcher.InputCard{Name: "Twin", Edition: "Alpha"}, mtgmatcher.NewAliasingError("a", "b"))
+	stats.Record(&mtgmatcher.InputCard{Name: "Odd", Edition: "Alpha"}, fmt.Errorf("wrapped: %w", mtgmatcher.ErrCardWrongVariant))
+
+	report := stats.Report()
+	if report.Matched != 1 || report.Unsupported != 1 || report.Failed != 5 {
+		t.Errorf("unexpected counts %+v", report)
+	}
+	if report.Editions["Beta"] != 3 || report.Editions["Alpha"] != 2 {
+		t.Errorf("unexpected editions %v", report.Editions)
+	}
+
+	missing := report.Classes["card_does_not_exist"]
+	if missing == nil || missing.Count != 3 || len(missing.Samples) != 2 || missing.Samples[0].Input.Name != "Missing0" {
+		t.Errorf("unexpected class %+v", missing)
+	}
+	if report.Classes["aliasing"] == nil || report.Classes["card_wrong_variant"] == nil {
+		t.Errorf("unexpected classes %v", report.Classes)
+	}
+
+	// The report is a copy
+	missing.Samples[0].Input.Name = "changed"
+	if stats.Report().Classes["card_does_not_exist"].Samples[0].Input.Name != "Missing0" {
+		t.Error("the report shares its samples with the stats")
+	}
+
+	var buf bytes.Buffer
+	err := WriteMatchReportToJSON(&stats, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded MatchReport
+	err = json.Unmarshal(buf.Bytes(), &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Failed != 5 || decoded.Classes["card_does_not_exist"].Editions["Beta"] != 3 {
+		t.Errorf("unexpected decoded report %+v", decoded)
+	}
+
+	stats.Reset()
+	if stats.Report().Failed != 0 {
+		t.Error("stats not reset")
+	}
+
+	// Nothing to record into
+	var none *MatchStats
+	none.Record(&mtgmatcher.InputCard{}, mtgmatcher.ErrCardDoesNotExist)
+	none.Reset()
+	if report := none.Report(); report.Matched != 0 || report.Classes != nil {
+		t.Errorf("a nil MatchStats reported %+v", report)
+	}
+	buf.Reset()
+	err = WriteMatchReportToJSON(none, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatchErrorClass(t *testing.T) {
+	tests := map[error]string{
+		mtgmatcher.ErrUnsupported:                        "unsupported",
+		mtgmatcher.NewAliasingError():                    "aliasing",
+		fmt.Errorf("%w", mtgmatcher.ErrCardNotInEdition): "card_not_in_edition",
+		mtgmatcher.ErrCardUnnamedFinish:                  "card_unnamed_finish",
+		mtgmatcher.ErrDatastoreEmpty:                     "datastore_empty",
+		fmt.Errorf("something else entirely"):            "other",
+	}
+	for err, class := range tests {
+		if got := MatchErrorClass(err); got != class {
+			t.Errorf("%v: expected %s, got %s", err, class, got)
+		}
+	}
+}
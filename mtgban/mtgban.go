@@ -50,6 +50,12 @@ type InventoryEntry struct {
 
 	// Any additional extra floating point values
 	ExtraValues map[string]float64 `json:"extra_values,omitempty"`
+
+	// Currency the price was originally quoted in, when not USD
+	Currency string `json:"currency,omitempty"`
+
+	// The price in the original currency, before conversion to USD
+	NativePrice float64 `json:"native_price,omitempty"`
 }
 
 // Pricing returns the asking price, satisfying GenericEntry so that code
@@ -101,6 +107,12 @@ type BuylistEntry struct {
 
 	// Any additional custom fields set by the scraper
 	CustomFields map[string]string `json:"custom_fields,omitempty"`
+
+	// Currency the price was originally quoted in, when not USD
+	Currency string `json:"currency,omitempty"`
+
+	// The buy price in the original currency, before conversion to USD
+	NativePrice float64 `json:"native_price,omitempty"`
 }
 
 // Pricing returns the price paid, not the price asked: the buylist half of
@@ -166,11 +178,19 @@ type ScraperInfo struct {
 	// Scraper contains sealed information instead of singles
 	SealedMode bool `json:"sealed,omitempty"`
 
-	// Whether the prices can be coalesced in a single entity
+	// Whether the prices can be coalesced in a single entity, see
+	// CoalesceScrapers
 	Family string `json:"family,omitempty"`
 
 	// Which game the scraper belongs to
 	Game string `json:"game,omitempty"`
+
+	// Currency the store quotes its prices in, when not USD
+	Currency string `json:"currency,omitempty"`
+
+	// The rates that converted native prices to USD at scrape time, keyed
+	// by lower case currency as GetExchangeRates returns them
+	ExchangeRates map[string]float64 `json:"exchange_rates,omitempty"`
 }
 
 // DefaultGradeTags are the conditions most scrapers report.
@@ -270,6 +290,10 @@ type Vendor interface {
 type ScraperOptions struct {
 	DisableRetail  bool
 	DisableBuylist bool
+
+	// Directory where the scrapers built on CheckpointedWorkerPool keep their
+	// progress, resuming from it on the next load. Empty disables it.
+	CheckpointDir string
 }
 
 // ScraperConfig is implemented by scrapers that accept ScraperOptions after
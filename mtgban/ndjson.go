@@ -0,0 +1,145 @@
+package mtgban
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// inventoryLine is one line of an inventory NDJSON file: an entry flattened
+// next to the id of its card.
+type inventoryLine struct {
+	UUID string
+	InventoryEntry
+}
+
+// buylistLine is one line of a buylist NDJSON file.
+type buylistLine struct {
+	UUID string
+	BuylistEntry
+}
+
+// WriteSellerToNDJSON writes the inventory of a seller one entry per line,
+// each carrying the id of its card in the UUID field. The info is not part
+// of the file, see WriteSellerToJSON for a sidecar holding it.
+func WriteSellerToNDJSON(seller Seller, w io.Writer) error {
+	inventory := seller.Inventory()
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, cardID := range sortedKeys(inventory) {
+		for _, entry := range inventory[cardID] {
+			err := enc.Encode(&inventoryLine{
+				UUID:           cardID,
+				InventoryEntry: entry,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// WriteVendorToNDJSON writes the buylist of a vendor one entry per line, like
+// WriteSellerToNDJSON does for inventories.
+func WriteVendorToNDJSON(vendor Vendor, w io.Writer) error {
+	buylist := vendor.Buylist()
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, cardID := range sortedKeys(buylist) {
+		for _, entry := range buylist[cardID] {
+			err := enc.Encode(&buylistLine{
+				UUID:         cardID,
+				BuylistEntry: entry,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ReadSellerFromNDJSON rebuilds a seller from what WriteSellerToNDJSON
+// emits. As the file carries no info, the seller has an empty one: use
+// ReadSeller to pair it with its sidecar.
+func ReadSellerFromNDJSON(r io.Reader) (Seller, error) {
+	inventory, err := LoadInventoryFromNDJSON(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewSellerFromInventory(inventory, ScraperInfo{}), nil
+}
+
+// ReadVendorFromNDJSON rebuilds a vendor from what WriteVendorToNDJSON
+// emits, with the same empty info as ReadSellerFromNDJSON.
+func ReadVendorFromNDJSON(r io.Reader) (Vendor, error) {
+	buylist, err := LoadBuylistFromNDJSON(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewVendorFromBuylist(buylist, ScraperInfo{}), nil
+}
+
+// LoadInventoryFromNDJSON reads the lines written by WriteSellerToNDJSON.
+// Entries are kept in the order they were written, which is the order of
+// the record they came from.
+func LoadInventoryFromNDJSON(r io.Reader) (InventoryRecord, error) {
+	inventory := InventoryRecord{}
+	err := decodeLines(r, func(line *inventoryLine) string {
+		inventory[line.UUID] = append(inventory[line.UUID], line.InventoryEntry)
+		return line.UUID
+	})
+	if err != nil {
+		return nil, err
+	}
+	return inventory, nil
+}
+
+// LoadBuylistFromNDJSON reads the lines written by WriteVendorToNDJSON.
+func LoadBuylistFromNDJSON(r io.Reader) (BuylistRecord, error) {
+	buylist := BuylistRecord{}
+	err := decodeLines(r, func(line *buylistLine) string {
+		buylist[line.UUID] = append(buylist[line.UUID], line.BuylistEntry)
+		return line.UUID
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buylist, nil
+}
+
+// decodeLines calls add on every line of r, which returns the card id the
+// line was stored under so that a line with none can be reported.
+func decodeLines[L any](r io.Reader, add func(*L) string) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for i := 1; ; i++ {
+		var line L
+		err := dec.Decode(&line)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading line %d: %v", i, err)
+		}
+		if add(&line) == "" {
+			return fmt.Errorf("error reading line %d: missing UUID", i)
+		}
+	}
+}
+
+func sortedKeys[E any](record map[string][]E) []string {
+	keys := make([]string, 0, len(record))
+	for key := range record {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,204 @@
+package mtgban
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	exchangeRateURL        = "https://cdn.jsdelivr.net/npm/@fawazahmed0/currency-api@latest/v1/currencies/usd.json"
+	exchangeRateDatedURL   = "https://cdn.jsdelivr.net/npm/@fawazahmed0/currency-api@%s/v1/currencies/usd.json"
+	exchangeRateDateFmt    = "2006-01-02"
+	defaultExchangeRateTTL = 6 * time.Hour
+)
+
+// ExchangeRateProvider is a source of exchange rates. ExchangeRates returns
+// the rate that converts each currency it knows to USD: multiply a price by
+// its entry to get dollars. Keys are lower case.
+type ExchangeRateProvider interface {
+	ExchangeRates(ctx context.Context) (map[string]float64, error)
+}
+
+// DefaultExchangeRateProvider is the provider used by GetExchangeRates, and by
+// every scraper whose own RateProvider is left nil. It fetches the latest rates
+// from the CDN, reusing them for a few hours since the feed is only updated
+// daily. Replace it before loading any scraper to pin or fake the rates of a
+// whole run.
+var DefaultExchangeRateProvider ExchangeRateProvider = NewCachedRates(&CDNRates{}, defaultExchangeRateTTL)
+
+// CDNRates fetches rates from the fawazahmed0 currency feed. With a zero Date
+// it asks for the latest rates, otherwise for the snapshot the feed published
+// on that day, which makes a run reproducible.
+type CDNRates struct {
+	Date time.Time
+}
+
+func (cdn *CDNRates) ExchangeRates(ctx context.Context) (map[string]float64, error) {
+	link := exchangeRateURL
+	if !cdn.Date.IsZero() {
+		link = fmt.Sprintf(exchangeRateDatedURL, cdn.Date.Format(exchangeRateDateFmt))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := NewHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exchange rates: unexpected status %s", resp.Status)
+	}
+
+	return parseRates(resp.Body)
+}
+
+// FileRates reads rates from a JSON file in the same format the CDN serves,
+// so a response saved once can be replayed offline. The file is read on
+// every call.
+type FileRates struct {
+	Path string
+}
+
+func (fr *FileRates) ExchangeRates(ctx context.Context) (map[string]float64, error) {
+	file, err := os.Open(fr.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rates, err := parseRates(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fr.Path, err)
+	}
+	return rates, nil
+}
+
+// StaticRates is a fixed table of multiply-to-USD rates, keyed like the ones
+// returned by any other provider, mostly meant for tests.
+type StaticRates map[string]float64
+
+func (sr StaticRates) ExchangeRates(ctx context.Context) (map[string]float64, error) {
+	if len(sr) == 0 {
+		return nil, errors.New("no exchange rates available")
+	}
+	return maps.Clone(sr), nil
+}
+
+// CachedRates wraps a provider so that it is asked at most once every TTL,
+// and only once in total when TTL is zero or less. Failed fetches are not
+// cached. Callers receive their own copy of the rates.
+type CachedRates struct {
+	provider ExchangeRateProvider
+	ttl      time.Duration
+
+	mu        sync.Mutex
+	rates     map[string]float64
+	fetchedAt time.Time
+}
+
+// NewCachedRates returns a provider caching the rates of provider for ttl.
+func NewCachedRates(provider ExchangeRateProvider, ttl time.Duration) *CachedRates {
+	return &CachedRates{
+		provider: provider,
+		ttl:      ttl,
+	}
+}
+
+func (cr *CachedRates) ExchangeRates(ctx context.Context) (map[string]float64, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if cr.rates == nil || (cr.ttl > 0 && time.Since(cr.fetchedAt) > cr.ttl) {
+		rates, err := cr.provider.ExchangeRates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		cr.rates = rates
+		cr.fetchedAt = time.Now()
+	}
+
+	return maps.Clone(cr.rates), nil
+}
+
+// parseRates decodes a response of the currency feed, which quotes how much
+// of each currency a dollar buys, into multiply-to-USD rates.
+//
+// A currency quoted at zero is left out rather than kept as an infinity: it
+// converts nothing, and a caller reading a missing entry refuses the price
+// where one read as a number would invent it.
+func parseRates(r io.Reader) (map[string]float64, error) {
+	var response struct {
+		USD map[string]float64 `json:"usd"`
+	}
+	err := json.NewDecoder(r).Decode(&response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.USD) == 0 {
+		return nil, errors.New("no exchange rates in response")
+	}
+
+	rates := make(map[string]float64, len(response.USD))
+	for currency, rate := range response.USD {
+		if rate == 0 {
+			continue
+		}
+		rates[currency] = 1 / rate
+	}
+	return rates, nil
+}
+
+// ParseExchangeRateProvider returns the provider described by spec, as taken
+// from a command line option: an empty string or "latest" for the latest CDN
+// rates, a YYYY-MM-DD date for the CDN snapshot of that day, and anything
+// else as the path of a rates file.
+func ParseExchangeRateProvider(spec string) ExchangeRateProvider {
+	switch spec {
+	case "", "latest":
+		return &CDNRates{}
+	}
+	date, err := time.Parse(exchangeRateDateFmt, spec)
+	if err == nil {
+		return &CDNRates{Date: date}
+	}
+	return &FileRates{Path: spec}
+}
+
+// ExchangeRatesFrom returns the rates of provider, or of
+// DefaultExchangeRateProvider when provider is nil.
+func ExchangeRatesFrom(ctx context.Context, provider ExchangeRateProvider) (map[string]float64, error) {
+	if provider == nil {
+		provider = DefaultExchangeRateProvider
+	}
+	return provider.ExchangeRates(ctx)
+}
+
+// ExchangeRateFrom returns the rate of provider that converts the given
+// currency to USD, or of DefaultExchangeRateProvider when provider is nil.
+func ExchangeRateFrom(ctx context.Context, provider ExchangeRateProvider, currency string) (float64, error) {
+	rates, err := ExchangeRatesFrom(ctx, provider)
+	if err != nil {
+		return 0, err
+	}
+
+	rate, found := rates[strings.ToLower(currency)]
+	if !found {
+		return 0, fmt.Errorf("%s not found in response", strings.ToLower(currency))
+	}
+
+	return rate, nil
+}
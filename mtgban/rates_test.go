@@ -0,0 +1,106 @@
+package mtgban
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type countingRates struct {
+	calls int
+	err   error
+}
+
+func (cr *countingRates) ExchangeRates(ctx context.Context) (map[string]float64, error) {
+	cr.calls++
+	if cr.err != nil {
+		return nil, cr.err
+	}
+	return map[string]float64{"eur": 1.1}, nil
+}
+
+func TestFileRates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	err := os.WriteFile(path, []byte(`{"date": "2024-03-06", "usd": {"eur": 0.8, "jpy": 150, "xxx": 0}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rate, err := ExchangeRateFrom(context.Background(), &FileRates{Path: path}, "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 1.25 {
+		t.Errorf("EUR rate is %f, expected 1.25", rate)
+	}
+
+	_, err = ExchangeRateFrom(context.Background(), &FileRates{Path: path}, "XXX")
+	if err == nil {
+		t.Error("a currency quoted at zero returned a rate")
+	}
+}
+
+func TestStaticRates(t *testing.T) {
+	rates := StaticRates{"cad": 0.75}
+
+	got, err := ExchangeRatesFrom(context.Background(), rates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got["cad"] = 2
+	if rates["cad"] != 0.75 {
+		t.Error("the static table was modified through the returned rates")
+	}
+
+	_, err = StaticRates{}.ExchangeRates(context.Background())
+	if err == nil {
+		t.Error("an empty table returned no error")
+	}
+}
+
+func TestCachedRates(t *testing.T) {
+	source := &countingRates{err: errors.New("offline")}
+	cached := NewCachedRates(source, 0)
+
+	_, err := cached.ExchangeRates(context.Background())
+	if err == nil {
+		t.Fatal("the error of the source was not returned")
+	}
+
+	source.err = nil
+	for i := 0; i < 3; i++ {
+		_, err = cached.ExchangeRates(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if source.calls != 2 {
+		t.Errorf("source was asked %d times, expected 2", source.calls)
+	}
+
+	expiring := NewCachedRates(source, time.Nanosecond)
+	expiring.ExchangeRates(context.Background())
+	time.Sleep(time.Millisecond)
+	expiring.ExchangeRates(context.Background())
+	if source.calls != 4 {
+		t.Errorf("source was asked %d times, expected the expired rates to be fetched again", source.calls)
+	}
+}
+
+func TestParseExchangeRateProvider(t *testing.T) {
+	cdn, ok := ParseExchangeRateProvider("latest").(*CDNRates)
+	if !ok || !cdn.Date.IsZero() {
+		t.Errorf("latest parsed as %#v", cdn)
+	}
+	cdn, ok = ParseExchangeRateProvider("2024-03-06").(*CDNRates)
+	if !ok || cdn.Date.Format("2006-01-02") != "2024-03-06" {
+		t.Errorf("a date parsed as %#v", cdn)
+	}
+	file, ok := ParseExchangeRateProvider("rates.json").(*FileRates)
+	if !ok || file.Path != "rates.json" {
+		t.Errorf("a path parsed as %#v", file)
+	}
+}
@@ -0,0 +1,340 @@
+package mtgban
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// TransportHook, when set, wraps the transport of every client built by
+// NewHTTPClient, NewRetryableClient and HookTransport. It is how a live run
+// is recorded with a Recorder, and how tests replay it with a Replayer. Set
+// it before building any scraper, since clients are built along with them.
+var TransportHook func(base http.RoundTripper) http.RoundTripper
+
+// HookTransport returns base wrapped by TransportHook, then paced by
+// DefaultHostThrottle; without either, base is returned as is. A nil base
+// stands for http.DefaultTransport.
+func HookTransport(base http.RoundTripper) http.RoundTripper {
+	if TransportHook == nil && DefaultHostThrottle == nil {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if TransportHook != nil {
+		base = TransportHook(base)
+	}
+	if DefaultHostThrottle != nil {
+		base = DefaultHostThrottle.Wrap(base)
+	}
+	return base
+}
+
+// NewHTTPClient returns a cleanhttp client going through TransportHook and
+// DefaultHostThrottle.
+func NewHTTPClient() *http.Client {
+	client := cleanhttp.DefaultClient()
+	client.Transport = HookTransport(client.Transport)
+	return client
+}
+
+// NewRetryableClient returns a retryablehttp client going through
+// TransportHook and DefaultHostThrottle.
+func NewRetryableClient() *retryablehttp.Client {
+	client := retryablehttp.NewClient()
+	client.HTTPClient.Transport = HookTransport(client.HTTPClient.Transport)
+	return client
+}
+
+// replayExchange describes a recorded response, whose body is kept in a file
+// of its own next to it. Credentials are redacted from every field before it
+// is saved, see ReplayRedactedParams and ReplayRedactedHeaders.
+type replayExchange struct {
+	Method        string      `json:"method"`
+	URL           string      `json:"url"`
+	RequestHeader http.Header `json:"request_header,omitempty"`
+	RequestBody   string      `json:"request_body,omitempty"`
+	Status        int         `json:"status"`
+	Header        http.Header `json:"header,omitempty"`
+}
+
+// ReplayRedactedParams are the query and form parameters, compared ignoring
+// case, whose values a Recorder replaces with ReplayRedacted. A Replayer
+// redacts requests the same way before looking them up, so fixtures are
+// found whatever credentials the replayed run uses.
+var ReplayRedactedParams = []string{
+	"api_key", "apikey", "token", "access_token", "refresh_token",
+	"client_id", "client_secret", "secret", "password", "signature",
+	"oauth_token", "oauth_consumer_key", "oauth_signature",
+}
+
+// ReplayRedactedHeaders are the request and response headers whose values a
+// Recorder replaces with ReplayRedacted.
+var ReplayRedactedHeaders = []string{
+	"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie",
+	"X-Api-Key", "X-Auth-Token",
+}
+
+// ReplayRedacted stands for the values of redacted parameters and headers.
+const ReplayRedacted = "REDACTED"
+
+// redactValues replaces the values of the ReplayRedactedParams of values,
+// reporting whether there were any.
+func redactValues(values url.Values) bool {
+	redacted := false
+	for name, vals := range values {
+		if !slices.ContainsFunc(ReplayRedactedParams, func(param string) bool {
+			return strings.EqualFold(param, name)
+		}) {
+			continue
+		}
+		for i := range vals {
+			vals[i] = ReplayRedacted
+		}
+		redacted = true
+	}
+	return redacted
+}
+
+// redactRequest returns the URL and body of req as a fixture records them,
+// with the values of ReplayRedactedParams replaced in the query and in form
+// bodies. Both are left as they are when there is nothing to redact.
+func redactRequest(req *http.Request, body []byte) (string, []byte) {
+	link := *req.URL
+	query := link.Query()
+	if redactValues(query) {
+		link.RawQuery = query.Encode()
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType == "application/x-www-form-urlencoded" {
+		form, err := url.ParseQuery(string(body))
+		if err == nil && redactValues(form) {
+			body = []byte(form.Encode())
+		}
+	}
+	return link.String(), body
+}
+
+// redactHeader returns a copy of header with the values of
+// ReplayRedactedHeaders replaced.
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range ReplayRedactedHeaders {
+		vals := header.Values(name)
+		for i := range vals {
+			vals[i] = ReplayRedacted
+		}
+	}
+	return header
+}
+
+// replayKey names the fixture of a request after its method, URL and body.
+func replayKey(method, link string, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", method, link)
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// readRequestBody drains the body of req, leaving a copy in its place.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// Recorder saves every response of a live run to a fixture directory, for a
+// Replayer to serve later. Each response is a pair of files named after the
+// request: a json file describing it and the body as it was received.
+// Known credentials are redacted from the description, but the bodies and
+// any other parameter are saved as they are, so review fixtures before
+// publishing.
+type Recorder struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewRecorder returns a Recorder writing to dir, creating it if needed.
+func NewRecorder(dir string) (*Recorder, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{dir: dir}, nil
+}
+
+// Wrap returns a transport recording the responses of base, ready to be
+// used as TransportHook.
+func (rec *Recorder) Wrap(base http.RoundTripper) http.RoundTripper {
+	return &recordingTransport{rec: rec, base: base}
+}
+
+type recordingTransport struct {
+	rec  *Recorder
+	base http.RoundTripper
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := rt.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	link, redactedBody := redactRequest(req, reqBody)
+	exchange := replayExchange{
+		Method:        req.Method,
+		URL:           link,
+		RequestHeader: redactHeader(req.Header),
+		RequestBody:   string(redactedBody),
+		Status:        resp.StatusCode,
+		Header:        redactHeader(resp.Header),
+	}
+	err = rt.rec.save(replayKey(exchange.Method, exchange.URL, redactedBody), &exchange, body)
+	if err != nil {
+		return nil, fmt.Errorf("recording %s %s: %w", exchange.Method, exchange.URL, err)
+	}
+	return resp, nil
+}
+
+func (rec *Recorder) save(key string, exchange *replayExchange, body []byte) error {
+	meta, err := json.MarshalIndent(exchange, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	err = os.WriteFile(filepath.Join(rec.dir, key+".body"), body, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(rec.dir, key+".json"), append(meta, '\n'), 0o644)
+}
+
+// Replayer serves the responses saved by a Recorder, without ever reaching
+// the network. A request that was not recorded is answered with a 501 Not
+// Implemented, which retrying clients give up on right away, and is listed
+// by Misses.
+type Replayer struct {
+	dir string
+
+	mu     sync.Mutex
+	misses []string
+}
+
+// NewReplayer returns a Replayer serving the fixtures in dir.
+func NewReplayer(dir string) (*Replayer, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return &Replayer{dir: dir}, nil
+}
+
+// Wrap returns the Replayer itself, ignoring base, ready to be used as
+// TransportHook.
+func (rep *Replayer) Wrap(base http.RoundTripper) http.RoundTripper {
+	return rep
+}
+
+// Misses returns the requests that had no recorded response, sorted.
+func (rep *Replayer) Misses() []string {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	misses := slices.Clone(rep.misses)
+	slices.Sort(misses)
+	return slices.Compact(misses)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rep *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	link, reqBody := redactRequest(req, reqBody)
+	key := replayKey(req.Method, link, reqBody)
+
+	var exchange replayExchange
+	meta, err := os.ReadFile(filepath.Join(rep.dir, key+".json"))
+	if os.IsNotExist(err) {
+		rep.mu.Lock()
+		rep.misses = append(rep.misses, req.Method+" "+link)
+		rep.mu.Unlock()
+
+		exchange.Status = http.StatusNotImplemented
+		return replayResponse(req, &exchange, []byte("no recorded response for "+req.Method+" "+link))
+	} else if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(meta, &exchange)
+	if err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", key, err)
+	}
+
+	body, err := os.ReadFile(filepath.Join(rep.dir, key+".body"))
+	if err != nil {
+		return nil, err
+	}
+	return replayResponse(req, &exchange, body)
+}
+
+func replayResponse(req *http.Request, exchange *replayExchange, body []byte) (*http.Response, error) {
+	header := exchange.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	// The body was saved as the client read it, possibly decompressed
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", exchange.Status, http.StatusText(exchange.Status)),
+		StatusCode:    exchange.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,170 @@
+package mtgban
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Test", "yes")
+		if r.URL.Path == "/auth" {
+			w.Header().Set("Set-Cookie", "session=s3cr3t")
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		fmt.Fprintf(w, "%s %s %s", r.Method, r.URL.Path, body)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	rec, err := NewRecorder(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hook := TransportHook
+	t.Cleanup(func() {
+		TransportHook = hook
+	})
+
+	retryable := func() *http.Client {
+		client := NewRetryableClient()
+		client.Logger = nil
+		return client.StandardClient()
+	}
+	get := func(client *http.Client, method, path, body string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(data)
+	}
+
+	TransportHook = rec.Wrap
+	live := retryable()
+	get(live, http.MethodGet, "/a", "")
+	get(live, http.MethodPost, "/a", "first")
+	get(live, http.MethodPost, "/a", "second")
+	get(NewHTTPClient(), http.MethodGet, "/missing", "")
+
+	// Requests carrying credentials
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/auth?page=2&api_key=s3cr3t", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer s3cr3t")
+	req.Header.Set("Cookie", "session=s3cr3t")
+	resp, err := live.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	req, err = http.NewRequest(http.MethodPost, server.URL+"/token", strings.NewReader("grant_type=password&password=hunter2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err = live.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	// Nothing reaches the server from now on
+	server.Close()
+
+	rep, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	TransportHook = rep.Wrap
+	replayed := retryable()
+
+	tests := []struct {
+		method, path, body string
+		status             int
+		expected           string
+	}{
+		{http.MethodGet, "/a", "", http.StatusOK, "GET /a "},
+		{http.MethodPost, "/a", "second", http.StatusOK, "POST /a second"},
+		{http.MethodPost, "/a", "first", http.StatusOK, "POST /a first"},
+		{http.MethodGet, "/missing", "", http.StatusNotFound, "GET /missing "},
+		{http.MethodGet, "/b", "", http.StatusNotImplemented, "no recorded response for GET " + server.URL + "/b"},
+	}
+	for _, test := range tests {
+		status, body := get(replayed, test.method, test.path, test.body)
+		if status != test.status || body != test.expected {
+			t.Errorf("%s %s: expected %d %q, got %d %q", test.method, test.path, test.status, test.expected, status, body)
+		}
+	}
+
+	// Credentials never reach the fixture descriptions, and the replayed run finds them
+	// whatever credentials it uses
+	fixtures, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fixture := range fixtures {
+		if filepath.Ext(fixture.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, fixture.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{"s3cr3t", "Bearer", "session=", "hunter2"} {
+			if strings.Contains(string(data), secret) {
+				t.Errorf("%s holds %q", fixture.Name(), secret)
+			}
+		}
+	}
+	for _, link := range []string{"/auth?api_key=other&page=2", "/auth?page=2&api_key=another"} {
+		status, body := get(replayed, http.MethodGet, link, "")
+		if status != http.StatusOK || body != "GET /auth " {
+			t.Errorf("%s: expected the recorded response, got %d %q", link, status, body)
+		}
+	}
+	req, err = http.NewRequest(http.MethodPost, server.URL+"/token", strings.NewReader("grant_type=password&password=other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err = replayed.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("the form request was not replayed: %d", resp.StatusCode)
+	}
+
+	misses := rep.Misses()
+	if len(misses) != 1 || misses[0] != "GET "+server.URL+"/b" {
+		t.Errorf("unexpected misses %v", misses)
+	}
+
+	// Without a hook clients are left alone
+	TransportHook = nil
+	if NewHTTPClient().Transport == rep {
+		t.Error("a client replays with no hook set")
+	}
+}
@@ -0,0 +1,119 @@
+// Package scrapertest runs scrapers end to end without network, replaying the
+// responses recorded by bantool -record against a datastore small enough to
+// live next to them.
+//
+// A test records once, keeps the fixtures and a trimmed AllPrintings in its
+// testdata directory, and asserts what Load produced:
+//
+//	sc := scrapertest.Load(t, "testdata/replay", "testdata/allprintings.json", func() (*Scraper, error) {
+//		return NewScraper(), nil
+//	})
+//	scrapertest.AssertInventory(t, sc.Inventory(), want)
+package scrapertest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+
+	_ "github.com/mtgban/go-mtgban/mtgmatcher/magic"
+)
+
+// Replay serves the fixtures in dir to every client built until the test
+// ends, and fails the test for each request that had no recorded response.
+func Replay(t testing.TB, dir string) *mtgban.Replayer {
+	t.Helper()
+
+	rep, err := mtgban.NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hook := mtgban.TransportHook
+	mtgban.TransportHook = rep.Wrap
+	t.Cleanup(func() {
+		mtgban.TransportHook = hook
+		for _, miss := range rep.Misses() {
+			t.Errorf("no recorded response for %s", miss)
+		}
+	})
+	return rep
+}
+
+// LoadDatastore installs the datastore at path as the global one.
+func LoadDatastore(t testing.TB, path string) {
+	t.Helper()
+
+	err := mtgmatcher.LoadDatastoreFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Load builds a scraper with newScraper and loads it, replaying the fixtures
+// in dir against the datastore at datastore. The test fails if Load does.
+func Load[S mtgban.Scraper](t testing.TB, dir, datastore string, newScraper func() (S, error)) S {
+	t.Helper()
+
+	LoadDatastore(t, datastore)
+	Replay(t, dir)
+
+	scraper, err := newScraper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = scraper.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return scraper
+}
+
+// AssertInventory reports every card whose entries differ from the expected
+// ones.
+func AssertInventory(t testing.TB, got, want mtgban.InventoryRecord) {
+	t.Helper()
+	assertRecord(t, got, want)
+}
+
+// AssertBuylist reports every card whose entries differ from the expected
+// ones.
+func AssertBuylist(t testing.TB, got, want mtgban.BuylistRecord) {
+	t.Helper()
+	assertRecord(t, got, want)
+}
+
+func assertRecord[E any](t testing.TB, got, want map[string][]E) {
+	t.Helper()
+
+	var cardIDs []string
+	for cardID := range got {
+		cardIDs = append(cardIDs, cardID)
+	}
+	for cardID := range want {
+		_, found := got[cardID]
+		if !found {
+			cardIDs = append(cardIDs, cardID)
+		}
+	}
+	slices.Sort(cardIDs)
+
+	for _, cardID := range cardIDs {
+		if reflect.DeepEqual(got[cardID], want[cardID]) {
+			continue
+		}
+		t.Errorf("%s: expected %s, got %s", cardID, describe(want[cardID]), describe(got[cardID]))
+	}
+}
+
+func describe[E any](entries []E) string {
+	if entries == nil {
+		return "nothing"
+	}
+	return fmt.Sprintf("%+v", entries)
+}
@@ -0,0 +1,393 @@
+package mtgban
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+)
+
+// CollectionItem is a card to sell, in the condition it is in.
+type CollectionItem struct {
+	CardID     string
+	Conditions string
+	Quantity   int
+}
+
+// SellOpts sets the constraints OptimizeSell honors on top of the buylist
+// quantities.
+type SellOpts struct {
+	// Value buylist prices as store credit, using the CreditMultiplier of
+	// each vendor that has one
+	StoreCredit bool
+
+	// Minimum total of an order, keyed by vendor Shorthand
+	MinOrder map[string]float64
+
+	// Maximum number of vendors to ship to, unlimited when zero
+	MaxVendors int
+
+	// List of vendor Shorthands that will be considered, all when empty
+	Vendors []string
+}
+
+// SellEntry is a buylist entry picked by OptimizeSell, with Quantity set to
+// the amount to sell rather than the amount the vendor is buying.
+type SellEntry struct {
+	CardID string
+	Entry  BuylistEntry
+
+	// What a single card brings in, in cash or credit
+	Value float64
+}
+
+// SellOrder is what a SellPlan ships to one vendor.
+type SellOrder struct {
+	Shorthand string
+
+	// Whether the order is paid in store credit, and the multiplier of the
+	// vendor credit
+	Credit           bool
+	CreditMultiplier float64
+
+	Entries []SellEntry
+	Total   float64
+}
+
+// Buylist returns the entries of the order as a record, for WriteBuylistToCSV.
+func (order *SellOrder) Buylist() BuylistRecord {
+	buylist := BuylistRecord{}
+	for _, entry := range order.Entries {
+		buylist[entry.CardID] = append(buylist[entry.CardID], entry.Entry)
+	}
+	return buylist
+}
+
+// SellPlan is the set of orders that sells a collection for the highest total
+// OptimizeSell found.
+type SellPlan struct {
+	Orders []SellOrder
+
+	// The part of the collection that no vendor is buying
+	Unsold []CollectionItem
+
+	Total float64
+}
+
+// OptimizeSell picks the vendors to sell a collection to, the inverse of
+// Arbit. Every card goes to the vendor paying the most for its condition,
+// within the buylist quantity. Vendors whose order does not reach its minimum
+// are then left out, and the cards moved to the others, as are the vendors
+// adding the least to the total until no more than MaxVendors are left.
+// Items with no Conditions are taken as NM, as buylists do, and any other
+// grade outside FullGradeTags fails with ErrInvalidCondition.
+func OptimizeSell(opts *SellOpts, collection []CollectionItem, vendors []Vendor) (*SellPlan, error) {
+	collection = slices.Clone(collection)
+	for i := range collection {
+		if collection[i].Conditions == "" {
+			collection[i].Conditions = "NM"
+		}
+		if !slices.Contains(FullGradeTags, collection[i].Conditions) {
+			return nil, fmt.Errorf("collection item %s: %w", collection[i].CardID, ErrInvalidCondition)
+		}
+	}
+
+	var o SellOpts
+	if opts != nil {
+		o = *opts
+	}
+
+	solver := sellSolver{
+		opts:       &o,
+		collection: collection,
+	}
+	for _, vendor := range vendors {
+		info := vendor.Info()
+		if len(o.Vendors) > 0 && !slices.Contains(o.Vendors, info.Shorthand) {
+			continue
+		}
+		solver.vendors = append(solver.vendors, sellVendor{
+			shorthand:  info.Shorthand,
+			credit:     o.StoreCredit && info.CreditMultiplier > 0,
+			multiplier: info.CreditMultiplier,
+			buylist:    vendor.Buylist(),
+		})
+	}
+
+	allowed := make([]bool, len(solver.vendors))
+	for i := range allowed {
+		allowed[i] = true
+	}
+
+	best := solver.solve(allowed)
+	for {
+		// Drop the emptiest order below its minimum, as the cards it had
+		// may well be what lets another order reach its own
+		worst := -1
+		for _, i := range best.used() {
+			total := best.totals[i]
+			if total >= o.MinOrder[solver.vendors[i].shorthand] {
+				continue
+			}
+			if worst < 0 || total < best.totals[worst] {
+				worst = i
+			}
+		}
+		if worst < 0 {
+			break
+		}
+		allowed[worst] = false
+		best = solver.solve(allowed)
+	}
+
+	for o.MaxVendors > 0 && len(best.totals) > o.MaxVendors {
+		var candidate *sellSolution
+		var candidateAllowed []bool
+		for _, i := range best.used() {
+			next := slices.Clone(allowed)
+			next[i] = false
+			solution := solver.solve(next)
+			for solution.belowMinimum(&solver) {
+				// The order of a vendor that fell below its minimum is
+				// worth nothing, so this drop costs both
+				for j, total := range solution.totals {
+					if total < o.MinOrder[solver.vendors[j].shorthand] {
+						next[j] = false
+					}
+				}
+				solution = solver.solve(next)
+			}
+			if candidate == nil || solution.total > candidate.total {
+				candidate = solution
+				candidateAllowed = next
+			}
+		}
+		best = candidate
+		allowed = candidateAllowed
+	}
+
+	return solver.plan(best), nil
+}
+
+type sellVendor struct {
+	shorthand  string
+	credit     bool
+	multiplier float64
+	buylist    BuylistRecord
+}
+
+// value is what one card sold at price brings in.
+func (vendor *sellVendor) value(price float64) float64 {
+	if vendor.credit {
+		return price * vendor.multiplier
+	}
+	return price
+}
+
+type sellSolver struct {
+	opts       *SellOpts
+	collection []CollectionItem
+	vendors    []sellVendor
+}
+
+type sellOffer struct {
+	vendor int
+	index  int
+	value  float64
+}
+
+// sellPick is a collection item sold to an entry of a vendor buylist.
+type sellPick struct {
+	item   int
+	vendor int
+	index  int
+}
+
+type sellSolution struct {
+	// Units sold of each pick, and unsold of each collection item
+	sold   map[sellPick]int
+	unsold []int
+	totals map[int]float64
+	total  float64
+}
+
+// used returns the vendors the solution sells to, in order.
+func (solution *sellSolution) used() []int {
+	vendors := make([]int, 0, len(solution.totals))
+	for i := range solution.totals {
+		vendors = append(vendors, i)
+	}
+	sort.Ints(vendors)
+	return vendors
+}
+
+func (solution *sellSolution) belowMinimum(solver *sellSolver) bool {
+	for i, total := range solution.totals {
+		if total < solver.opts.MinOrder[solver.vendors[i].shorthand] {
+			return true
+		}
+	}
+	return false
+}
+
+type sellKey struct {
+	vendor int
+	cardID string
+	index  int
+}
+
+// solve sells every card to the allowed vendor paying the most for it.
+func (solver *sellSolver) solve(allowed []bool) *sellSolution {
+	solution := sellSolution{
+		sold:   map[sellPick]int{},
+		unsold: make([]int, len(solver.collection)),
+		totals: map[int]float64{},
+	}
+	// Units already sold against each buylist entry, shared by the items of
+	// the collection that are the same card
+	taken := map[sellKey]int{}
+
+	for i, item := range solver.collection {
+		var offers []sellOffer
+		for v, vendor := range solver.vendors {
+			if !allowed[v] {
+				continue
+			}
+			for j, entry := range vendor.buylist[item.CardID] {
+				if entry.Conditions != item.Conditions || entry.BuyPrice <= 0 {
+					continue
+				}
+				offers = append(offers, sellOffer{
+					vendor: v,
+					index:  j,
+					value:  vendor.value(entry.BuyPrice),
+				})
+			}
+		}
+		sort.SliceStable(offers, func(a, b int) bool {
+			return offers[a].value > offers[b].value
+		})
+
+		needed := item.Quantity
+		for _, offer := range offers {
+			if needed <= 0 {
+				break
+			}
+			key := sellKey{offer.vendor, item.CardID, offer.index}
+			qty := needed
+			// A buylist quantity of zero means the vendor takes any amount
+			limit := solver.vendors[offer.vendor].buylist[item.CardID][offer.index].Quantity
+			if limit > 0 {
+				qty = min(qty, limit-taken[key])
+			}
+			if qty <= 0 {
+				continue
+			}
+			taken[key] += qty
+			needed -= qty
+
+			solution.sold[sellPick{i, offer.vendor, offer.index}] += qty
+			solution.totals[offer.vendor] += offer.value * float64(qty)
+			solution.total += offer.value * float64(qty)
+		}
+		solution.unsold[i] = max(needed, 0)
+	}
+
+	return &solution
+}
+
+func (solver *sellSolver) plan(solution *sellSolution) *SellPlan {
+	var plan SellPlan
+
+	orders := map[int]*SellOrder{}
+	for pick, qty := range solution.sold {
+		item := solver.collection[pick.item]
+		vendor := &solver.vendors[pick.vendor]
+		entry := vendor.buylist[item.CardID][pick.index]
+		entry.Quantity = qty
+
+		order, found := orders[pick.vendor]
+		if !found {
+			order = &SellOrder{
+				Shorthand:        vendor.shorthand,
+				Credit:           vendor.credit,
+				CreditMultiplier: vendor.multiplier,
+				Total:            solution.totals[pick.vendor],
+			}
+			orders[pick.vendor] = order
+		}
+		order.Entries = append(order.Entries, SellEntry{
+			CardID: item.CardID,
+			Entry:  entry,
+			Value:  vendor.value(entry.BuyPrice),
+		})
+	}
+
+	for v := range solver.vendors {
+		order, found := orders[v]
+		if !found {
+			continue
+		}
+		sort.Slice(order.Entries, func(i, j int) bool {
+			if order.Entries[i].CardID != order.Entries[j].CardID {
+				return order.Entries[i].CardID < order.Entries[j].CardID
+			}
+			return order.Entries[i].Value > order.Entries[j].Value
+		})
+		plan.Orders = append(plan.Orders, *order)
+		plan.Total += order.Total
+	}
+	sort.SliceStable(plan.Orders, func(i, j int) bool {
+		return plan.Orders[i].Total > plan.Orders[j].Total
+	})
+
+	for i, item := range solver.collection {
+		if solution.unsold[i] > 0 {
+			item.Quantity = solution.unsold[i]
+			plan.Unsold = append(plan.Unsold, item)
+		}
+	}
+
+	return &plan
+}
+
+// WriteSellPlanToCSV writes every order of plan as one buylist file would be
+// written, with Quantity being the amount to sell and Vendor the Shorthand of
+// the order.
+func WriteSellPlanToCSV(plan *SellPlan, w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+	defer csvWriter.Flush()
+
+	err := csvWriter.Write(BuylistHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, order := range plan.Orders {
+		for _, sold := range order.Entries {
+			record, err := cardID2record(sold.CardID)
+			if err != nil {
+				continue
+			}
+
+			entry := sold.Entry
+			record = append(record,
+				entry.Conditions,
+				fmt.Sprintf("%0.2f", entry.BuyPrice),
+				fmt.Sprintf("%0.2f", entry.BuyPrice*order.CreditMultiplier),
+				fmt.Sprint(entry.Quantity),
+				fmt.Sprintf("%0.2f", entry.PriceRatio),
+				entry.URL,
+				order.Shorthand,
+			)
+
+			err = csvWriter.Write(record)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return csvWriter.Error()
+}
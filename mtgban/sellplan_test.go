@@ -0,0 +1,114 @@
+package mtgban
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"math"
+	"testing"
+)
+
+func sellVendors() []Vendor {
+	v1 := NewVendorFromBuylist(BuylistRecord{
+		"x|A|SET|1": {{Conditions: "NM", BuyPrice: 10, Quantity: 1}},
+		"x|B|SET|2": {{Conditions: "NM", BuyPrice: 5}},
+	}, ScraperInfo{Shorthand: "V1", CreditMultiplier: 1.3})
+	v2 := NewVendorFromBuylist(BuylistRecord{
+		"x|A|SET|1": {{Conditions: "NM", BuyPrice: 11}},
+	}, ScraperInfo{Shorthand: "V2"})
+	return []Vendor{v1, v2}
+}
+
+func sellCollection() []CollectionItem {
+	return []CollectionItem{
+		{CardID: "x|A|SET|1", Conditions: "NM", Quantity: 2},
+		{CardID: "x|B|SET|2", Conditions: "NM", Quantity: 1},
+		{CardID: "x|C|SET|3", Conditions: "NM", Quantity: 1},
+	}
+}
+
+func TestOptimizeSell(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   *SellOpts
+		total  float64
+		orders int
+		unsold int
+	}{
+		{"cash", nil, 27, 2, 1},
+		{"credit", &SellOpts{StoreCredit: true}, 13 + 11 + 6.5, 2, 1},
+		{"minimum", &SellOpts{MinOrder: map[string]float64{"V1": 10}}, 22, 1, 2},
+		{"max vendors", &SellOpts{MaxVendors: 1}, 22, 1, 2},
+		{"credit max vendors", &SellOpts{StoreCredit: true, MaxVendors: 1}, 22, 1, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plan, err := OptimizeSell(test.opts, sellCollection(), sellVendors())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(plan.Total-test.total) > 1e-9 {
+				t.Errorf("expected a total of %f, got %f", test.total, plan.Total)
+			}
+			if len(plan.Orders) != test.orders {
+				t.Errorf("expected %d orders, got %+v", test.orders, plan.Orders)
+			}
+			if len(plan.Unsold) != test.unsold {
+				t.Errorf("expected %d unsold items, got %+v", test.unsold, plan.Unsold)
+			}
+		})
+	}
+}
+
+func TestWriteSellPlanToCSV(t *testing.T) {
+	plan, err := OptimizeSell(&SellOpts{StoreCredit: true}, sellCollection(), sellVendors())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = WriteSellPlanToCSV(plan, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected a header and 3 rows, got %d records", len(records))
+	}
+	for _, record := range records[1:] {
+		if len(record) != len(BuylistHeader) {
+			t.Fatalf("record %v does not match the buylist header", record)
+		}
+		if record[len(record)-1] == "" || record[len(record)-4] != "1" {
+			t.Errorf("unexpected vendor or quantity in %v", record)
+		}
+	}
+}
+
+func TestOptimizeSellConditions(t *testing.T) {
+	// An item with no condition sells as NM
+	collection := []CollectionItem{
+		{CardID: "x|A|SET|1", Quantity: 1},
+	}
+	plan, err := OptimizeSell(nil, collection, sellVendors())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Total != 11 || len(plan.Unsold) != 0 {
+		t.Errorf("expected the NM price, got %f with %+v unsold", plan.Total, plan.Unsold)
+	}
+	if collection[0].Conditions != "" {
+		t.Error("the collection was modified")
+	}
+
+	collection[0].Conditions = "EX"
+	_, err = OptimizeSell(nil, collection, sellVendors())
+	if !errors.Is(err, ErrInvalidCondition) {
+		t.Errorf("expected ErrInvalidCondition, got %v", err)
+	}
+}
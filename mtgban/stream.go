@@ -0,0 +1,347 @@
+package mtgban
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+)
+
+// StreamFilter selects what a JSONStream yields while it decodes, so that the
+// entries left out are never held in memory. Empty fields do not filter.
+type StreamFilter struct {
+	// IDs of the cards to keep
+	CardIDs []string
+
+	// Conditions of the entries to keep
+	Conditions []string
+}
+
+// JSONStream reads one side of a file written by the Write*ToJSON functions,
+// or by a JSONStreamWriter, one card at a time instead of decoding it whole.
+// Like a bufio.Scanner it can be iterated only once, and Err reports what
+// stopped the iteration early. Dumps of the current version, whose version
+// comes first as the writers of this package put it, are never held whole;
+// older ones are held raw to run the migrations of ReadSellerFromJSON before
+// they are yielded, and newer ones are refused.
+type JSONStream[E GenericEntry] struct {
+	r      io.Reader
+	side   string
+	filter StreamFilter
+	ids    map[string]bool
+
+	info ScraperInfo
+	err  error
+	used bool
+}
+
+// NewInventoryStream returns a stream over the inventory side of r.
+func NewInventoryStream(r io.Reader, filter *StreamFilter) *JSONStream[InventoryEntry] {
+	return newJSONStream[InventoryEntry](r, "inventory", filter)
+}
+
+// NewBuylistStream returns a stream over the buylist side of r.
+func NewBuylistStream(r io.Reader, filter *StreamFilter) *JSONStream[BuylistEntry] {
+	return newJSONStream[BuylistEntry](r, "buylist", filter)
+}
+
+func newJSONStream[E GenericEntry](r io.Reader, side string, filter *StreamFilter) *JSONStream[E] {
+	stream := JSONStream[E]{
+		r:    r,
+		side: side,
+	}
+	if filter != nil {
+		stream.filter = *filter
+		if len(filter.CardIDs) > 0 {
+			stream.ids = make(map[string]bool, len(filter.CardIDs))
+			for _, cardID := range filter.CardIDs {
+				stream.ids[cardID] = true
+			}
+		}
+	}
+	return &stream
+}
+
+// Info returns the info of the file. The writers of this package put it
+// first, so it is available as soon as the first card is yielded.
+func (s *JSONStream[E]) Info() ScraperInfo {
+	return s.info
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (s *JSONStream[E]) Err() error {
+	return s.err
+}
+
+// All yields every card of the side with its entries, skipping the cards
+// left with no entries once filtered.
+func (s *JSONStream[E]) All() iter.Seq2[string, []E] {
+	return func(yield func(string, []E) bool) {
+		if s.used {
+			s.err = errors.New("stream already iterated")
+			return
+		}
+		s.used = true
+		s.err = s.decode(yield)
+	}
+}
+
+func (s *JSONStream[E]) decode(yield func(string, []E) bool) error {
+	dec := json.NewDecoder(bufio.NewReader(s.r))
+
+	err := expectDelim(dec, '{')
+	if err != nil {
+		return err
+	}
+
+	// Until the version is known to be the current one every field is held
+	// raw, as it may need migrating
+	dump := map[string]json.RawMessage{}
+	version := 0
+	current := false
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		key = dumpFieldName(key)
+
+		switch {
+		case key == "version":
+			err = dec.Decode(&version)
+			if err == nil && version > DumpVersion() {
+				err = fmt.Errorf("unsupported dump version %d, expected up to %d", version, DumpVersion())
+			}
+			current = version == DumpVersion()
+			if current && dump["info"] != nil {
+				err = json.Unmarshal(dump["info"], &s.info)
+				delete(dump, "info")
+			}
+		case !current:
+			var raw json.RawMessage
+			err = dec.Decode(&raw)
+			dump[key] = raw
+		case key == "info":
+			err = dec.Decode(&s.info)
+		case key == s.side:
+			var stop bool
+			stop, err = s.decodeRecord(dec, yield)
+			if stop {
+				return nil
+			}
+		default:
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	err = expectDelim(dec, '}')
+	if err != nil || len(dump) == 0 {
+		return err
+	}
+
+	// The fields held raw are migrated, then decoded as a current dump would
+	// be, save for those of a current dump that came before its version
+	if !current {
+		err = migrateDump(dump, version)
+		if err != nil {
+			return err
+		}
+	}
+	info, found := dump["info"]
+	if found {
+		err = json.Unmarshal(info, &s.info)
+		if err != nil {
+			return err
+		}
+	}
+	record, found := dump[s.side]
+	if !found {
+		return nil
+	}
+	_, err = s.decodeRecord(json.NewDecoder(bytes.NewReader(record)), yield)
+	return err
+}
+
+// decodeRecord yields the cards of a record, returning true if the caller
+// asked to stop.
+func (s *JSONStream[E]) decodeRecord(dec *json.Decoder, yield func(string, []E) bool) (bool, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return false, err
+	}
+	if tok == nil {
+		return false, nil
+	}
+	if tok != json.Delim('{') {
+		return false, fmt.Errorf("unexpected %v at the start of %s", tok, s.side)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return false, err
+		}
+		cardID, _ := tok.(string)
+
+		if s.ids != nil && !s.ids[cardID] {
+			err = skipValue(dec)
+			if err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		var entries []E
+		err = dec.Decode(&entries)
+		if err != nil {
+			return false, err
+		}
+		if len(s.filter.Conditions) > 0 {
+			entries = slices.DeleteFunc(entries, func(entry E) bool {
+				return !slices.Contains(s.filter.Conditions, entry.Condition())
+			})
+		}
+		if len(entries) == 0 {
+			continue
+		}
+
+		if !yield(cardID, entries) {
+			return true, nil
+		}
+	}
+
+	return false, expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("expected %v, found %v", delim, tok)
+	}
+	return nil
+}
+
+// skipValue consumes the next value token by token, so that skipping a whole
+// side of a file does not load it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// JSONStreamWriter writes a file in the shape of WriteScraperToJSON one card
+// at a time. The inventory, if any, has to be written before the buylist,
+// and Close has to be called to complete the file. Unlike WriteScraperToJSON
+// it does not know in advance whether a side will be empty, so the timestamps
+// of info are written as they are.
+type JSONStreamWriter struct {
+	w    *bufio.Writer
+	side string
+	sep  bool
+	err  error
+}
+
+// NewJSONStreamWriter starts a file with info.
+func NewJSONStreamWriter(w io.Writer, info ScraperInfo) (*JSONStreamWriter, error) {
+	sw := JSONStreamWriter{
+		w: bufio.NewWriter(w),
+	}
+
+	data, err := json.Marshal(&info)
+	if err != nil {
+		return nil, err
+	}
+	sw.write(fmt.Sprintf(`{"version":%d,"info":`, DumpVersion()))
+	sw.write(string(data))
+
+	return &sw, sw.err
+}
+
+// WriteInventory writes the inventory entries of a card.
+func (sw *JSONStreamWriter) WriteInventory(cardID string, entries []InventoryEntry) error {
+	return writeStreamEntries(sw, "inventory", cardID, entries)
+}
+
+// WriteBuylist writes the buylist entries of a card.
+func (sw *JSONStreamWriter) WriteBuylist(cardID string, entries []BuylistEntry) error {
+	return writeStreamEntries(sw, "buylist", cardID, entries)
+}
+
+// Close completes the file and flushes it to the underlying writer, which is
+// not closed.
+func (sw *JSONStreamWriter) Close() error {
+	if sw.side != "" {
+		sw.write("}")
+	}
+	sw.write("}\n")
+	if sw.err != nil {
+		return sw.err
+	}
+	return sw.w.Flush()
+}
+
+func writeStreamEntries[E GenericEntry](sw *JSONStreamWriter, side, cardID string, entries []E) error {
+	if sw.err != nil {
+		return sw.err
+	}
+	if side != sw.side {
+		if sw.side == "buylist" {
+			return errors.New("inventory written after the buylist")
+		}
+		if sw.side != "" {
+			sw.write("}")
+		}
+		sw.write(`,"` + side + `":{`)
+		sw.side = side
+		sw.sep = false
+	}
+
+	key, err := json.Marshal(cardID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	if sw.sep {
+		sw.write(",")
+	}
+	sw.sep = true
+	sw.write(string(key))
+	sw.write(":")
+	sw.write(string(data))
+
+	return sw.err
+}
+
+func (sw *JSONStreamWriter) write(s string) {
+	if sw.err != nil {
+		return
+	}
+	_, sw.err = sw.w.WriteString(s)
+}
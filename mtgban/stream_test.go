@@ -0,0 +1,183 @@
+package mtgban
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONStreamReadsWriteScraperToJSON(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	inventory := InventoryRecord{
+		"A": {{Conditions: "NM", Price: 2, Quantity: 1}, {Conditions: "SP", Price: 1, Quantity: 3}},
+		"B": {{Conditions: "MP", Price: 5, Quantity: 1}},
+	}
+	buylist := BuylistRecord{
+		"A": {{Conditions: "NM", BuyPrice: 1, Quantity: 4}},
+	}
+	info := ScraperInfo{Name: "Test", Shorthand: "TST", InventoryTimestamp: &now, BuylistTimestamp: &now}
+
+	var buf bytes.Buffer
+	err := WriteScraperToJSON(&testMarket{NewSellerFromInventory(inventory, info), buylist}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// The whole inventory, skipping the buylist
+	stream := NewInventoryStream(bytes.NewReader(data), nil)
+	got := InventoryRecord{}
+	for cardID, entries := range stream.All() {
+		got[cardID] = entries
+	}
+	if stream.Err() != nil {
+		t.Fatal(stream.Err())
+	}
+	if !reflect.DeepEqual(got, inventory) {
+		t.Errorf("expected %v, got %v", inventory, got)
+	}
+	if stream.Info().Shorthand != "TST" {
+		t.Errorf("unexpected info %+v", stream.Info())
+	}
+
+	// The buylist, past the inventory
+	blStream := NewBuylistStream(bytes.NewReader(data), nil)
+	count := 0
+	for cardID, entries := range blStream.All() {
+		count++
+		if cardID != "A" || entries[0].BuyPrice != 1 {
+			t.Errorf("unexpected buylist %s %v", cardID, entries)
+		}
+	}
+	if blStream.Err() != nil || count != 1 {
+		t.Errorf("expected one buylist card, got %d (%v)", count, blStream.Err())
+	}
+
+	// Filtered
+	stream = NewInventoryStream(bytes.NewReader(data), &StreamFilter{
+		CardIDs:    []string{"A", "B"},
+		Conditions: []string{"SP", "HP"},
+	})
+	got = InventoryRecord{}
+	for cardID, entries := range stream.All() {
+		got[cardID] = entries
+	}
+	expected := InventoryRecord{"A": {{Conditions: "SP", Price: 1, Quantity: 3}}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// Stopped early, and not reusable
+	stream = NewInventoryStream(bytes.NewReader(data), nil)
+	for range stream.All() {
+		break
+	}
+	if stream.Err() != nil {
+		t.Errorf("unexpected error after a break: %v", stream.Err())
+	}
+	for range stream.All() {
+	}
+	if stream.Err() == nil {
+		t.Error("a stream was iterated twice")
+	}
+}
+
+func TestJSONStreamMigrations(t *testing.T) {
+	addTestMigration(t)
+
+	for _, file := range []string{"testdata/dump_v0.json", "testdata/dump_v1.json"} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seller, err := ReadSellerFromJSON(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		stream := NewInventoryStream(bytes.NewReader(data), nil)
+		got := InventoryRecord{}
+		for cardID, entries := range stream.All() {
+			if stream.Info().Shorthand != "MIGRATED" {
+				t.Errorf("%s: yielded before the info was migrated: %+v", file, stream.Info())
+			}
+			got[cardID] = entries
+		}
+		if stream.Err() != nil {
+			t.Fatalf("%s: %v", file, stream.Err())
+		}
+		if !reflect.DeepEqual(got, seller.Inventory()) {
+			t.Errorf("%s: expected %v, got %v", file, seller.Inventory(), got)
+		}
+	}
+}
+
+func TestJSONStreamFieldOrder(t *testing.T) {
+	// A current dump whose version comes last is read whole
+	dump := fmt.Sprintf(`{"info":{"shorthand":"TST"},"inventory":{"A":[{"conditions":"NM","price":1}]},"version":%d}`, DumpVersion())
+	stream := NewInventoryStream(strings.NewReader(dump), nil)
+	count := 0
+	for range stream.All() {
+		count++
+	}
+	if stream.Err() != nil || count != 1 || stream.Info().Shorthand != "TST" {
+		t.Errorf("fields before the version were lost: %d %+v %v", count, stream.Info(), stream.Err())
+	}
+}
+
+func TestJSONStreamWriter(t *testing.T) {
+	var buf bytes.Buffer
+	sw, err := NewJSONStreamWriter(&buf, ScraperInfo{Shorthand: "TST"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sw.WriteInventory("A", []InventoryEntry{{Conditions: "NM", Price: 2, Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sw.WriteInventory("B", []InventoryEntry{{Conditions: "SP", Price: 1, Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sw.WriteBuylist("A", []BuylistEntry{{Conditions: "NM", BuyPrice: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sw.WriteInventory("C", []InventoryEntry{{Conditions: "NM", Price: 2, Quantity: 1}})
+	if err == nil {
+		t.Error("inventory written after the buylist")
+	}
+	err = sw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Readable in full by the regular readers
+	seller, err := ReadSellerFromJSON(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seller.Inventory()) != 2 || seller.Info().Shorthand != "TST" {
+		t.Errorf("unexpected seller %v %+v", seller.Inventory(), seller.Info())
+	}
+	vendor, err := ReadVendorFromJSON(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vendor.Buylist()["A"][0].BuyPrice != 3 {
+		t.Errorf("unexpected buylist %v", vendor.Buylist())
+	}
+}
+
+type testMarket struct {
+	Seller
+	buylist BuylistRecord
+}
+
+func (tm *testMarket) Buylist() BuylistRecord {
+	return tm.buylist
+}
@@ -0,0 +1,273 @@
+package mtgban
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultMaxRetryAfter is the longest a HostThrottle pauses a host on the
+// word of a Retry-After header, when MaxRetryAfter is not set.
+const DefaultMaxRetryAfter = 5 * time.Minute
+
+// DefaultHostThrottle paces every client built by NewHTTPClient,
+// NewRetryableClient and HookTransport when set. It is nil, so off, unless
+// the program opts in, as bantool does from its command line. Install it
+// before building any scraper, since clients are built along with them.
+var DefaultHostThrottle *HostThrottle
+
+var (
+	forbiddenMu    sync.Mutex
+	forbiddenHosts = map[string]bool{}
+)
+
+// ThrottleOnForbidden tells every HostThrottle that these hosts answer 403
+// Forbidden when they mean 429 Too Many Requests, so that a 403 from them
+// slows down like a 429 does. Anywhere else a 403 is an authentication or
+// access failure, and leaves the pace alone. Scrapers call this for the hosts
+// they know to behave like that.
+func ThrottleOnForbidden(hosts ...string) {
+	forbiddenMu.Lock()
+	defer forbiddenMu.Unlock()
+	for _, host := range hosts {
+		forbiddenHosts[host] = true
+	}
+}
+
+func forbiddenIsPushback(host string) bool {
+	forbiddenMu.Lock()
+	defer forbiddenMu.Unlock()
+	return forbiddenHosts[host]
+}
+
+// HostThrottle is a transport wrapper pacing requests per host, shared by all
+// the clients it wraps. A token bucket bounds the rate of requests, and the
+// number of requests in flight adapts to the host: it halves on each 429 Too
+// Many Requests, or 403 Forbidden for the hosts given to ThrottleOnForbidden,
+// and grows back by one after as many
+// successful responses as the current limit, up to MaxConcurrency or, when
+// that is not set, to where it was before the first reduction, at which point
+// it is lifted. A Retry-After header on a 429 or 503 holds every request to
+// that host until the time it names. Retrying the failed request itself is
+// left to the client.
+//
+// The zero value is ready to use; its fields must be set before its first
+// request.
+type HostThrottle struct {
+	// Requests per second allowed to each host, zero for no limit
+	Rate float64
+
+	// Requests that may be sent at once after an idle period, one if zero
+	Burst int
+
+	// Requests in flight allowed to each host, zero for no limit until the
+	// host pushes back
+	MaxConcurrency int
+
+	// Longest pause honored from a Retry-After header
+	MaxRetryAfter time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+type hostState struct {
+	name string
+
+	tokens float64
+	last   time.Time
+
+	inflight  int
+	limit     int
+	ceiling   int
+	successes int
+
+	until time.Time
+
+	// Closed and replaced whenever a request completes
+	wake chan struct{}
+}
+
+// Wrap returns base going through the throttle.
+func (ht *HostThrottle) Wrap(base http.RoundTripper) http.RoundTripper {
+	return &throttledTransport{ht: ht, base: base}
+}
+
+type throttledTransport struct {
+	ht   *HostThrottle
+	base http.RoundTripper
+}
+
+func (tt *throttledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	hs, err := tt.ht.acquire(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := tt.base.RoundTrip(req)
+	tt.ht.release(hs, resp)
+	return resp, err
+}
+
+func (ht *HostThrottle) host(name string) *hostState {
+	if ht.hosts == nil {
+		ht.hosts = map[string]*hostState{}
+	}
+	hs, found := ht.hosts[name]
+	if !found {
+		hs = &hostState{
+			name:   name,
+			tokens: float64(ht.burst()),
+			last:   time.Now(),
+			limit:  ht.MaxConcurrency,
+			wake:   make(chan struct{}),
+		}
+		ht.hosts[name] = hs
+	}
+	return hs
+}
+
+func (ht *HostThrottle) burst() int {
+	if ht.Burst < 1 {
+		return 1
+	}
+	return ht.Burst
+}
+
+// acquire waits until req may be sent to its host, or until its context is
+// done.
+func (ht *HostThrottle) acquire(req *http.Request) (*hostState, error) {
+	ctx := req.Context()
+	for {
+		ht.mu.Lock()
+		hs := ht.host(req.URL.Host)
+		now := time.Now()
+
+		if ht.Rate > 0 {
+			hs.tokens += now.Sub(hs.last).Seconds() * ht.Rate
+			hs.tokens = min(hs.tokens, float64(ht.burst()))
+		}
+		hs.last = now
+
+		// A negative wait lasts until a request completes
+		var wait time.Duration
+		switch {
+		case now.Before(hs.until):
+			wait = hs.until.Sub(now)
+		case hs.limit > 0 && hs.inflight >= hs.limit:
+			wait = -1
+		case ht.Rate > 0 && hs.tokens < 1:
+			wait = time.Duration((1 - hs.tokens) / ht.Rate * float64(time.Second))
+		}
+		if wait == 0 {
+			if ht.Rate > 0 {
+				hs.tokens--
+			}
+			hs.inflight++
+			ht.mu.Unlock()
+			return hs, nil
+		}
+		wake := hs.wake
+		ht.mu.Unlock()
+
+		var timer *time.Timer
+		var expired <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			expired = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return nil, ctx.Err()
+		case <-wake:
+		case <-expired:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// release accounts for the response of a request sent by acquire.
+func (ht *HostThrottle) release(hs *hostState, resp *http.Response) {
+	ht.mu.Lock()
+	defer ht.mu.Unlock()
+
+	inflight := hs.inflight
+	hs.inflight--
+	defer func() {
+		close(hs.wake)
+		hs.wake = make(chan struct{})
+	}()
+
+	if resp == nil {
+		return
+	}
+
+	pushback := resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && forbiddenIsPushback(hs.name))
+
+	switch {
+	case pushback:
+		if hs.limit == 0 {
+			hs.limit = inflight
+			hs.ceiling = inflight
+		}
+		hs.limit = max(hs.limit/2, 1)
+		hs.successes = 0
+	default:
+		if hs.limit == 0 || resp.StatusCode >= http.StatusInternalServerError {
+			break
+		}
+		hs.successes++
+		if hs.successes < hs.limit {
+			break
+		}
+		hs.successes = 0
+		hs.limit++
+
+		ceiling := ht.MaxConcurrency
+		if ceiling == 0 {
+			ceiling = hs.ceiling
+		}
+		if hs.limit >= ceiling {
+			hs.limit = ht.MaxConcurrency
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		wait, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			break
+		}
+		maxWait := ht.MaxRetryAfter
+		if maxWait == 0 {
+			maxWait = DefaultMaxRetryAfter
+		}
+		until := time.Now().Add(min(wait, maxWait))
+		if until.After(hs.until) {
+			hs.until = until
+		}
+	}
+}
+
+// retryAfter parses a Retry-After header, either a number of seconds or a
+// date.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(date.Sub(now), 0), true
+}
@@ -0,0 +1,229 @@
+package mtgban
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func statusTransport(status func() (int, http.Header)) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		code, header := status()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{StatusCode: code, Header: header, Body: http.NoBody, Request: req}, nil
+	})
+}
+
+func throttledGet(t *testing.T, transport http.RoundTripper, link string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, link, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHostThrottleRate(t *testing.T) {
+	ht := &HostThrottle{Rate: 50, Burst: 2}
+	transport := ht.Wrap(statusTransport(func() (int, http.Header) {
+		return http.StatusOK, nil
+	}))
+
+	// Two requests go through at once, the next three wait 20ms each
+	start := time.Now()
+	for range 5 {
+		throttledGet(t, transport, "http://a.example/")
+	}
+	elapsed := time.Since(start)
+	if elapsed < 50*time.Millisecond {
+		t.Errorf("five requests took %s, faster than the rate allows", elapsed)
+	}
+
+	// Other hosts have buckets of their own
+	start = time.Now()
+	throttledGet(t, transport, "http://b.example/")
+	throttledGet(t, transport, "http://b.example/")
+	elapsed = time.Since(start)
+	if elapsed > 15*time.Millisecond {
+		t.Errorf("a fresh host was held for %s", elapsed)
+	}
+}
+
+func TestHostThrottleConcurrency(t *testing.T) {
+	ht := &HostThrottle{MaxConcurrency: 8}
+
+	var mu sync.Mutex
+	status := http.StatusOK
+	transport := ht.Wrap(statusTransport(func() (int, http.Header) {
+		mu.Lock()
+		defer mu.Unlock()
+		return status, nil
+	}))
+	limit := func() int {
+		ht.mu.Lock()
+		defer ht.mu.Unlock()
+		return ht.hosts["a.example"].limit
+	}
+
+	throttledGet(t, transport, "http://a.example/")
+	if limit() != 8 {
+		t.Fatalf("expected a limit of 8, got %d", limit())
+	}
+
+	mu.Lock()
+	status = http.StatusTooManyRequests
+	mu.Unlock()
+	for _, expected := range []int{4, 2, 1, 1} {
+		throttledGet(t, transport, "http://a.example/")
+		if limit() != expected {
+			t.Fatalf("expected a limit of %d after pushback, got %d", expected, limit())
+		}
+	}
+
+	// Recovery takes as many successes as the current limit
+	mu.Lock()
+	status = http.StatusOK
+	mu.Unlock()
+	for _, expected := range []int{2, 2, 3, 3, 3, 4} {
+		throttledGet(t, transport, "http://a.example/")
+		if limit() != expected {
+			t.Fatalf("expected a limit of %d while recovering, got %d", expected, limit())
+		}
+	}
+	for range 4 + 5 + 6 + 7 {
+		throttledGet(t, transport, "http://a.example/")
+	}
+	if limit() != 8 {
+		t.Errorf("expected the limit to recover to 8, got %d", limit())
+	}
+}
+
+func TestHostThrottleForbidden(t *testing.T) {
+	ht := &HostThrottle{MaxConcurrency: 8}
+	transport := ht.Wrap(statusTransport(func() (int, http.Header) {
+		return http.StatusForbidden, nil
+	}))
+	limit := func(host string) int {
+		ht.mu.Lock()
+		defer ht.mu.Unlock()
+		return ht.hosts[host].limit
+	}
+
+	// A 403 is an access failure, unless the host said otherwise
+	throttledGet(t, transport, "http://denied.example/")
+	if limit("denied.example") != 8 {
+		t.Errorf("expected a 403 to leave the limit at 8, got %d", limit("denied.example"))
+	}
+
+	ThrottleOnForbidden("forbidden.example")
+	throttledGet(t, transport, "http://forbidden.example/")
+	if limit("forbidden.example") != 4 {
+		t.Errorf("expected a 403 to halve the limit of an opted in host, got %d", limit("forbidden.example"))
+	}
+}
+
+func TestHostThrottleHoldsInFlight(t *testing.T) {
+	ht := &HostThrottle{MaxConcurrency: 1}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	transport := ht.Wrap(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-release
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		req, _ := http.NewRequest(http.MethodGet, "http://a.example/", nil)
+		transport.RoundTrip(req)
+	}()
+	<-started
+
+	// The second request waits for the first one, or for its context
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://a.example/", nil)
+	_, err := transport.RoundTrip(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the request to be held, got %v", err)
+	}
+	close(release)
+	<-done
+}
+
+func TestHostThrottleRetryAfter(t *testing.T) {
+	ht := &HostThrottle{}
+
+	first := true
+	transport := ht.Wrap(statusTransport(func() (int, http.Header) {
+		if first {
+			first = false
+			return http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}}
+		}
+		return http.StatusOK, nil
+	}))
+
+	start := time.Now()
+	throttledGet(t, transport, "http://a.example/")
+	throttledGet(t, transport, "http://a.example/")
+	elapsed := time.Since(start)
+	if elapsed < 900*time.Millisecond {
+		t.Errorf("Retry-After was not honored, the second request came after %s", elapsed)
+	}
+
+	// The pause is capped
+	ht = &HostThrottle{MaxRetryAfter: 10 * time.Millisecond}
+	first = true
+	transport = ht.Wrap(statusTransport(func() (int, http.Header) {
+		if first {
+			first = false
+			return http.StatusServiceUnavailable, http.Header{"Retry-After": {"3600"}}
+		}
+		return http.StatusOK, nil
+	}))
+	start = time.Now()
+	throttledGet(t, transport, "http://a.example/")
+	throttledGet(t, transport, "http://a.example/")
+	elapsed = time.Since(start)
+	if elapsed > time.Second {
+		t.Errorf("Retry-After was not capped, the second request came after %s", elapsed)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-5", 0, true},
+		{"Wed, 01 Jan 2025 12:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 01 Jan 2025 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, test := range tests {
+		wait, ok := retryAfter(test.value, now)
+		if wait != test.expected || ok != test.ok {
+			t.Errorf("%q: expected %s %v, got %s %v", test.value, test.expected, test.ok, wait, ok)
+		}
+	}
+}
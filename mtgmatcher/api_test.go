@@ -60,6 +60,8 @@ func BenchmarkSearchRegexp(b *testing.B) {
 }
 
 func TestSearchRegexp(t *testing.T) {
+	mtgmatcher.NeedFullDatastore(t)
+
 	hashes, err := mtgmatcher.SearchRegexp("Lotus$")
 	if err != nil {
 		t.Error("FAIL: Unexpected", err)
@@ -86,6 +88,8 @@ func TestSearchRegexp(t *testing.T) {
 }
 
 func TestSearchFlavor(t *testing.T) {
+	mtgmatcher.NeedFullDatastore(t)
+
 	hashes, err := mtgmatcher.SearchEquals("Stay with Me")
 	if err != nil {
 		t.Error("FAIL: Unexpected", err)
@@ -111,6 +115,8 @@ func TestSearchFlavor(t *testing.T) {
 }
 
 func TestSearchHalfName(t *testing.T) {
+	mtgmatcher.NeedFullDatastore(t)
+
 	hashes, err := mtgmatcher.SearchEquals("Jonathan Harker")
 	if err != nil {
 		t.Error("FAIL: Unexpected", err)
@@ -136,6 +142,8 @@ func TestSearchHalfName(t *testing.T) {
 }
 
 func TestPrintings(t *testing.T) {
+	mtgmatcher.NeedFullDatastore(t)
+
 	setCodes, _ := mtgmatcher.Printings4Card("Black Lotus")
 	if len(setCodes) != 6 {
 		t.Error("FAIL: Printings should be exactly 6 results, got " + fmt.Sprint(setCodes))
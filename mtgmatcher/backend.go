@@ -350,6 +350,10 @@ type Backend struct {
 	// game's datastore loader via SetRules.
 	rules         GameRules
 	knownFinishes map[string]bool
+
+	// Trigrams of every card and product name, for SearchFuzzy, built
+	// along with the rules
+	fuzzy *fuzzyIndex
 }
 
 // Logger receives the matcher's diagnostics. It discards them until
@@ -392,6 +396,13 @@ func SetGlobalDatastore(b *Backend) {
 	defaultBackend = *b
 }
 
+// GlobalDatastore returns the datastore installed by SetGlobalDatastore, for
+// the packages building on top of the matcher that take a Backend. It is
+// shared with every caller and must not be modified.
+func GlobalDatastore() *Backend {
+	return &defaultBackend
+}
+
 // SetGlobalLogger points the matcher's diagnostics at a logger of your own.
 func SetGlobalLogger(userLogger *log.Logger) {
 	Logger = userLogger
@@ -412,9 +423,7 @@ func SetGlobalLogger(userLogger *log.Logger) {
 // name as written leaves it holding something else.
 func (b *Backend) AddName(name string) {
 	if b.seenNames == nil {
-		b.seenNames = map[string]bool{}
-		b.seenLowerNames = map[string]bool{}
-		b.seenCanonicalNames = map[string]bool{}
+		b.indexNames()
 	}
 	if n := Normalize(name); !b.seenNames[n] {
 		b.seenNames[n] = true
@@ -429,3 +438,20 @@ func (b *Backend) AddName(name string) {
 		b.AllCanonicalNames = append(b.AllCanonicalNames, name)
 	}
 }
+
+// indexNames rebuilds the sets AddName dedupes on out of the name lists they
+// mirror, for a Backend whose lists were filled without it.
+func (b *Backend) indexNames() {
+	b.seenNames = make(map[string]bool, len(b.AllNames))
+	for _, name := range b.AllNames {
+		b.seenNames[name] = true
+	}
+	b.seenLowerNames = make(map[string]bool, len(b.AllLowerNames))
+	for _, name := range b.AllLowerNames {
+		b.seenLowerNames[name] = true
+	}
+	b.seenCanonicalNames = make(map[string]bool, len(b.AllCanonicalNames))
+	for _, name := range b.AllCanonicalNames {
+		b.seenCanonicalNames[name] = true
+	}
+}
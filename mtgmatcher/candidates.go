@@ -0,0 +1,226 @@
+package mtgmatcher
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// The weights of the parts of MatchCandidate.Score, adding up to one.
+const (
+	candidateKeptWeight      = 0.4
+	candidateVariationWeight = 0.3
+	candidateEditionWeight   = 0.2
+	candidateFinishWeight    = 0.1
+)
+
+// MatchCandidate is a printing an input could describe, with the evidence
+// for it.
+type MatchCandidate struct {
+	// The uuid of the printing in the finish asked for, or in the one it
+	// is sold in when it is not sold in that
+	UUID string `json:"uuid"`
+
+	// How well the printing fits the input, from 0 to 1
+	Score float64 `json:"score"`
+
+	// Whether the matcher itself kept the printing: it is what Match
+	// returns, or one of the printings of its AliasingError
+	Kept bool `json:"kept"`
+
+	// The words of the variation the printing accounts for, by number,
+	// set, promo type, frame effect, border, finish, language or artist,
+	// and the ones it does not
+	Explained   []string `json:"explained,omitempty"`
+	Unexplained []string `json:"unexplained,omitempty"`
+
+	// 1 when the edition names the set, 0.5 when it names part of it, 0
+	// when it names something else or nothing
+	EditionMatch float64 `json:"edition_match"`
+
+	// When the printing was released
+	ReleaseDate time.Time `json:"release_date"`
+
+	// Whether the printing is sold in the finish asked for
+	FinishMatch bool `json:"finish_match"`
+}
+
+// MatchCandidates resolves inCard to its candidate printings, using the
+// default datastore. See the method.
+func MatchCandidates(inCard *InputCard) ([]MatchCandidate, error) {
+	return defaultBackend.MatchCandidates(inCard)
+}
+
+// MatchCandidates runs Match on inCard and, rather than a single uuid or an
+// AliasingError, returns every printing the last MatchInSet pass considered,
+// scored and sorted best first; ties go to the newest printing. A caller can
+// accept the first candidate when it is kept and scores clearly above the
+// second, and queue the input for review otherwise.
+//
+// The score adds up whether the matcher kept the printing, the share of the
+// variation it explains, how closely the edition names its set, and whether
+// it is sold in the finish asked for. An error is only returned when the
+// match failed before any printing was considered, such as for an unknown
+// name or an unsupported card. Like Match, inCard is normalized in place.
+func (b *Backend) MatchCandidates(inCard *InputCard) ([]MatchCandidate, error) {
+	trace := &MatchTrace{}
+	cardID, err := b.match(inCard, trace)
+
+	var alias *AliasingError
+	kept := map[string]bool{}
+	if err == nil || errors.As(err, &alias) {
+		for _, card := range trace.kept {
+			kept[card.UUID] = true
+		}
+	}
+
+	pool := trace.pool
+	byID := len(pool) == 0 && cardID != ""
+	if byID {
+		// Found by id, so no pass ran
+		co := b.UUIDs[cardID]
+		pool = []Card{co.Card}
+		kept[co.UUID] = true
+	}
+	if len(pool) == 0 {
+		if err == nil {
+			err = ErrCardDoesNotExist
+		}
+		return nil, err
+	}
+
+	tokens := variationTokens(inCard.Variation)
+
+	var candidates []MatchCandidate
+	for _, card := range pool {
+		set := b.Sets[card.SetCode]
+
+		candidate := MatchCandidate{
+			UUID:        b.output(card, inCard.Foil, inCard.IsEtched()),
+			Kept:        kept[card.UUID],
+			FinishMatch: b.finishMatch(inCard, &card),
+		}
+		switch {
+		case byID:
+			candidate.UUID = cardID
+		case inCard.Finish != "" && candidate.FinishMatch:
+			candidate.UUID = b.FinishUUID(&card, inCard.Finish)
+		}
+		if set != nil {
+			candidate.ReleaseDate = set.ReleaseDateTime
+			switch {
+			case Equals(set.Name, inCard.Edition) || strings.EqualFold(set.Code, inCard.Edition):
+				candidate.EditionMatch = 1
+			case inCard.Edition != "" && Contains(set.Name, inCard.Edition):
+				candidate.EditionMatch = 0.5
+			}
+		}
+		if card.OriginalReleaseDate != "" {
+			date, err := time.Parse("2006-01-02", card.OriginalReleaseDate)
+			if err == nil {
+				candidate.ReleaseDate = date
+			}
+		}
+		for _, token := range tokens {
+			if explainsToken(&card, set, token) {
+				candidate.Explained = append(candidate.Explained, token)
+			} else {
+				candidate.Unexplained = append(candidate.Unexplained, token)
+			}
+		}
+
+		if candidate.Kept {
+			candidate.Score += candidateKeptWeight
+		}
+		if len(tokens) == 0 {
+			candidate.Score += candidateVariationWeight / 2
+		} else {
+			candidate.Score += candidateVariationWeight * float64(len(candidate.Explained)) / float64(len(tokens))
+		}
+		if inCard.Edition == "" {
+			candidate.Score += candidateEditionWeight / 2
+		} else {
+			candidate.Score += candidateEditionWeight * candidate.EditionMatch
+		}
+		if candidate.FinishMatch {
+			candidate.Score += candidateFinishWeight
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		if !candidates[i].ReleaseDate.Equal(candidates[j].ReleaseDate) {
+			return candidates[i].ReleaseDate.After(candidates[j].ReleaseDate)
+		}
+		return candidates[i].UUID < candidates[j].UUID
+	})
+
+	return candidates, nil
+}
+
+// finishMatch reports whether card is sold in the finish inCard asks for,
+// by name or by flags.
+func (b *Backend) finishMatch(inCard *InputCard, card *Card) bool {
+	switch {
+	case inCard.Finish != "":
+		return b.FinishUUID(card, inCard.Finish) != ""
+	case inCard.IsEtched():
+		return card.HasFinish(FinishEtched)
+	case inCard.Foil:
+		return card.HasFinish(FinishFoil)
+	}
+	return card.HasFinish(FinishNonfoil)
+}
+
+// variationTokens splits a variation into the lower case words a printing may
+// explain, dropping the ones too short to mean anything.
+func variationTokens(variation string) []string {
+	var tokens []string
+	fields := strings.FieldsFunc(strings.ToLower(variation), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, field := range fields {
+		if field == "the" || field == "of" || field == "and" {
+			continue
+		}
+		if len(field) < 2 && !unicode.IsDigit(rune(field[0])) {
+			continue
+		}
+		// Normalize drops symbols, and an empty word would explain anything
+		if Normalize(field) == "" {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+// explainsToken reports whether a word of a variation describes card.
+func explainsToken(card *Card, set *Set, token string) bool {
+	number := strings.TrimLeft(strings.ToLower(card.Number), "0")
+	if strings.TrimLeft(token, "0") == number {
+		return true
+	}
+	if set != nil && (Contains(set.Name, token) || strings.EqualFold(set.Code, token)) {
+		return true
+	}
+	for _, props := range [][]string{card.PromoTypes, card.FrameEffects, card.Finishes, card.Subsets} {
+		for _, prop := range props {
+			if Contains(prop, token) {
+				return true
+			}
+		}
+	}
+	for _, prop := range []string{card.BorderColor, card.Language, card.Artist, card.Watermark} {
+		if prop != "" && Contains(prop, token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package mtgmatcher_test
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+)
+
+func TestMatchCandidates(t *testing.T) {
+	card := mtgmatcher.InputCard{
+		Name:      "Llanowar Elves",
+		Edition:   "FDN",
+		Variation: "227 showcase",
+		Foil:      true,
+	}
+	candidates, err := mtgmatcher.MatchCandidates(&card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sort.SliceIsSorted(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	}) {
+		t.Errorf("candidates are not sorted by score: %v", candidates)
+	}
+
+	best := candidates[0]
+	co, err := mtgmatcher.GetUUID(best.UUID)
+	if err != nil || co.Number != "227" || !co.Foil {
+		t.Fatalf("expected the foil 227 first, got %+v", best)
+	}
+	if !best.Kept || best.EditionMatch != 1 || !best.FinishMatch || best.ReleaseDate.IsZero() {
+		t.Errorf("unexpected evidence %+v", best)
+	}
+	if !slices.Equal(best.Explained, []string{"227"}) || !slices.Equal(best.Unexplained, []string{"showcase"}) {
+		t.Errorf("unexpected tokens %v %v", best.Explained, best.Unexplained)
+	}
+	if math.Abs(best.Score-0.85) > 1e-9 {
+		t.Errorf("expected a score of 0.85, got %v", best.Score)
+	}
+
+	// Found by id, the candidate is the id itself
+	card = mtgmatcher.InputCard{ID: best.UUID, Foil: true}
+	candidates, err = mtgmatcher.MatchCandidates(&card)
+	if err != nil || len(candidates) != 1 || candidates[0].UUID != best.UUID || !candidates[0].Kept {
+		t.Errorf("unexpected id candidates %v %v", candidates, err)
+	}
+
+	card = mtgmatcher.InputCard{Name: "Llanowar Elfs of Nowhere"}
+	_, err = mtgmatcher.MatchCandidates(&card)
+	if err != mtgmatcher.ErrCardDoesNotExist {
+		t.Errorf("expected no candidates for an unknown name, got %v", err)
+	}
+}
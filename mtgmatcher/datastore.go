@@ -1,6 +1,7 @@
 package mtgmatcher
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
 	"fmt"
@@ -12,8 +13,9 @@ import (
 type GameLoader func(io.Reader) (*Backend, error)
 
 type registeredGame struct {
-	name string
-	load GameLoader
+	name  string
+	load  GameLoader
+	rules GameRules
 }
 
 var registeredGames []registeredGame
@@ -35,6 +37,23 @@ func RegisterGame(name string, load GameLoader) {
 	registeredGames = append(registeredGames, registeredGame{name: name, load: load})
 }
 
+// RegisterRules records the GameRules the named game's loader attaches, so
+// that a snapshot of one of its Backends is reloaded with them. Game packages
+// call this from their init(), after RegisterGame. It panics on nil rules or
+// a game that is not registered.
+func RegisterRules(name string, rules GameRules) {
+	if rules == nil {
+		panic("mtgmatcher: RegisterRules rules are nil for " + name)
+	}
+	for i := range registeredGames {
+		if registeredGames[i].name == name {
+			registeredGames[i].rules = rules
+			return
+		}
+	}
+	panic("mtgmatcher: RegisterRules called for unregistered game " + name)
+}
+
 // RegisteredGames returns the names of the registered games in registration
 // order.
 func RegisteredGames() []string {
@@ -49,7 +68,9 @@ func RegisteredGames() []string {
 // and installs it as the global backend. At least one game package must be
 // blank-imported. Preserved for source compatibility with the pre-sub-package
 // loading API: each registered loader is tried in registration order and the
-// first that succeeds wins (loaders reject formats they don't recognize).
+// first that succeeds wins (loaders reject formats they don't recognize). A
+// snapshot written by WriteSnapshot is recognized before any of them, and
+// reloaded with the rules of its game.
 func LoadDatastore(reader io.Reader) error {
 	if len(registeredGames) == 0 {
 		return errors.New("mtgmatcher: no game registered; blank-import a game package such as github.com/mtgban/go-mtgban/mtgmatcher/magic")
@@ -58,6 +79,14 @@ func LoadDatastore(reader io.Reader) error {
 	if err != nil {
 		return err
 	}
+	if isSnapshot(data) {
+		b, err := LoadSnapshot(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		SetGlobalDatastore(b)
+		return nil
+	}
 	var firstErr error
 	for _, g := range registeredGames {
 		b, err := g.load(bytes.NewReader(data))
@@ -86,12 +115,26 @@ func LoadDatastoreFile(filename string) error {
 }
 
 // Open loads the named game's datastore explicitly (sql.Open style) and returns
-// the Backend without installing it as the global one.
+// the Backend without installing it as the global one. The datastore may be a
+// snapshot of that game.
 func Open(name string, reader io.Reader) (*Backend, error) {
 	for _, g := range registeredGames {
-		if g.name == name {
-			return g.load(reader)
+		if g.name != name {
+			continue
+		}
+		buffered := bufio.NewReader(reader)
+		header, _ := buffered.Peek(len(snapshotMagic))
+		if !isSnapshot(header) {
+			return g.load(buffered)
+		}
+		b, game, err := readSnapshot(buffered)
+		if err != nil {
+			return nil, err
+		}
+		if game != name {
+			return nil, fmt.Errorf("mtgmatcher: the snapshot is a %s datastore, not %s", game, name)
 		}
+		return b, nil
 	}
 	return nil, fmt.Errorf("mtgmatcher: unknown game %q (registered: %v)", name, RegisteredGames())
 }
@@ -0,0 +1,16 @@
+package mtgmatcher
+
+import (
+	"os"
+	"testing"
+)
+
+// NeedFullDatastore skips t unless ALLPRINTINGS5_PATH is set. Without it
+// TestMain loads the small datastore in testdata, which holds too few cards
+// for the tests probing real printings.
+func NeedFullDatastore(t *testing.T) {
+	t.Helper()
+	if os.Getenv("ALLPRINTINGS5_PATH") == "" {
+		t.Skip("ALLPRINTINGS5_PATH not set; skipping a test of the full datastore")
+	}
+}
@@ -0,0 +1,176 @@
+package mtgmatcher
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// MatchTrace is the account of one Match, stage by stage, as MatchExplain
+// records it.
+type MatchTrace struct {
+	// The input as the caller sent it
+	Input InputCard `json:"input"`
+
+	// Every stage the match went through, in order
+	Steps []MatchStep `json:"steps"`
+
+	// The printings of the last MatchInSet pass, and the ones the filters
+	// after it left, for MatchCandidates
+	pool []Card
+	kept []Card
+}
+
+// MatchStep is one stage of a match. Hooks of the GameRules record the input
+// they received and what they left of it, since they may rewrite it.
+type MatchStep struct {
+	// Name of the stage, the GameRules method for the hooks
+	Stage string `json:"stage"`
+
+	// The input as the stage received it
+	Input InputCard `json:"input"`
+
+	// The input as the stage left it, when it changed
+	Output *InputCard `json:"output,omitempty"`
+
+	// Set codes still in play after the stage
+	Printings []string `json:"printings,omitempty"`
+
+	// Printings still in play after the stage
+	Candidates []TraceCard `json:"candidates,omitempty"`
+
+	// What the stage concluded, when it is not one of the above
+	Result string `json:"result,omitempty"`
+}
+
+// TraceCard is a candidate printing in a MatchStep.
+type TraceCard struct {
+	UUID     string `json:"uuid"`
+	Name     string `json:"name"`
+	SetCode  string `json:"set_code"`
+	Number   string `json:"number"`
+	Language string `json:"language,omitempty"`
+}
+
+func (tc TraceCard) String() string {
+	return fmt.Sprintf("%s|%s|%s %s %s", tc.Name, tc.SetCode, tc.Number, tc.Language, tc.UUID)
+}
+
+// MatchExplain matches inCard like Match does, using the default datastore,
+// and returns the trace of how it got there. See the method.
+func MatchExplain(inCard *InputCard) (string, *MatchTrace, error) {
+	return defaultBackend.MatchExplain(inCard)
+}
+
+// MatchExplain matches inCard like Match does, and returns the trace of how it
+// got there: the language resolution, the id lookup, each GameRules hook with
+// its input and output, the printings left by FilterPrintings, the candidates
+// of each MatchInSet pass, of FilterCards and of the language filter, and the
+// verdict. It is meant for finding which stage lost a card, not for bulk
+// matching, as every stage copies the input.
+func (b *Backend) MatchExplain(inCard *InputCard) (string, *MatchTrace, error) {
+	trace := &MatchTrace{Input: *inCard}
+	cardID, err := b.match(inCard, trace)
+	result := cardID
+	if err != nil {
+		result = err.Error()
+	}
+	trace.result("Verdict", inCard, result)
+	return cardID, trace, err
+}
+
+// The recording methods below record nothing on a nil trace, which is what
+// Match passes.
+
+func (t *MatchTrace) hook(stage string, inCard *InputCard, hook func()) {
+	if t == nil {
+		hook()
+		return
+	}
+	step := MatchStep{Stage: stage, Input: *inCard}
+	hook()
+	if *inCard != step.Input {
+		output := *inCard
+		step.Output = &output
+	}
+	t.Steps = append(t.Steps, step)
+}
+
+func (t *MatchTrace) result(stage string, inCard *InputCard, result string) {
+	if t == nil {
+		return
+	}
+	t.Steps = append(t.Steps, MatchStep{Stage: stage, Input: *inCard, Result: result})
+}
+
+func (t *MatchTrace) flag(stage string, inCard *InputCard, value bool) {
+	if t == nil {
+		return
+	}
+	t.result(stage, inCard, strconv.FormatBool(value))
+}
+
+func (t *MatchTrace) printings(stage string, inCard *InputCard, printings []string) {
+	if t == nil {
+		return
+	}
+	t.Steps = append(t.Steps, MatchStep{Stage: stage, Input: *inCard})
+	t.lastPrintings(printings)
+}
+
+func (t *MatchTrace) cards(stage string, inCard *InputCard, cards []Card) {
+	if t == nil {
+		return
+	}
+	t.Steps = append(t.Steps, MatchStep{Stage: stage, Input: *inCard})
+	t.lastCards(cards)
+}
+
+// lastPrintings adds the printings left by the last stage recorded.
+func (t *MatchTrace) lastPrintings(printings []string) {
+	if t == nil {
+		return
+	}
+	step := &t.Steps[len(t.Steps)-1]
+	step.Printings = printings
+	step.Result = fmt.Sprintf("%d printings", len(printings))
+}
+
+// lastCards adds the candidates left by the last stage recorded.
+func (t *MatchTrace) lastCards(cards []Card) {
+	if t == nil {
+		return
+	}
+	t.kept = cards
+	step := &t.Steps[len(t.Steps)-1]
+	step.Candidates = nil
+	for _, card := range cards {
+		step.Candidates = append(step.Candidates, TraceCard{
+			UUID:     card.UUID,
+			Name:     card.Name,
+			SetCode:  card.SetCode,
+			Number:   card.Number,
+			Language: card.Language,
+		})
+	}
+	step.Result = fmt.Sprintf("%d candidates", len(cards))
+}
+
+// cardSet records a pass of MatchInSet, in set code order.
+func (t *MatchTrace) cardSet(stage string, inCard *InputCard, cardSet map[string][]Card) {
+	if t == nil {
+		return
+	}
+	var setCodes []string
+	for setCode := range cardSet {
+		setCodes = append(setCodes, setCode)
+	}
+	sort.Strings(setCodes)
+	var cards []Card
+	for _, setCode := range setCodes {
+		cards = append(cards, cardSet[setCode]...)
+	}
+	t.cards(stage, inCard, cards)
+	t.Steps[len(t.Steps)-1].Printings = setCodes
+	t.pool = cards
+}
@@ -0,0 +1,83 @@
+package mtgmatcher_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+)
+
+func traceStep(trace *mtgmatcher.MatchTrace, stage string) *mtgmatcher.MatchStep {
+	for i := range trace.Steps {
+		if trace.Steps[i].Stage == stage {
+			return &trace.Steps[i]
+		}
+	}
+	return nil
+}
+
+func TestMatchExplain(t *testing.T) {
+	input := mtgmatcher.InputCard{
+		Name:      "llanowar elves",
+		Edition:   "Foundations",
+		Variation: "227",
+	}
+
+	card := input
+	expected, err := mtgmatcher.Match(&card)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	card = input
+	cardID, trace, err := mtgmatcher.MatchExplain(&card)
+	if err != nil || cardID != expected {
+		t.Fatalf("expected %s like Match, got %s %v", expected, cardID, err)
+	}
+	if trace.Input != input {
+		t.Errorf("the trace does not start from the input: %+v", trace.Input)
+	}
+
+	for _, stage := range []string{"Language", "Prefilter", "IsUnsupported", "CanonicalNames", "AdjustEdition", "Printings4Card", "FilterCards", "MissingPromoTag", "Verdict"} {
+		if traceStep(trace, stage) == nil {
+			t.Errorf("stage %s was not recorded", stage)
+		}
+	}
+
+	step := traceStep(trace, "CanonicalNames")
+	if step != nil && step.Result != "Llanowar Elves" {
+		t.Errorf("unexpected canonical name %q", step.Result)
+	}
+	found := false
+	for _, step := range trace.Steps {
+		if strings.HasPrefix(step.Stage, "MatchInSet") && slices.Contains(step.Printings, "FDN") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("no MatchInSet pass considered FDN")
+	}
+	step = traceStep(trace, "FilterCards")
+	if step != nil && (len(step.Candidates) != 1 || step.Candidates[0].Number != "227") {
+		t.Errorf("unexpected FilterCards candidates %v", step.Candidates)
+	}
+	if last := trace.Steps[len(trace.Steps)-1]; last.Stage != "Verdict" || last.Result != cardID {
+		t.Errorf("unexpected verdict %+v", last)
+	}
+
+	// An unknown name is lost after AdjustName, and the trace ends there
+	card = input
+	card.Name = "Llanowar Elfs of Nowhere"
+	_, trace, err = mtgmatcher.MatchExplain(&card)
+	if !errors.Is(err, mtgmatcher.ErrCardDoesNotExist) {
+		t.Fatalf("expected a card that does not exist, got %v", err)
+	}
+	if traceStep(trace, "AdjustName") == nil || traceStep(trace, "CanonicalNames") != nil {
+		t.Errorf("expected the trace to stop at AdjustName, got %+v", trace.Steps)
+	}
+	if last := trace.Steps[len(trace.Steps)-1]; last.Result != mtgmatcher.ErrCardDoesNotExist.Error() {
+		t.Errorf("unexpected verdict %+v", last)
+	}
+}
@@ -0,0 +1,228 @@
+package mtgmatcher
+
+import (
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+// fuzzyIndex files every card and product name under the trigrams of its
+// normalized form, so that a misspelled name finds the few names worth
+// measuring against it without scanning all of them.
+type fuzzyIndex struct {
+	names    []fuzzyName
+	trigrams map[string][]int32
+
+	// Per-name shared trigram counts for search, reused across lookups and
+	// left zeroed after each, so a lookup only touches the names it finds
+	counts sync.Pool
+}
+
+type fuzzyName struct {
+	name       string
+	normalized string
+	sealed     bool
+	grams      int
+}
+
+// fuzzyResult is a name found by fuzzyIndex.search, with its edit distance to
+// the query and the share of trigrams they have in common.
+type fuzzyResult struct {
+	*fuzzyName
+	distance int
+	dice     float64
+}
+
+// fuzzyCandidates is how many names, per result asked, are measured by edit
+// distance after ranking them by trigrams.
+const fuzzyCandidates = 8
+
+// newFuzzyIndex indexes the canonical card and product names of b.
+func newFuzzyIndex(b *Backend) *fuzzyIndex {
+	idx := &fuzzyIndex{
+		trigrams: map[string][]int32{},
+	}
+	add := func(name string, sealed bool) {
+		normalized := Normalize(name)
+		if normalized == "" {
+			return
+		}
+		grams := trigrams(normalized)
+		for _, gram := range grams {
+			idx.trigrams[gram] = append(idx.trigrams[gram], int32(len(idx.names)))
+		}
+		idx.names = append(idx.names, fuzzyName{
+			name:       name,
+			normalized: normalized,
+			sealed:     sealed,
+			grams:      len(grams),
+		})
+	}
+	for _, name := range b.AllCanonicalNames {
+		add(name, false)
+	}
+	for _, name := range b.AllCanonicalSealed {
+		add(name, true)
+	}
+	return idx
+}
+
+// trigrams returns the distinct three letter runs of str, padded so that its
+// first and last letters weigh as much as the others.
+func trigrams(str string) []string {
+	runes := []rune("^^" + str + "$")
+	seen := map[string]bool{}
+	var grams []string
+	for i := 0; i+3 <= len(runes); i++ {
+		gram := string(runes[i : i+3])
+		if seen[gram] {
+			continue
+		}
+		seen[gram] = true
+		grams = append(grams, gram)
+	}
+	return grams
+}
+
+// search returns up to maxResults names close to query, closest first, among
+// the sealed or the card names as keep says.
+func (idx *fuzzyIndex) search(query string, maxResults int, keep func(*fuzzyName) bool) []fuzzyResult {
+	query = Normalize(query)
+	if idx == nil || query == "" || maxResults <= 0 {
+		return nil
+	}
+
+	counts, _ := idx.counts.Get().([]uint16)
+	if len(counts) != len(idx.names) {
+		counts = make([]uint16, len(idx.names))
+	}
+	defer idx.counts.Put(counts)
+
+	grams := trigrams(query)
+	var found []int32
+	for _, gram := range grams {
+		for _, i := range idx.trigrams[gram] {
+			if counts[i] == 0 {
+				found = append(found, i)
+			}
+			counts[i]++
+		}
+	}
+
+	var results []fuzzyResult
+	for _, i := range found {
+		count := counts[i]
+		counts[i] = 0
+		name := &idx.names[i]
+		if keep != nil && !keep(name) {
+			continue
+		}
+		results = append(results, fuzzyResult{
+			fuzzyName: name,
+			dice:      2 * float64(count) / float64(len(grams)+name.grams),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].dice != results[j].dice {
+			return results[i].dice > results[j].dice
+		}
+		return results[i].name < results[j].name
+	})
+	if len(results) > maxResults*fuzzyCandidates {
+		results = results[:maxResults*fuzzyCandidates]
+	}
+
+	for i := range results {
+		results[i].distance = editDistance(query, results[i].normalized)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].distance < results[j].distance
+	})
+	if len(results) > maxResults {
+		results = results[:maxResults]
+	}
+	return results
+}
+
+// editDistance counts the insertions, deletions, substitutions and swaps of
+// two adjacent letters turning a into b.
+func editDistance(a, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	// Three rows are enough: the current one, and the two before it for
+	// swaps
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
+
+// SearchFuzzy returns up to maxResults card and product names close to name,
+// closest first, tolerating the typos the Search functions do not: a name is
+// found through the trigrams it shares with the query, then ranked by edit
+// distance once both are Normalize-d. Names come back as printed, ready for
+// SearchEquals or SearchSealedEquals. The index is built when the game's
+// loader attaches its rules.
+func (b *Backend) SearchFuzzy(name string, maxResults int) []string {
+	var names []string
+	for _, result := range b.fuzzy.search(name, maxResults, nil) {
+		names = append(names, result.name)
+	}
+	return names
+}
+
+// SearchFuzzy searches the default datastore's card and product names,
+// tolerating typos. See the method.
+func SearchFuzzy(name string, maxResults int) []string {
+	return defaultBackend.SearchFuzzy(name, maxResults)
+}
+
+// FuzzyCardName returns the one card name name is a likely typo of, for the
+// rules to fall back on when nothing else recognized a name. It only answers
+// when a single name is closest, within one edit for names of eight letters
+// or more and two for names of sixteen or more once normalized; shorter
+// names are too easy to land on another card.
+func (b *Backend) FuzzyCardName(name string) (string, bool) {
+	results := b.fuzzy.search(name, 2, func(fn *fuzzyName) bool {
+		return !fn.sealed
+	})
+	if len(results) == 0 {
+		return "", false
+	}
+
+	length := utf8.RuneCountInString(Normalize(name))
+	allowed := 0
+	switch {
+	case length >= 16:
+		allowed = 2
+	case length >= 8:
+		allowed = 1
+	}
+
+	best := results[0]
+	if best.distance > allowed {
+		return "", false
+	}
+	if len(results) > 1 && results[1].distance == best.distance {
+		return "", false
+	}
+	return best.name, true
+}
@@ -0,0 +1,34 @@
+package mtgmatcher_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+)
+
+func TestMatchFuzzyName(t *testing.T) {
+	tests := []struct {
+		input    mtgmatcher.InputCard
+		expected string
+	}{
+		{mtgmatcher.InputCard{Name: "Llanowar Elvs"}, "a0b0c0d0-0000-5000-8000-000000000227"},
+		{mtgmatcher.InputCard{Name: "Llanowar Elvs", Edition: "Foundations"}, "a0b0c0d0-0000-5000-8000-000000000227"},
+		{mtgmatcher.InputCard{Name: "Llanowar Elvs", Edition: "FDN"}, "a0b0c0d0-0000-5000-8000-000000000227"},
+		// A near miss is not a license to match a card the edition never had
+		{mtgmatcher.InputCard{Name: "Llanowar Elvs", Edition: "Alpha"}, ""},
+	}
+	for _, test := range tests {
+		card := test.input
+		cardID, err := testBackend.Match(&card)
+		if test.expected == "" {
+			if !errors.Is(err, mtgmatcher.ErrCardDoesNotExist) {
+				t.Errorf("%+v: expected a card that does not exist, got %s %v", test.input, cardID, err)
+			}
+			continue
+		}
+		if err != nil || cardID != test.expected {
+			t.Errorf("%+v: expected %s, got %s %v", test.input, test.expected, cardID, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package mtgmatcher
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"llanowarelves", "llanowarelves", 0},
+		{"llanowarelves", "llanowarelvs", 1},
+		{"llanowarelves", "llnaowarelves", 1},
+		{"counterspell", "countrespel", 2},
+		{"kitten", "sitting", 3},
+	}
+	for _, test := range tests {
+		distance := editDistance(test.a, test.b)
+		if distance != test.expected {
+			t.Errorf("%q %q: expected %d, got %d", test.a, test.b, test.expected, distance)
+		}
+	}
+}
+
+func TestSearchFuzzy(t *testing.T) {
+	b := &Backend{
+		AllCanonicalNames:  []string{"Llanowar Elves", "Llanowar Elite", "Counterspell", "Counterbalance", "Opt"},
+		AllCanonicalSealed: []string{"Foundations Play Booster Box"},
+	}
+	b.SetRules(nil)
+
+	results := b.SearchFuzzy("Llanowar Elvs", 2)
+	if !slices.Equal(results, []string{"Llanowar Elves", "Llanowar Elite"}) {
+		t.Errorf("unexpected results %v", results)
+	}
+	results = b.SearchFuzzy("foundation play boster box", 1)
+	if !slices.Equal(results, []string{"Foundations Play Booster Box"}) {
+		t.Errorf("unexpected sealed results %v", results)
+	}
+	if b.SearchFuzzy("", 5) != nil || b.SearchFuzzy("Opt", 0) != nil {
+		t.Error("an empty search returned results")
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Llanowar Elvs", "Llanowar Elves"},
+		{"Countre Spell", "Counterspell"},
+		{"Conutrebalance", ""},
+		{"Counterbalanse", "Counterbalance"},
+		{"Llanowar Eleves of Doom", ""},
+		{"Opx", ""},
+		{"Foundations Play Booster Bx", ""},
+	}
+	for _, test := range tests {
+		name, found := b.FuzzyCardName(test.name)
+		if name != test.expected || found != (test.expected != "") {
+			t.Errorf("%q: expected %q, got %q %v", test.name, test.expected, name, found)
+		}
+	}
+}
@@ -12,6 +12,8 @@ import (
 // card actually carrying the queried name, regardless of the bucket
 // order the load process produced.
 func TestPrintings4CardExactName(t *testing.T) {
+	NeedFullDatastore(t)
+
 	if len(GetUUIDs()) == 0 {
 		t.Skip("datastore not loaded")
 	}
@@ -233,6 +235,8 @@ func BenchmarkHasPrintingWide(b *testing.B) {
 // invariant - unlike TestHasPrintingEquivalence, whose seeded sample
 // reaches a given collision only by luck.
 func TestHasPrintingAnswersForTheNamedCard(t *testing.T) {
+	NeedFullDatastore(t)
+
 	uuids := GetUUIDs()
 	if len(uuids) == 0 {
 		t.Skip("datastore not loaded")
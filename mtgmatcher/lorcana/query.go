@@ -0,0 +1,19 @@
+package lorcana
+
+import (
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+	"github.com/mtgban/go-mtgban/mtgmatcher/query"
+)
+
+// The query keywords for the Lorcana vocabulary: the colors are inks, and
+// the franchise a card comes from is kept as its supertype.
+var queryPredicates = query.Predicates{
+	Keywords: map[string]query.Keyword{
+		"i":   inkQuery,
+		"ink": inkQuery,
+
+		"story": query.ListField(func(co *mtgmatcher.CardObject) []string { return co.Supertypes }),
+	},
+}
+
+var inkQuery = query.ListField(func(co *mtgmatcher.CardObject) []string { return co.Colors })
@@ -1,9 +1,15 @@
 package lorcana
 
-import "github.com/mtgban/go-mtgban/mtgmatcher"
+import (
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+	"github.com/mtgban/go-mtgban/mtgmatcher/query"
+)
 
 // Register the Lorcana datastore loader so that a blank import of this package
-// makes mtgmatcher.LoadDatastore able to auto-detect and load it.
+// makes mtgmatcher.LoadDatastore able to auto-detect and load it, and the
+// Lorcana keywords of mtgmatcher/query along with it.
 func init() {
 	mtgmatcher.RegisterGame("lorcana", Load)
+	mtgmatcher.RegisterRules("lorcana", Rules{})
+	query.Register(Rules{}, queryPredicates)
 }
@@ -1030,16 +1030,27 @@ func (ap *AllPrintings) newBackend() *mtgmatcher.Backend {
 		}
 	}
 
-	duplicate(ap.Data, "Legends Italian", "LEG", "ITA", "1995-09-01")
-	duplicate(ap.Data, "The Dark Italian", "DRK", "ITA", "1995-08-01")
-	duplicate(ap.Data, "Alternate Fourth Edition", "4ED", "ALT", "1995-04-01")
-	allSets = append(allSets, "LEGITA", "DRKITA", "4EDALT")
+	// The sets below may be missing from a trimmed datastore, like the ones
+	// scraper tests replay against
+	if duplicate(ap.Data, "Legends Italian", "LEG", "ITA", "1995-09-01") {
+		allSets = append(allSets, "LEGITA")
+	}
+	if duplicate(ap.Data, "The Dark Italian", "DRK", "ITA", "1995-08-01") {
+		allSets = append(allSets, "DRKITA")
+	}
+	if duplicate(ap.Data, "Alternate Fourth Edition", "4ED", "ALT", "1995-04-01") {
+		allSets = append(allSets, "4EDALT")
+	}
 
-	sldDupes := duplicateCards(ap.Data, "SLD", "JPN", sldJPNLangDupes)
-	ap.Data["SLD"].Cards = append(ap.Data["SLD"].Cards, sldDupes...)
+	if sld, found := ap.Data["SLD"]; found {
+		sldDupes := duplicateCards(ap.Data, "SLD", "JPN", sldJPNLangDupes)
+		sld.Cards = append(sld.Cards, sldDupes...)
+	}
 
-	purlDupes := duplicateCards(ap.Data, "PURL", "JPN", []string{"1"})
-	ap.Data["PURL"].Cards = append(ap.Data["PURL"].Cards, purlDupes...)
+	if purl, found := ap.Data["PURL"]; found {
+		purlDupes := duplicateCards(ap.Data, "PURL", "JPN", []string{"1"})
+		purl.Cards = append(purl.Cards, purlDupes...)
+	}
 
 	// Generate the unique identifiers for singles and products
 	uuids, allUUIDs, allSealedUUIDs, setUUIDs, setSealedUUIDs := generateUUIDsMap(ap.Data)
@@ -1212,6 +1223,9 @@ var mtgColorMap = map[string]int{
 }
 
 func fillinSLDdecks(set *Set) []string {
+	if set == nil {
+		return nil
+	}
 	var output []string
 	for _, product := range set.SealedProduct {
 		if strings.HasPrefix(product.Name, "Secret Lair Commander") {
@@ -1436,10 +1450,16 @@ var langs = map[string]string{
 	"ALT": "English",
 }
 
-// Duplicate an entire set of cards, using a custom code and a different language
-func duplicate(sets map[string]*Set, name, code, tag, date string) {
+// Duplicate an entire set of cards, using a custom code and a different
+// language, reporting whether the set to duplicate was found
+func duplicate(sets map[string]*Set, name, code, tag, date string) bool {
+	base, found := sets[code]
+	if !found {
+		return false
+	}
+
 	// Copy base set information
-	dup := *sets[code]
+	dup := *base
 
 	// Update with new info
 	dup.Name = name
@@ -1516,6 +1536,8 @@ func duplicate(sets map[string]*Set, name, code, tag, date string) {
 		}
 		dup.Cards[i].Identifiers = altIdentifiers
 	}
+
+	return true
 }
 
 // Duplicate certain cards within the same set according to the language tag
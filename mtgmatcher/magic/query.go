@@ -0,0 +1,157 @@
+package magic
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+	"github.com/mtgban/go-mtgban/mtgmatcher/query"
+)
+
+// The query keywords reading Magic cards the way Scryfall does: colors as
+// letters with set comparisons, rarities in order, and the flags MTGJSON
+// sets on its cards.
+var queryPredicates = query.Predicates{
+	Keywords: map[string]query.Keyword{
+		"c":     colorQuery(func(co *mtgmatcher.CardObject) []string { return co.Colors }, query.OpGreaterEqual),
+		"color": colorQuery(func(co *mtgmatcher.CardObject) []string { return co.Colors }, query.OpGreaterEqual),
+
+		"id":       colorQuery(func(co *mtgmatcher.CardObject) []string { return co.ColorIdentity }, query.OpLessEqual),
+		"ci":       colorQuery(func(co *mtgmatcher.CardObject) []string { return co.ColorIdentity }, query.OpLessEqual),
+		"identity": colorQuery(func(co *mtgmatcher.CardObject) []string { return co.ColorIdentity }, query.OpLessEqual),
+
+		"r":      rarityQuery,
+		"rarity": rarityQuery,
+
+		"border":    query.StringField(func(co *mtgmatcher.CardObject) string { return co.BorderColor }),
+		"wm":        query.StringField(func(co *mtgmatcher.CardObject) string { return co.Watermark }),
+		"watermark": query.StringField(func(co *mtgmatcher.CardObject) string { return co.Watermark }),
+	},
+	Is: map[string]query.Predicate{
+		"fullart": func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			return co.IsFullArt
+		},
+		"funny": func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			return co.IsFunny
+		},
+		"oversized": func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			return co.IsOversized
+		},
+		"alternative": func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			return co.IsAlternative
+		},
+		"gamechanger": func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			return co.IsGameChanger
+		},
+		"showcase": func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			return co.HasFrameEffect(FrameEffectShowcase)
+		},
+		"extendedart": func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			return co.HasFrameEffect(FrameEffectExtendedArt)
+		},
+		"borderless": func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			return co.BorderColor == BorderColorBorderless
+		},
+	},
+}
+
+// queryColors spells the colors the way MTGJSON does, by name and by the
+// two color pairs.
+var queryColors = map[string]string{
+	"white": "W",
+	"blue":  "U",
+	"black": "B",
+	"red":   "R",
+	"green": "G",
+
+	"azorius":  "WU",
+	"dimir":    "UB",
+	"rakdos":   "BR",
+	"gruul":    "RG",
+	"selesnya": "GW",
+	"orzhov":   "WB",
+	"izzet":    "UR",
+	"golgari":  "BG",
+	"boros":    "RW",
+	"simic":    "GU",
+}
+
+// colorQuery compares the colors field returns to the ones of the term as
+// sets, with ":" standing for defaultOp. The value is a run of WUBRG letters,
+// a color or pair name, or c (colorless) and m (multicolor).
+func colorQuery(field func(co *mtgmatcher.CardObject) []string, defaultOp query.Operator) query.Keyword {
+	return func(b *mtgmatcher.Backend, op query.Operator, value string) (query.Predicate, error) {
+		value = strings.ToLower(value)
+		switch value {
+		case "m", "multicolor":
+			if op != query.OpMatch && op != query.OpEqual {
+				return nil, fmt.Errorf("%s only takes \":\"", value)
+			}
+			return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+				return len(field(co)) > 1
+			}, nil
+		case "c", "colorless":
+			value = ""
+		}
+		letters, found := queryColors[value]
+		if !found {
+			letters = strings.ToUpper(value)
+		}
+		var wanted []string
+		for _, letter := range letters {
+			if !strings.ContainsRune("WUBRG", letter) {
+				return nil, fmt.Errorf("%q is not a color", value)
+			}
+			if !slices.Contains(wanted, string(letter)) {
+				wanted = append(wanted, string(letter))
+			}
+		}
+
+		// Every card has at least no colors, so colorless asks for exactly
+		// none whatever ":" stands for
+		if op == query.OpMatch && len(wanted) > 0 {
+			op = defaultOp
+		}
+		return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			colors := field(co)
+			common := 0
+			for _, color := range colors {
+				if slices.Contains(wanted, color) {
+					common++
+				}
+			}
+			subset := common == len(colors)
+			superset := common == len(wanted)
+			switch op {
+			case query.OpMatch, query.OpEqual:
+				return subset && superset
+			case query.OpLess:
+				return subset && !superset
+			case query.OpLessEqual:
+				return subset
+			case query.OpGreater:
+				return superset && !subset
+			}
+			return superset
+		}, nil
+	}
+}
+
+// queryRarities are the rarities in order, each also going by its first
+// letter.
+var queryRarities = []string{"common", "uncommon", "rare", "mythic", "special", "bonus"}
+
+func rarityQuery(b *mtgmatcher.Backend, op query.Operator, value string) (query.Predicate, error) {
+	value = strings.ToLower(value)
+	rank := slices.IndexFunc(queryRarities, func(rarity string) bool {
+		return rarity == value || rarity[:1] == value
+	})
+	if rank < 0 {
+		return nil, fmt.Errorf("%q is not a rarity", value)
+	}
+	return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		cardRank := slices.Index(queryRarities, co.Rarity)
+		return cardRank >= 0 && op.Compare(cardRank-rank)
+	}, nil
+}
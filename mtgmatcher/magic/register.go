@@ -1,9 +1,15 @@
 package magic
 
-import "github.com/mtgban/go-mtgban/mtgmatcher"
+import (
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+	"github.com/mtgban/go-mtgban/mtgmatcher/query"
+)
 
 // Register the Magic (MTGJSON) datastore loader so that a blank import of this
-// package makes mtgmatcher.LoadDatastore able to auto-detect and load it.
+// package makes mtgmatcher.LoadDatastore able to auto-detect and load it, and
+// the Magic keywords of mtgmatcher/query along with it.
 func init() {
 	mtgmatcher.RegisterGame("magic", Load)
+	mtgmatcher.RegisterRules("magic", Rules{})
+	query.Register(Rules{}, queryPredicates)
 }
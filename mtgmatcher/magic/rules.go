@@ -2131,6 +2131,29 @@ func (Rules) AdjustName(b *mtgmatcher.Backend, inCard *mtgmatcher.InputCard) {
 			return
 		}
 	}
+
+	// Last chance, the name may just be misspelled, as long as the card it
+	// stands for was printed in the edition asked, if any
+	name, found := b.FuzzyCardName(inCard.Name)
+	if found && (inCard.Edition == "" || printedIn(b, name, inCard.Edition)) {
+		inCard.Name = name
+	}
+}
+
+// printedIn tells whether a card named name has a printing in edition, by
+// set name or code.
+func printedIn(b *mtgmatcher.Backend, name, edition string) bool {
+	uuids, _ := b.SearchEquals(name)
+	for _, uuid := range uuids {
+		co, err := b.GetUUID(uuid)
+		if err != nil {
+			continue
+		}
+		if mtgmatcher.Equals(co.Edition, edition) || strings.EqualFold(co.SetCode, edition) {
+			return true
+		}
+	}
+	return false
 }
 
 // isBasicLand mirrors core's strict (exact-name) basic-land check for the
@@ -226,6 +226,11 @@ func (b *Backend) MatchID(inputID string, finishes ...bool) (string, error) {
 // one. The input is normalized in place, so a caller can see what the matcher
 // made of it.
 func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
+	return b.match(inCard, nil)
+}
+
+// match is Match recording its stages to trace, which may be nil.
+func (b *Backend) match(inCard *InputCard, trace *MatchTrace) (cardID string, err error) {
 	if b.Sets == nil {
 		return "", ErrDatastoreEmpty
 	}
@@ -236,32 +241,19 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 	}
 
 	// Set up language
-	if inCard.Language != "" {
-		lang, found := LanguageCode2LanguageTag[strings.ToLower(inCard.Language)]
-		if found {
-			inCard.Language = lang
-		} else {
-			for _, field := range strings.Fields(inCard.Language) {
-				field = Title(field)
-				if slices.Contains(allLanguageTags, field) {
-					inCard.Language = field
-					break
-				}
-			}
-		}
-	}
-	// Override if needed
-	for _, tag := range allLanguageTags {
-		if inCard.Contains(tag) {
-			inCard.Language = tag
-			break
-		}
-	}
+	trace.hook("Language", inCard, func() {
+		b.resolveLanguage(inCard)
+	})
 
 	// Look up by uuid
 	if inCard.ID != "" {
 		Logger.Printf("Performing id lookup")
 		outID, err := b.matchIDFor(inCard)
+		if err != nil {
+			trace.result("MatchID", inCard, err.Error())
+		} else {
+			trace.result("MatchID", inCard, outID)
+		}
 		// The wording cannot improve on a finish the printing does not
 		// carry: it would answer from the same printing, and the only
 		// answer it has is another finish's uuid. A name the game could not
@@ -317,7 +309,9 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 	// canonical-name lookup: Magic splits bracketed editions and parenthesized
 	// or dashed variants off the name, Lorcana only the parenthetical (its
 	// names are "Character - Title"), plus each game's token/name fixups.
-	rules.Prefilter(b, inCard)
+	trace.hook("Prefilter", inCard, func() {
+		rules.Prefilter(b, inCard)
+	})
 
 	// Re-check foil in case prefilter moved a finish hint into the variant.
 	if inCard.IsFoil() {
@@ -328,7 +322,9 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 	}
 
 	// Skip unsupported sets
-	if rules.IsUnsupported(b, inCard) {
+	unsupported := rules.IsUnsupported(b, inCard)
+	trace.flag("IsUnsupported", inCard, unsupported)
+	if unsupported {
 		return "", ErrUnsupported
 	}
 
@@ -337,7 +333,9 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 	if !found {
 		ogName := inCard.Name
 		// Fixup up the name and try again
-		rules.AdjustName(b, inCard)
+		trace.hook("AdjustName", inCard, func() {
+			rules.AdjustName(b, inCard)
+		})
 		if ogName != inCard.Name {
 			inCard.OriginalName = ogName
 			Logger.Printf("Adjusted name from '%s' to '%s'", ogName, inCard.Name)
@@ -356,10 +354,13 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 	// Restore the card to the canonical MTGJSON name
 	ogName = inCard.Name
 	inCard.Name = canonicalName
+	trace.result("CanonicalNames", inCard, canonicalName)
 
 	// Fix up edition
 	ogEdition := inCard.Edition
-	rules.AdjustEdition(b, inCard)
+	trace.hook("AdjustEdition", inCard, func() {
+		rules.AdjustEdition(b, inCard)
+	})
 	if ogName != inCard.Name {
 		Logger.Printf("Re-adjusted name from '%s' to '%s'", ogName, inCard.Name)
 	}
@@ -374,15 +375,18 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 	case (strings.Contains(strings.ToLower(inCard.Edition), "token") ||
 		strings.Contains(strings.ToLower(inCard.Variation), "token")) &&
 		!inCard.Contains("League"):
+		trace.result("Token gate", inCard, "true")
 		return "", ErrUnsupported
 	// For any unsupported set that wasn't processed previously
 	case inCard.Contains("Oversize") &&
 		!(inCard.Contains("Commander") || inCard.Contains("Vanguard") ||
 			inCard.Contains("Planechase") || inCard.Contains("Archenemy") ||
 			inCard.Contains("Player Rewards")):
+		trace.result("Oversize gate", inCard, "true")
 		return "", ErrUnsupported
 	// For any specific missing card
 	case rules.IsSpecificUnsupported(b, inCard):
+		trace.result("IsSpecificUnsupported", inCard, "true")
 		return "", ErrUnsupported
 	}
 
@@ -391,6 +395,7 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 		Logger.Println("Printings error:", err)
 		return "", err
 	}
+	trace.printings("Printings4Card", inCard, printings)
 
 	// If there are multiple printings of the card, filter out to the
 	// minimum common elements, using the rules defined.
@@ -398,7 +403,10 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 	// out unrelated editions.
 	Logger.Println("Processing", inCard, printings)
 	if len(printings) > 1 || strings.HasSuffix(ogName, "Token") {
-		printings = rules.FilterPrintings(b, inCard, printings)
+		trace.hook("FilterPrintings", inCard, func() {
+			printings = rules.FilterPrintings(b, inCard, printings)
+		})
+		trace.lastPrintings(printings)
 		Logger.Println("Filtered printings:", printings)
 
 		// Filtering was too aggressive or wrong data fed,
@@ -419,6 +427,7 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 	// Only one printing, it *has* to be it
 	if len(printings) == 1 {
 		cardSet[printings[0]] = b.MatchInSet(inCard.Name, printings[0])
+		trace.cardSet("MatchInSet single", inCard, cardSet)
 	} else if !inCard.PromoWildcard && !inCard.IsSecretLair() {
 		// If multiple printing, try filtering to the closest name
 		// described by the inCard.Edition.
@@ -461,6 +470,8 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 			}
 		}
 
+		trace.cardSet("MatchInSet exact", inCard, cardSet)
+
 		// Second loop, hope that a portion of the edition is in the set Name
 		// This may result in false positives under certain circumstances.
 		if len(cardSet) == 0 {
@@ -483,6 +494,7 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 					cardSet[setCode] = b.MatchInSet(inCard.Name, setCode)
 				}
 			}
+			trace.cardSet("MatchInSet heuristic", inCard, cardSet)
 		}
 	}
 
@@ -494,6 +506,7 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 		for _, setCode := range printings {
 			cardSet[setCode] = b.MatchInSet(inCard.Name, setCode)
 		}
+		trace.cardSet("MatchInSet all", inCard, cardSet)
 	}
 
 	// Log the candidate matches
@@ -509,7 +522,11 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 	// blindly (Lorcana enforces the collector number here, which the old
 	// single-card shortcut skipped, returning a wrong-numbered card).
 	Logger.Println("Now filtering...")
-	outCards := rules.FilterCards(b, inCard, cardSet)
+	var outCards []Card
+	trace.hook("FilterCards", inCard, func() {
+		outCards = rules.FilterCards(b, inCard, cardSet)
+	})
+	trace.lastCards(outCards)
 
 	Logger.Println("Post filtering status...")
 	for _, card := range outCards {
@@ -522,6 +539,7 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 			Logger.Println("Dropping a few extra entries...")
 			Logger.Println(outCards[1:])
 			outCards = []Card{outCards[0]}
+			trace.cards("World Championship", inCard, outCards)
 		}
 	}
 
@@ -538,6 +556,7 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 			filteredOutCards = append(filteredOutCards, card)
 		}
 		outCards = filteredOutCards
+		trace.cards("Language filter", inCard, outCards)
 	}
 
 	// Finish line
@@ -562,7 +581,9 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 		Logger.Println(inCard, "->", co)
 
 		// Validation step
-		if rules.MissingPromoTag(b, inCard, co) {
+		missing := rules.MissingPromoTag(b, inCard, co)
+		trace.flag("MissingPromoTag", inCard, missing)
+		if missing {
 			Logger.Println("...but it's invalid")
 			return "", ErrUnsupported
 		}
@@ -579,6 +600,33 @@ func (b *Backend) Match(inCard *InputCard) (cardID string, err error) {
 	return
 }
 
+// resolveLanguage turns the language of inCard into one of the tags the
+// datastore uses, from a code or from a tag named anywhere in it, and lets a
+// tag mentioned in the edition or variation override it.
+func (b *Backend) resolveLanguage(inCard *InputCard) {
+	if inCard.Language != "" {
+		lang, found := LanguageCode2LanguageTag[strings.ToLower(inCard.Language)]
+		if found {
+			inCard.Language = lang
+		} else {
+			for _, field := range strings.Fields(inCard.Language) {
+				field = Title(field)
+				if slices.Contains(allLanguageTags, field) {
+					inCard.Language = field
+					break
+				}
+			}
+		}
+	}
+	// Override if needed
+	for _, tag := range allLanguageTags {
+		if inCard.Contains(tag) {
+			inCard.Language = tag
+			break
+		}
+	}
+}
+
 // MatchInSet returns every printing in the set whose name is exactly the one
 // given. A combined name is matched on its first half alone.
 func (b *Backend) MatchInSet(cardName string, setCode string) (outCards []Card) {
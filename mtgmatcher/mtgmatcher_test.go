@@ -17,9 +17,11 @@ import (
 var testBackend *mtgmatcher.Backend
 
 func TestMain(m *testing.M) {
+	// Without a full datastore only the tests that fit the small one run,
+	// the others skip through NeedFullDatastore
 	datastorePath := os.Getenv("ALLPRINTINGS5_PATH")
 	if datastorePath == "" {
-		log.Fatalln("Need ALLPRINTINGS5_PATH variable set to run this suite")
+		datastorePath = "testdata/allprintings.json"
 	}
 
 	datastoreReader, err := os.Open(datastorePath)
@@ -0,0 +1,372 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+)
+
+// Operator is what joins a keyword to its value.
+type Operator string
+
+const (
+	OpMatch        Operator = ":"
+	OpEqual        Operator = "="
+	OpNotEqual     Operator = "!="
+	OpLess         Operator = "<"
+	OpLessEqual    Operator = "<="
+	OpGreater      Operator = ">"
+	OpGreaterEqual Operator = ">="
+)
+
+// Compare reports whether a card comparing to the value of a term as cmp
+// says (negative, zero or positive, like strings.Compare) satisfies op. The
+// ":" and "=" operators both ask for equality.
+func (op Operator) Compare(cmp int) bool {
+	switch op {
+	case OpLess:
+		return cmp < 0
+	case OpLessEqual:
+		return cmp <= 0
+	case OpGreater:
+		return cmp > 0
+	case OpGreaterEqual:
+		return cmp >= 0
+	case OpNotEqual:
+		return cmp != 0
+	}
+	return cmp == 0
+}
+
+// errOrdering is returned by the keywords that can only test for equality.
+var errOrdering = errors.New("only \":\" and \"=\" are supported")
+
+// Predicate reports whether a card of b satisfies a term.
+type Predicate func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool
+
+// Keyword turns the operator and value of a term into its Predicate, or
+// explains why the value makes no sense. It never receives OpNotEqual, which
+// is run as the negation of OpEqual.
+type Keyword func(b *mtgmatcher.Backend, op Operator, value string) (Predicate, error)
+
+// Predicates is what a game adds to the common keywords. Entries of the same
+// name take over the common ones.
+type Predicates struct {
+	// Keywords by name, aliases included, in lower case
+	Keywords map[string]Keyword
+
+	// The values of is: and not:, in lower case
+	Is map[string]Predicate
+}
+
+var registered = map[reflect.Type]Predicates{}
+
+// Register adds the predicates of the game whose rules are of the same type
+// as rules. Game packages call this from their init, next to
+// mtgmatcher.RegisterGame. It panics on nil rules or when called twice for
+// the same game.
+func Register(rules mtgmatcher.GameRules, predicates Predicates) {
+	if rules == nil {
+		panic("query: Register rules are nil")
+	}
+	key := reflect.TypeOf(rules)
+	_, found := registered[key]
+	if found {
+		panic("query: Register called twice for " + key.String())
+	}
+	registered[key] = predicates
+}
+
+// grammar is the keywords in play for one Backend.
+type grammar struct {
+	b        *mtgmatcher.Backend
+	keywords map[string]Keyword
+	is       map[string]Predicate
+}
+
+func newGrammar(b *mtgmatcher.Backend) *grammar {
+	g := &grammar{
+		b:        b,
+		keywords: maps.Clone(commonKeywords),
+		is:       maps.Clone(commonIs),
+	}
+	rules := b.Rules()
+	if rules == nil {
+		return g
+	}
+	predicates, found := registered[reflect.TypeOf(rules)]
+	if found {
+		maps.Copy(g.keywords, predicates.Keywords)
+		maps.Copy(g.is, predicates.Is)
+	}
+	return g
+}
+
+var commonKeywords = map[string]Keyword{
+	"name": StringField(func(co *mtgmatcher.CardObject) string { return co.Name }),
+
+	"set":     setKeyword,
+	"s":       setKeyword,
+	"e":       setKeyword,
+	"edition": setKeyword,
+
+	"r":      rarityKeyword,
+	"rarity": rarityKeyword,
+
+	"t":    typeKeyword,
+	"type": typeKeyword,
+
+	"c":     colorKeyword,
+	"color": colorKeyword,
+
+	"pt":    promoKeyword,
+	"promo": promoKeyword,
+
+	"fe":    frameKeyword,
+	"frame": frameKeyword,
+
+	"finish": ListField(func(co *mtgmatcher.CardObject) []string { return co.Finishes }),
+
+	"f":          legalityKeyword("Legal", "Restricted"),
+	"format":     legalityKeyword("Legal", "Restricted"),
+	"legal":      legalityKeyword("Legal", "Restricted"),
+	"banned":     legalityKeyword("Banned"),
+	"restricted": legalityKeyword("Restricted"),
+
+	"date": dateKeyword,
+	"year": yearKeyword,
+
+	"cn":     numberKeyword,
+	"number": numberKeyword,
+
+	"lang":     languageKeyword,
+	"language": languageKeyword,
+
+	"a":      artistKeyword,
+	"artist": artistKeyword,
+}
+
+var commonIs = map[string]Predicate{
+	"foil": func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		return co.Foil
+	},
+	"etched": func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		return co.Etched
+	},
+	"nonfoil": func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		return !co.Foil && !co.Etched
+	},
+	"promo": func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		return co.IsPromo
+	},
+	"reserved": func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		return co.IsReserved
+	},
+}
+
+// StringField is a Keyword over one string of the card: ":" looks for the
+// value in it and "=" wants all of it, both Normalize-d.
+func StringField(field func(co *mtgmatcher.CardObject) string) Keyword {
+	return func(b *mtgmatcher.Backend, op Operator, value string) (Predicate, error) {
+		switch op {
+		case OpMatch:
+			return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+				return mtgmatcher.Contains(field(co), value)
+			}, nil
+		case OpEqual:
+			return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+				return mtgmatcher.Equals(field(co), value)
+			}, nil
+		}
+		return nil, errOrdering
+	}
+}
+
+// ListField is a Keyword over a list of strings of the card, satisfied when
+// any of them is, the way StringField is.
+func ListField(field func(co *mtgmatcher.CardObject) []string) Keyword {
+	return func(b *mtgmatcher.Backend, op Operator, value string) (Predicate, error) {
+		check := mtgmatcher.Contains
+		switch op {
+		case OpMatch:
+		case OpEqual:
+			check = mtgmatcher.Equals
+		default:
+			return nil, errOrdering
+		}
+		return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			for _, str := range field(co) {
+				if check(str, value) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+}
+
+var (
+	artistKeyword   = StringField(func(co *mtgmatcher.CardObject) string { return co.Artist })
+	languageKeyword = StringField(func(co *mtgmatcher.CardObject) string { return co.Language })
+	promoKeyword    = ListField(func(co *mtgmatcher.CardObject) []string { return co.PromoTypes })
+
+	typeKeyword = ListField(func(co *mtgmatcher.CardObject) []string {
+		types := make([]string, 0, len(co.Supertypes)+len(co.Types)+len(co.Subtypes))
+		types = append(types, co.Supertypes...)
+		types = append(types, co.Types...)
+		return append(types, co.Subtypes...)
+	})
+
+	frameKeyword = ListField(func(co *mtgmatcher.CardObject) []string {
+		if co.FrameVersion == "" {
+			return co.FrameEffects
+		}
+		return append([]string{co.FrameVersion}, co.FrameEffects...)
+	})
+)
+
+// setKeyword takes a set code or name, in any of the spellings
+// GetSetByName knows.
+func setKeyword(b *mtgmatcher.Backend, op Operator, value string) (Predicate, error) {
+	if op != OpMatch && op != OpEqual {
+		return nil, errOrdering
+	}
+	set, err := b.GetSetByName(value)
+	if err != nil {
+		return nil, fmt.Errorf("unknown set %q", value)
+	}
+	code := set.Code
+	return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		return co.SetCode == code
+	}, nil
+}
+
+// rarityKeyword only tests for equality, as the order of the rarities
+// belongs to each game.
+func rarityKeyword(b *mtgmatcher.Backend, op Operator, value string) (Predicate, error) {
+	if op != OpMatch && op != OpEqual {
+		return nil, errOrdering
+	}
+	return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		return mtgmatcher.Equals(co.Rarity, value)
+	}, nil
+}
+
+// colorKeyword takes a color as the game spells it, or "multicolor" and
+// "colorless".
+func colorKeyword(b *mtgmatcher.Backend, op Operator, value string) (Predicate, error) {
+	if op != OpMatch && op != OpEqual {
+		return nil, errOrdering
+	}
+	switch strings.ToLower(value) {
+	case "multicolor", "multi":
+		return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			return len(co.Colors) > 1
+		}, nil
+	case "colorless":
+		return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			return len(co.Colors) == 0
+		}, nil
+	}
+	colors := ListField(func(co *mtgmatcher.CardObject) []string { return co.Colors })
+	return colors(b, OpEqual, value)
+}
+
+// legalityKeyword takes a format, satisfied when the card has one of the
+// statuses given in it.
+func legalityKeyword(statuses ...string) Keyword {
+	return func(b *mtgmatcher.Backend, op Operator, value string) (Predicate, error) {
+		if op != OpMatch && op != OpEqual {
+			return nil, errOrdering
+		}
+		format := strings.ToLower(value)
+		return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			status := co.Legalities[format]
+			for _, wanted := range statuses {
+				if strings.EqualFold(status, wanted) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+}
+
+// releaseDate is when the card was first available: its own release date
+// when it has one, the set's otherwise.
+func releaseDate(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) time.Time {
+	if co.OriginalReleaseDate != "" {
+		date, err := time.Parse("2006-01-02", co.OriginalReleaseDate)
+		if err == nil {
+			return date
+		}
+	}
+	set, found := b.Sets[co.SetCode]
+	if !found {
+		return time.Time{}
+	}
+	return set.ReleaseDateTime
+}
+
+// dateKeyword takes a date as YYYY-MM-DD, or a set code standing for the
+// date the set was released.
+func dateKeyword(b *mtgmatcher.Backend, op Operator, value string) (Predicate, error) {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		set, err := b.GetSet(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is neither a date nor a set code", value)
+		}
+		date = set.ReleaseDateTime
+	}
+	return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		released := releaseDate(b, co)
+		return !released.IsZero() && op.Compare(released.Compare(date))
+	}, nil
+}
+
+func yearKeyword(b *mtgmatcher.Backend, op Operator, value string) (Predicate, error) {
+	year, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a year", value)
+	}
+	return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		released := releaseDate(b, co)
+		return !released.IsZero() && op.Compare(released.Year()-year)
+	}, nil
+}
+
+// numberKeyword compares collector numbers as written for equality, and by
+// their leading digits otherwise.
+func numberKeyword(b *mtgmatcher.Backend, op Operator, value string) (Predicate, error) {
+	if op == OpMatch || op == OpEqual {
+		return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			return strings.EqualFold(co.Number, value)
+		}, nil
+	}
+	number, ok := leadingNumber(value)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a number", value)
+	}
+	return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		cn, ok := leadingNumber(co.Number)
+		return ok && op.Compare(cn-number)
+	}, nil
+}
+
+// leadingNumber returns the digits a collector number starts with, so that
+// 123a and 123 sort together.
+func leadingNumber(str string) (int, bool) {
+	end := 0
+	for end < len(str) && str[end] >= '0' && str[end] <= '9' {
+		end++
+	}
+	number, err := strconv.Atoi(str[:end])
+	return number, err == nil
+}
@@ -0,0 +1,477 @@
+// Package query searches a mtgmatcher datastore with Scryfall-style
+// expressions, such as
+//
+//	set:mh2 r:mythic is:foil t:creature c:g
+//
+// Terms are joined by AND unless separated by "or", a leading "-" negates a
+// term or a parenthesized group, and a value with spaces goes in double
+// quotes. A word without a keyword looks into the card name, and "!" before
+// it asks for the exact name.
+//
+// The keywords every game understands read the fields of the Card: set,
+// rarity, type, color, promo types, frame effects, finishes, legalities,
+// release date and so on (see §2.8 of SPECIFICATIONS.md).
+// A game package adds its own, or replaces a common one with a reading fit
+// for its cards, by calling Register from its init with its GameRules; the
+// keywords in play are those of the rules attached to the Backend searched.
+//
+// Results are uuids, one per finish like the rest of the datastore, ready
+// for mtgban.ArbitOpts.OnlyUUIDs.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+)
+
+// Query is a parsed expression, to be run against any Backend.
+type Query struct {
+	root node
+}
+
+// node is one part of the expression tree.
+type node interface {
+	compile(g *grammar) (Predicate, error)
+	String() string
+}
+
+type andNode []node
+
+type orNode []node
+
+type notNode struct {
+	node
+}
+
+// termNode is a single keyword term, or a bare name when key is empty.
+type termNode struct {
+	key   string
+	op    Operator
+	value string
+	exact bool
+}
+
+func (n andNode) String() string {
+	return joinNodes(n, " ")
+}
+
+func (n orNode) String() string {
+	return "(" + joinNodes(n, " or ") + ")"
+}
+
+func (n notNode) String() string {
+	return "-" + n.node.String()
+}
+
+func (n termNode) String() string {
+	value := n.value
+	if value == "" || strings.ContainsAny(value, " ()") {
+		value = `"` + value + `"`
+	}
+	switch {
+	case n.exact:
+		return "!" + value
+	case n.key == "":
+		return value
+	}
+	return n.key + string(n.op) + value
+}
+
+func joinNodes(nodes []node, sep string) string {
+	parts := make([]string, len(nodes))
+	for i, n := range nodes {
+		parts[i] = n.String()
+	}
+	return strings.Join(parts, sep)
+}
+
+// String returns the expression as parsed, with every or group in
+// parentheses.
+func (q *Query) String() string {
+	return q.root.String()
+}
+
+// Parse parses an expression. Only the syntax is checked here, since the
+// keywords depend on the game: an unknown keyword or a bad value is reported
+// by Search.
+func Parse(expr string) (*Query, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("query: empty query")
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("query: unexpected %s at %d", p.tokens[p.pos], p.tokens[p.pos].at)
+	}
+	return &Query{root: root}, nil
+}
+
+// Search returns the uuids of the cards of b matching the query, in the order
+// of Backend.AllUUIDs. Sealed products are never returned. The slice is empty
+// rather than nil when nothing matches, so that it still filters everything
+// out when used as ArbitOpts.OnlyUUIDs.
+func (q *Query) Search(b *mtgmatcher.Backend) ([]string, error) {
+	match, err := q.root.compile(newGrammar(b))
+	if err != nil {
+		return nil, err
+	}
+	uuids := []string{}
+	for _, uuid := range b.AllUUIDs {
+		co, found := b.UUIDs[uuid]
+		if !found || co.Sealed {
+			continue
+		}
+		if match(b, co) {
+			uuids = append(uuids, uuid)
+		}
+	}
+	return uuids, nil
+}
+
+// Search parses expr and runs it against the default datastore.
+func Search(expr string) ([]string, error) {
+	q, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	return q.Search(mtgmatcher.GlobalDatastore())
+}
+
+type tokenKind int
+
+const (
+	tokenTerm tokenKind = iota
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind tokenKind
+	term termNode
+	at   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenOr:
+		return `"or"`
+	case tokenNot:
+		return `"-"`
+	case tokenOpen:
+		return `"("`
+	case tokenClose:
+		return `")"`
+	}
+	return fmt.Sprintf("%q", t.term.String())
+}
+
+// operators are tried in order, so the two letter ones go first.
+var operators = []Operator{
+	OpNotEqual, OpLessEqual, OpGreaterEqual, OpMatch, OpEqual, OpLess, OpGreater,
+}
+
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, at: i})
+			i++
+			continue
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, at: i})
+			i++
+			continue
+		case r == '-':
+			if i+1 == len(runes) || unicode.IsSpace(runes[i+1]) {
+				return nil, fmt.Errorf("query: nothing to negate at %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenNot, at: i})
+			i++
+			continue
+		}
+
+		start := i
+		tok := token{kind: tokenTerm, at: start}
+
+		// An exact name
+		if r == '!' {
+			value, next, err := lexValue(runes, i+1)
+			if err != nil {
+				return nil, err
+			}
+			if value == "" {
+				return nil, fmt.Errorf("query: missing name after \"!\" at %d", start)
+			}
+			tok.term = termNode{value: value, exact: true}
+			tokens = append(tokens, tok)
+			i = next
+			continue
+		}
+
+		// A keyword, when letters are followed by an operator
+		for i < len(runes) && unicode.IsLetter(runes[i]) {
+			i++
+		}
+		key := strings.ToLower(string(runes[start:i]))
+		isKeyword := false
+		for _, op := range operators {
+			if key == "" || !strings.HasPrefix(string(runes[i:]), string(op)) {
+				continue
+			}
+			// Operators are all ASCII, so their length is in runes too
+			value, next, err := lexValue(runes, i+len(op))
+			if err != nil {
+				return nil, err
+			}
+			if value == "" {
+				return nil, fmt.Errorf("query: missing value for %s%s at %d", key, op, start)
+			}
+			tok.term = termNode{key: key, op: op, value: value}
+			tokens = append(tokens, tok)
+			i = next
+			isKeyword = true
+			break
+		}
+		if isKeyword {
+			continue
+		}
+
+		// Otherwise a word of the name, or a connective
+		value, next, err := lexValue(runes, start)
+		if err != nil {
+			return nil, err
+		}
+		i = next
+		quoted := runes[start] == '"'
+		switch {
+		case !quoted && strings.EqualFold(value, "or"):
+			tok.kind = tokenOr
+		case !quoted && strings.EqualFold(value, "and"):
+			continue
+		case value == "":
+			return nil, fmt.Errorf("query: empty name at %d", start)
+		default:
+			tok.term = termNode{value: value}
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens, nil
+}
+
+// lexValue reads a value starting at i, quoted or running to the next space
+// or parenthesis, and returns it with where it ends.
+func lexValue(runes []rune, i int) (string, int, error) {
+	if i < len(runes) && runes[i] == '"' {
+		for j := i + 1; j < len(runes); j++ {
+			if runes[j] == '"' {
+				return string(runes[i+1 : j]), j + 1, nil
+			}
+		}
+		return "", 0, fmt.Errorf("query: unterminated quote at %d", i)
+	}
+	j := i
+	for j < len(runes) && !unicode.IsSpace(runes[j]) && runes[j] != ')' && runes[j] != '(' {
+		j++
+	}
+	return string(runes[i:j]), j, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() *token {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+func (p *parser) parseOr() (node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []node{first}
+	for tok := p.peek(); tok != nil && tok.kind == tokenOr; tok = p.peek() {
+		p.pos++
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, next)
+	}
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return orNode(nodes), nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	var nodes []node
+	for tok := p.peek(); tok != nil && tok.kind != tokenOr && tok.kind != tokenClose; tok = p.peek() {
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	switch len(nodes) {
+	case 0:
+		if tok := p.peek(); tok != nil {
+			return nil, fmt.Errorf("query: expected a term before %s at %d", tok, tok.at)
+		}
+		return nil, errors.New("query: expected a term at the end")
+	case 1:
+		return nodes[0], nil
+	}
+	return andNode(nodes), nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	tok := p.peek()
+	p.pos++
+	switch tok.kind {
+	case tokenNot:
+		if next := p.peek(); next == nil || next.kind == tokenOr || next.kind == tokenClose {
+			return nil, fmt.Errorf("query: nothing to negate at %d", tok.at)
+		}
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	case tokenOpen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if next := p.peek(); next == nil || next.kind != tokenClose {
+			return nil, fmt.Errorf("query: unclosed parenthesis at %d", tok.at)
+		}
+		p.pos++
+		return n, nil
+	}
+	return tok.term, nil
+}
+
+func (n andNode) compile(g *grammar) (Predicate, error) {
+	preds, err := compileNodes(n, g)
+	if err != nil {
+		return nil, err
+	}
+	return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		for _, pred := range preds {
+			if !pred(b, co) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func (n orNode) compile(g *grammar) (Predicate, error) {
+	preds, err := compileNodes(n, g)
+	if err != nil {
+		return nil, err
+	}
+	return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		for _, pred := range preds {
+			if pred(b, co) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func (n notNode) compile(g *grammar) (Predicate, error) {
+	pred, err := n.node.compile(g)
+	if err != nil {
+		return nil, err
+	}
+	return not(pred), nil
+}
+
+func (n termNode) compile(g *grammar) (Predicate, error) {
+	switch n.key {
+	case "":
+		value := n.value
+		if n.exact {
+			return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+				return mtgmatcher.Equals(co.Name, value)
+			}, nil
+		}
+		return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+			return mtgmatcher.Contains(co.Name, value)
+		}, nil
+	case "is", "not":
+		if n.op != OpMatch && n.op != OpEqual {
+			return nil, fmt.Errorf("query: %s only takes \":\"", n.key)
+		}
+		pred, found := g.is[strings.ToLower(n.value)]
+		if !found {
+			return nil, fmt.Errorf("query: unknown %s:%s", n.key, n.value)
+		}
+		if n.key == "not" {
+			return not(pred), nil
+		}
+		return pred, nil
+	}
+
+	keyword, found := g.keywords[n.key]
+	if !found {
+		return nil, fmt.Errorf("query: unknown keyword %q", n.key)
+	}
+	op := n.op
+	if op == OpNotEqual {
+		op = OpEqual
+	}
+	pred, err := keyword(g.b, op, n.value)
+	if err != nil {
+		return nil, fmt.Errorf("query: %s: %w", n, err)
+	}
+	if n.op == OpNotEqual {
+		return not(pred), nil
+	}
+	return pred, nil
+}
+
+func compileNodes(nodes []node, g *grammar) ([]Predicate, error) {
+	preds := make([]Predicate, len(nodes))
+	for i, n := range nodes {
+		pred, err := n.compile(g)
+		if err != nil {
+			return nil, err
+		}
+		preds[i] = pred
+	}
+	return preds, nil
+}
+
+func not(pred Predicate) Predicate {
+	return func(b *mtgmatcher.Backend, co *mtgmatcher.CardObject) bool {
+		return !pred(b, co)
+	}
+}
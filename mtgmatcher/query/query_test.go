@@ -0,0 +1,160 @@
+package query_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+	"github.com/mtgban/go-mtgban/mtgmatcher/magic"
+	"github.com/mtgban/go-mtgban/mtgmatcher/query"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{"set:mh2 r:mythic is:foil", "set:mh2 r:mythic is:foil"},
+		{"t:creature (c:g or c:u)", "t:creature (c:g or c:u)"},
+		{"-is:promo and llanowar", "-is:promo llanowar"},
+		{`!"Llanowar Elves" a:"Chris Rahn"`, `!"Llanowar Elves" a:"Chris Rahn"`},
+		{"cn>=100 date<2021-06-18 r!=rare", "cn>=100 date<2021-06-18 r!=rare"},
+		{"-(set:fdn OR set:mh2)", "-(set:fdn or set:mh2)"},
+	}
+	for _, test := range tests {
+		q, err := query.Parse(test.expr)
+		if err != nil {
+			t.Errorf("%s: %v", test.expr, err)
+			continue
+		}
+		if q.String() != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.expr, test.expected, q.String())
+		}
+	}
+
+	for _, expr := range []string{"", "set:", `a:"unterminated`, "(t:elf", "t:elf)", "t:elf or", "-", "!"} {
+		_, err := query.Parse(expr)
+		if err == nil {
+			t.Errorf("%q: expected an error", expr)
+		}
+	}
+}
+
+func queryBackend() *mtgmatcher.Backend {
+	cards := []mtgmatcher.CardObject{
+		{Card: mtgmatcher.Card{
+			UUID: "elves", Name: "Llanowar Elves", SetCode: "FDN", Number: "227",
+			Rarity: "common", Colors: []string{"G"}, ColorIdentity: []string{"G"},
+			Types: []string{"Creature"}, Subtypes: []string{"Elf", "Druid"},
+			Finishes: []string{"nonfoil", "foil"}, Legalities: map[string]string{"modern": "Legal"},
+		}, Edition: "Foundations"},
+		{Card: mtgmatcher.Card{
+			UUID: "elves_f", Name: "Llanowar Elves", SetCode: "FDN", Number: "227",
+			Rarity: "common", Colors: []string{"G"}, ColorIdentity: []string{"G"},
+			Types: []string{"Creature"}, Subtypes: []string{"Elf", "Druid"},
+			Finishes: []string{"nonfoil", "foil"}, Legalities: map[string]string{"modern": "Legal"},
+		}, Edition: "Foundations", Foil: true},
+		{Card: mtgmatcher.Card{
+			UUID: "thrun", Name: "Thrun, Breaker of Silence", SetCode: "MH2", Number: "186",
+			Rarity: "rare", Colors: []string{"G"}, ColorIdentity: []string{"G"},
+			Supertypes: []string{"Legendary"}, Types: []string{"Creature"}, Subtypes: []string{"Troll", "Shaman"},
+			Finishes: []string{"nonfoil"}, Legalities: map[string]string{"modern": "Legal"},
+		}, Edition: "Modern Horizons 2"},
+		{Card: mtgmatcher.Card{
+			UUID: "grist", Name: "Grist, the Hunger Tide", SetCode: "MH2", Number: "404",
+			Rarity: "mythic", Colors: []string{"B", "G"}, ColorIdentity: []string{"B", "G"},
+			Supertypes: []string{"Legendary"}, Types: []string{"Planeswalker"}, Subtypes: []string{"Grist"},
+			Finishes: []string{"etched"}, FrameEffects: []string{"etched"}, IsPromo: true,
+			Legalities: map[string]string{"modern": "Banned"},
+		}, Edition: "Modern Horizons 2", Etched: true},
+		{Card: mtgmatcher.Card{
+			UUID: "lotus", Name: "Black Lotus", SetCode: "LEA", Number: "232",
+			Rarity: "rare", Types: []string{"Artifact"}, IsReserved: true,
+			Finishes: []string{"nonfoil"}, Legalities: map[string]string{"vintage": "Restricted"},
+		}, Edition: "Limited Edition Alpha"},
+		{Card: mtgmatcher.Card{
+			UUID: "box", Name: "Modern Horizons 2 Draft Booster Box", SetCode: "MH2",
+		}, Edition: "Modern Horizons 2", Sealed: true},
+	}
+
+	b := &mtgmatcher.Backend{
+		Sets: map[string]*mtgmatcher.Set{
+			"FDN": {Code: "FDN", Name: "Foundations", ReleaseDateTime: time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC)},
+			"MH2": {Code: "MH2", Name: "Modern Horizons 2", ReleaseDateTime: time.Date(2021, 6, 18, 0, 0, 0, 0, time.UTC)},
+			"LEA": {Code: "LEA", Name: "Limited Edition Alpha", ReleaseDateTime: time.Date(1993, 8, 5, 0, 0, 0, 0, time.UTC)},
+		},
+		UUIDs: map[string]*mtgmatcher.CardObject{},
+	}
+	for i := range cards {
+		b.UUIDs[cards[i].UUID] = &cards[i]
+		b.AllUUIDs = append(b.AllUUIDs, cards[i].UUID)
+	}
+	slices.Sort(b.AllUUIDs)
+	b.IndexSets()
+	b.SetRules(magic.Rules{})
+	return b
+}
+
+func TestSearch(t *testing.T) {
+	b := queryBackend()
+
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{"set:mh2", []string{"grist", "thrun"}},
+		{`e:"Modern Horizons 2" r>=rare`, []string{"grist", "thrun"}},
+		{"r:m", []string{"grist"}},
+		{"r<rare", []string{"elves", "elves_f"}},
+		{"is:foil", []string{"elves_f"}},
+		{"is:nonfoil t:creature", []string{"elves", "thrun"}},
+		{"t:elf or t:planeswalker", []string{"elves", "elves_f", "grist"}},
+		{"c:g", []string{"elves", "elves_f", "grist", "thrun"}},
+		{"c=g", []string{"elves", "elves_f", "thrun"}},
+		{"c:golgari", []string{"grist"}},
+		{"c:m", []string{"grist"}},
+		{"c:c", []string{"lotus"}},
+		{"id:g", []string{"elves", "elves_f", "lotus", "thrun"}},
+		{"is:reserved", []string{"lotus"}},
+		{"-is:promo set:mh2", []string{"thrun"}},
+		{"f:modern", []string{"elves", "elves_f", "thrun"}},
+		{"banned:modern", []string{"grist"}},
+		{"f:vintage", []string{"lotus"}},
+		{"date>=mh2", []string{"elves", "elves_f", "grist", "thrun"}},
+		{"year<2000", []string{"lotus"}},
+		{"cn>200 cn<300", []string{"elves", "elves_f", "lotus"}},
+		{"fe:etched", []string{"grist"}},
+		{"llanowar", []string{"elves", "elves_f"}},
+		{`!"Black Lotus"`, []string{"lotus"}},
+		{"!lotus", []string{}},
+		{"s:fdn r!=common", []string{}},
+	}
+	for _, test := range tests {
+		q, err := query.Parse(test.expr)
+		if err != nil {
+			t.Errorf("%s: %v", test.expr, err)
+			continue
+		}
+		uuids, err := q.Search(b)
+		if err != nil {
+			t.Errorf("%s: %v", test.expr, err)
+			continue
+		}
+		if !slices.Equal(uuids, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.expr, test.expected, uuids)
+		}
+	}
+
+	for _, expr := range []string{"set:nope", "r:epic", "c:x", "foo:bar", "is:shiny", "set>mh2", "date:yesterday"} {
+		q, err := query.Parse(expr)
+		if err != nil {
+			t.Errorf("%s: %v", expr, err)
+			continue
+		}
+		_, err = q.Search(b)
+		if err == nil {
+			t.Errorf("%s: expected an error", expr)
+		}
+	}
+}
@@ -6,4 +6,5 @@ import "github.com/mtgban/go-mtgban/mtgmatcher"
 // package makes the game available to mtgmatcher.LoadDatastore and Open.
 func init() {
 	mtgmatcher.RegisterGame("riftbound", Load)
+	mtgmatcher.RegisterRules("riftbound", Rules{})
 }
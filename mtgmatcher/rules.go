@@ -62,6 +62,12 @@ type GameRules interface {
 	CanonicalFinish(name string) string
 }
 
+// Rules returns the game-specific hooks attached by SetRules, which tell what
+// game the datastore is for.
+func (b *Backend) Rules() GameRules {
+	return b.rules
+}
+
 // SetRules attaches the game-specific identification hooks used by Match. A
 // game's datastore loader calls this when it builds a Backend.
 func (b *Backend) SetRules(r GameRules) {
@@ -82,4 +88,7 @@ func (b *Backend) SetRules(r GameRules) {
 			b.knownFinishes[key] = true
 		}
 	}
+
+	// Every loader calls this last, once all the names are filed
+	b.fuzzy = newFuzzyIndex(b)
 }
@@ -361,9 +361,7 @@ func (b *Backend) AddSealed(uuid, name, setCode, image string, tcgplayerProductI
 	// can normalize to one string while staying two spellings, and asking
 	// the wrong list drops one of them.
 	if b.seenSealed == nil {
-		b.seenSealed = map[string]bool{}
-		b.seenLowerSealed = map[string]bool{}
-		b.seenCanonicalSealed = map[string]bool{}
+		b.indexSealedNames()
 	}
 	n := Normalize(name)
 	if !b.seenSealed[n] {
@@ -389,6 +387,23 @@ func (b *Backend) AddSealed(uuid, name, setCode, image string, tcgplayerProductI
 	b.SetSealedUUIDs[setCode] = append(b.SetSealedUUIDs[setCode], uuid)
 }
 
+// indexSealedNames rebuilds the sets AddSealed dedupes on out of the sealed
+// name lists they mirror, as indexNames does for AddName.
+func (b *Backend) indexSealedNames() {
+	b.seenSealed = make(map[string]bool, len(b.AllSealed))
+	for _, name := range b.AllSealed {
+		b.seenSealed[name] = true
+	}
+	b.seenLowerSealed = make(map[string]bool, len(b.AllLowerSealed))
+	for _, name := range b.AllLowerSealed {
+		b.seenLowerSealed[name] = true
+	}
+	b.seenCanonicalSealed = make(map[string]bool, len(b.AllCanonicalSealed))
+	for _, name := range b.AllCanonicalSealed {
+		b.seenCanonicalSealed[name] = true
+	}
+}
+
 // SortSealed puts the sealed indexes in order, once every product is filed.
 // The lists are built in the datastore's order and read as sorted ones.
 func (b *Backend) SortSealed() {
@@ -0,0 +1,135 @@
+package mtgmatcher
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// A snapshot is a Backend as it stands once loaded, so that starting up is
+// reading it back rather than parsing and indexing the source data again.
+//
+// A file starts with snapshotMagic and a version byte, followed by a gob
+// stream holding a snapshotHeader and the Backend, with every exported field
+// and so every index. The set names index is rebuilt by IndexSets rather than
+// stored, since it points to the very sets of Sets, and so are the ones the
+// rules keep, by SetRules, which reattaches the rules of the game the header
+// names. The unexported sets AddName and AddSealed dedupe on are rebuilt out
+// of the name lists they mirror.
+const snapshotMagic = "BANSNAP"
+
+// snapshotVersion is bumped whenever a field of Backend, or of a type it
+// holds, changes meaning: gob skips the fields it does not know and leaves
+// the missing ones empty, so only the version tells an old snapshot apart.
+const snapshotVersion byte = 1
+
+type snapshotHeader struct {
+	// Name of the game, as registered with RegisterGame
+	Game string
+}
+
+// isSnapshot reports whether data starts like a snapshot.
+func isSnapshot(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(snapshotMagic))
+}
+
+// WriteSnapshot writes the default datastore as a snapshot. See the method.
+func WriteSnapshot(w io.Writer) error {
+	return defaultBackend.WriteSnapshot(w)
+}
+
+// WriteSnapshot writes b as a versioned binary snapshot, which LoadDatastore,
+// Open and LoadSnapshot read back in a fraction of the time the game's own
+// loader takes. The rules attached to b need to be registered with
+// RegisterRules, since that is how the snapshot names its game.
+func (b *Backend) WriteSnapshot(w io.Writer) error {
+	game := ""
+	for _, g := range registeredGames {
+		if g.rules != nil && reflect.TypeOf(g.rules) == reflect.TypeOf(b.rules) {
+			game = g.name
+			break
+		}
+	}
+	if game == "" {
+		return errors.New("mtgmatcher: the rules of this datastore are not registered with RegisterRules")
+	}
+
+	// Shallow copy, dropping what is rebuilt on load
+	snapshot := *b
+	snapshot.NormalizedSets = nil
+
+	buffered := bufio.NewWriter(w)
+	_, err := buffered.WriteString(snapshotMagic)
+	if err != nil {
+		return err
+	}
+	err = buffered.WriteByte(snapshotVersion)
+	if err != nil {
+		return err
+	}
+	enc := gob.NewEncoder(buffered)
+	err = enc.Encode(snapshotHeader{Game: game})
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&snapshot)
+	if err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+// LoadSnapshot reads a snapshot written by WriteSnapshot and returns the
+// Backend without installing it as the global one, with the rules of its
+// game attached. The game package needs to be imported, as for Open.
+func LoadSnapshot(reader io.Reader) (*Backend, error) {
+	b, _, err := readSnapshot(reader)
+	return b, err
+}
+
+// readSnapshot is LoadSnapshot, also returning the name of the game.
+func readSnapshot(reader io.Reader) (*Backend, string, error) {
+	buffered := bufio.NewReader(reader)
+	header := make([]byte, len(snapshotMagic)+1)
+	_, err := io.ReadFull(buffered, header)
+	if err != nil || !isSnapshot(header) {
+		return nil, "", errors.New("mtgmatcher: not a datastore snapshot")
+	}
+	version := header[len(snapshotMagic)]
+	if version != snapshotVersion {
+		return nil, "", fmt.Errorf("mtgmatcher: snapshot version %d, expected %d; write it again from the source datastore", version, snapshotVersion)
+	}
+
+	dec := gob.NewDecoder(buffered)
+	var info snapshotHeader
+	err = dec.Decode(&info)
+	if err != nil {
+		return nil, "", fmt.Errorf("mtgmatcher: snapshot header: %w", err)
+	}
+	var rules GameRules
+	for _, g := range registeredGames {
+		if g.name == info.Game {
+			rules = g.rules
+			break
+		}
+	}
+	if rules == nil {
+		return nil, "", fmt.Errorf("mtgmatcher: snapshot of game %q, whose rules are not registered (registered: %v)", info.Game, RegisteredGames())
+	}
+
+	var b Backend
+	err = dec.Decode(&b)
+	if err != nil {
+		return nil, "", fmt.Errorf("mtgmatcher: snapshot: %w", err)
+	}
+	b.IndexSets()
+	b.indexNames()
+	b.indexSealedNames()
+	b.SetRules(rules)
+
+	return &b, info.Game, nil
+}
@@ -0,0 +1,108 @@
+package mtgmatcher_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/mtgban/go-mtgban/mtgmatcher"
+	"github.com/mtgban/go-mtgban/mtgmatcher/magic"
+)
+
+func TestSnapshot(t *testing.T) {
+	var buf bytes.Buffer
+	err := testBackend.WriteSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	b, err := mtgmatcher.LoadSnapshot(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.Rules().(magic.Rules); !ok {
+		t.Fatalf("expected the magic rules, got %T", b.Rules())
+	}
+	for name, index := range map[string][2]any{
+		"AllUUIDs":            {testBackend.AllUUIDs, b.AllUUIDs},
+		"Hashes":              {testBackend.Hashes, b.Hashes},
+		"SetUUIDs":            {testBackend.SetUUIDs, b.SetUUIDs},
+		"ExternalIdentifiers": {testBackend.ExternalIdentifiers, b.ExternalIdentifiers},
+		"AlternateProps":      {testBackend.AlternateProps, b.AlternateProps},
+		"CanonicalNames":      {testBackend.CanonicalNames, b.CanonicalNames},
+		"AllCanonicalNames":   {testBackend.AllCanonicalNames, b.AllCanonicalNames},
+	} {
+		if !reflect.DeepEqual(index[0], index[1]) {
+			t.Errorf("%s differs after the snapshot", name)
+		}
+	}
+	if len(b.NormalizedSets) != len(testBackend.NormalizedSets) {
+		t.Errorf("expected %d set names, got %d", len(testBackend.NormalizedSets), len(b.NormalizedSets))
+	}
+
+	// The reloaded rules match as the original ones do
+	for _, input := range []mtgmatcher.InputCard{
+		{Name: "llanowar elves", Edition: "Foundations", Variation: "227"},
+		{Name: "Counterspell", Edition: "FDN", Foil: true},
+		{Name: "Llanowar Elvs"},
+	} {
+		card := input
+		expected, expectedErr := testBackend.Match(&card)
+		card = input
+		cardID, err := b.Match(&card)
+		if cardID != expected || (err == nil) != (expectedErr == nil) {
+			t.Errorf("%v: expected %s %v, got %s %v", input, expected, expectedErr, cardID, err)
+		}
+	}
+	if !reflect.DeepEqual(b.SearchFuzzy("Conterspell", 1), []string{"Counterspell"}) {
+		t.Errorf("the fuzzy index was not rebuilt")
+	}
+
+	// The names already there are not filed again
+	names, sealed := len(b.AllCanonicalNames), len(b.AllCanonicalSealed)
+	if sealed == 0 {
+		t.Fatal("expected a sealed product in the datastore")
+	}
+	b.AddName(b.AllCanonicalNames[0])
+	b.AddSealed("a0b0c0d0-0000-5000-8000-00000000b0b1", b.AllCanonicalSealed[0], b.AllSets[0], "", 0)
+	for name, lists := range map[string][2]int{
+		"AllNames":           {names, len(b.AllNames)},
+		"AllLowerNames":      {names, len(b.AllLowerNames)},
+		"AllCanonicalNames":  {names, len(b.AllCanonicalNames)},
+		"AllSealed":          {sealed, len(b.AllSealed)},
+		"AllLowerSealed":     {sealed, len(b.AllLowerSealed)},
+		"AllCanonicalSealed": {sealed, len(b.AllCanonicalSealed)},
+	} {
+		if lists[0] != lists[1] {
+			t.Errorf("%s: expected %d names after adding a known one, got %d", name, lists[0], lists[1])
+		}
+	}
+	b.AddName("Not A Card")
+	if len(b.AllCanonicalNames) != names+1 {
+		t.Errorf("expected a new name to be added")
+	}
+
+	// Snapshots are found by Open and LoadDatastore like any game
+	_, err = mtgmatcher.Open("magic", bytes.NewReader(data))
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = mtgmatcher.Open("lorcana", bytes.NewReader(data))
+	if err == nil {
+		t.Error("expected a magic snapshot to be rejected as lorcana")
+	}
+	err = mtgmatcher.LoadDatastore(bytes.NewReader(data))
+	if err != nil {
+		t.Error(err)
+	}
+	mtgmatcher.SetGlobalDatastore(testBackend)
+
+	// Another version is refused rather than misread
+	stale := bytes.Clone(data)
+	stale[len("BANSNAP")]++
+	_, err = mtgmatcher.LoadSnapshot(bytes.NewReader(stale))
+	if err == nil {
+		t.Error("expected a snapshot of another version to be refused")
+	}
+}
@@ -115,6 +115,8 @@ var NumberTests = []ExtractTest{
 }
 
 func TestExtractNumber(t *testing.T) {
+	NeedFullDatastore(t)
+
 	for _, probe := range NumberTests {
 		test := probe
 		t.Run(test.In, func(t *testing.T) {
@@ -324,6 +326,8 @@ func TestCut(t *testing.T) {
 }
 
 func TestAlias(t *testing.T) {
+	NeedFullDatastore(t)
+
 	inCard := &InputCard{
 		Name:      "Forest",
 		Variation: "Full-Art",
@@ -4,4 +4,5 @@ import "github.com/mtgban/go-mtgban/mtgmatcher"
 
 func init() {
 	mtgmatcher.RegisterGame("yugioh", Load)
+	mtgmatcher.RegisterRules("yugioh", Rules{})
 }
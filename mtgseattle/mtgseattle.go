@@ -14,7 +14,6 @@ import (
 	"github.com/mtgban/go-mtgban/mtgmatcher"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/hashicorp/go-retryablehttp"
 )
 
 const (
@@ -43,6 +42,8 @@ type MTGSeattle struct {
 	DisableBuylist bool
 
 	client *http.Client
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a scraper.
@@ -51,7 +52,7 @@ func NewScraper() *MTGSeattle {
 	ms.inventory = mtgban.InventoryRecord{}
 	ms.buylist = mtgban.BuylistRecord{}
 	ms.MaxConcurrency = defaultConcurrency
-	client := retryablehttp.NewClient()
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	ms.client = client.StandardClient()
 	return &ms
@@ -194,7 +195,7 @@ func (ms *MTGSeattle) processProduct(ctx context.Context, channel chan<- respons
 			return
 		}
 
-		cardID, err := mtgmatcher.Match(theCard)
+		cardID, err := ms.matchStats.Match(theCard)
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			return
 		} else if err != nil {
@@ -461,6 +462,11 @@ func grading(cardID string, price float64) map[string]float64 {
 	}
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (ms *MTGSeattle) MatchStats() *mtgban.MatchStats {
+	return &ms.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (ms *MTGSeattle) Info() (info mtgban.ScraperInfo) {
 	info.Name = "MTGSeattle"
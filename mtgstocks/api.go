@@ -13,6 +13,7 @@ import (
 
 	"github.com/corpix/uarand"
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/mtgban/go-mtgban/mtgban"
 )
 
 // StocksInterest is one card whose price moved, with the old and new numbers.
@@ -70,7 +71,7 @@ type STKSClient struct {
 // NewClient returns a client.
 func NewClient() *STKSClient {
 	stks := STKSClient{}
-	stks.client = retryablehttp.NewClient()
+	stks.client = mtgban.NewRetryableClient()
 	stks.client.Backoff = retryablehttp.LinearJitterBackoff
 	stks.client.RetryWaitMin = 2 * time.Second
 	stks.client.RetryWaitMax = 10 * time.Second
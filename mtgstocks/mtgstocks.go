@@ -24,6 +24,8 @@ type MTGStocks struct {
 
 	client    *STKSClient
 	inventory mtgban.InventoryRecord
+
+	matchStats mtgban.MatchStats
 }
 
 type requestChan struct {
@@ -61,7 +63,7 @@ func (stks *MTGStocks) processEntry(channel chan<- responseChan, req requestChan
 		return nil
 	}
 
-	cardID, err := mtgmatcher.Match(theCard)
+	cardID, err := stks.matchStats.Match(theCard)
 	if errors.Is(err, mtgmatcher.ErrUnsupported) {
 		return nil
 	} else if err != nil {
@@ -170,6 +172,11 @@ func (stks *MTGStocks) Inventory() mtgban.InventoryRecord {
 	return stks.inventory
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (stks *MTGStocks) MatchStats() *mtgban.MatchStats {
+	return &stks.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (stks *MTGStocks) Info() (info mtgban.ScraperInfo) {
 	info.Name = "MTGStocks"
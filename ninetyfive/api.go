@@ -9,7 +9,7 @@ import (
 	"net/url"
 	"strings"
 
-	"github.com/hashicorp/go-retryablehttp"
+	"github.com/mtgban/go-mtgban/mtgban"
 )
 
 const (
@@ -63,7 +63,7 @@ type NFClient struct {
 // NewNFClient returns a client.
 func NewNFClient() *NFClient {
 	nf := NFClient{}
-	client := retryablehttp.NewClient()
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	nf.client = client.StandardClient()
 	return &nf
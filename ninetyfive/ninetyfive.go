@@ -40,6 +40,8 @@ type Ninetyfive struct {
 	buylistDate    time.Time
 	buylist        mtgban.BuylistRecord
 	DisableBuylist bool
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a scraper for one game, failing if the catalog cannot be
@@ -107,7 +109,7 @@ func (nf *Ninetyfive) processPrices(allCards NFCard, allPrices NFPrice, mode str
 				continue
 			}
 
-			cardID, err := mtgmatcher.Match(theCard)
+			cardID, err := nf.matchStats.Match(theCard)
 			if errors.Is(err, mtgmatcher.ErrUnsupported) {
 				continue
 			} else if err != nil {
@@ -280,6 +282,11 @@ func (nf *Ninetyfive) Buylist() mtgban.BuylistRecord {
 	return nf.buylist
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (nf *Ninetyfive) MatchStats() *mtgban.MatchStats {
+	return &nf.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (nf *Ninetyfive) Info() (info mtgban.ScraperInfo) {
 	info.Name = "95mtg"
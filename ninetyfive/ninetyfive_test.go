@@ -0,0 +1,46 @@
+package ninetyfive
+
+import (
+	"testing"
+
+	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/go-mtgban/mtgban/scrapertest"
+)
+
+const (
+	elvesID     = "a0b0c0d0-0000-5000-8000-000000000227"
+	elvesFoilID = "a0b0c0d0-0000-5000-8000-000000000227_f"
+	storeURL    = "https://shop.95gamecenter.com/app.php"
+)
+
+func TestLoad(t *testing.T) {
+	nf := scrapertest.Load(t, "testdata/replay", "testdata/allprintings.json", func() (*Ninetyfive, error) {
+		return NewScraper(GameMagic)
+	})
+
+	// Mint copies are not listed, and neither are the Japanese printing,
+	// missing from the datastore, nor the Lorcana card
+	scrapertest.AssertInventory(t, nf.Inventory(), mtgban.InventoryRecord{
+		elvesID: {
+			{Conditions: "NM", Price: 0.25, Quantity: 3, URL: storeURL, OriginalID: "FDN_227", InstanceID: "FDN_227_NM_EN_false"},
+		},
+		elvesFoilID: {
+			{Conditions: "SP", Price: 1.5, Quantity: 1, URL: storeURL, OriginalID: "FDN_227", InstanceID: "FDN_227_LP_EN_true"},
+		},
+	})
+
+	// The same offer covers both finishes
+	scrapertest.AssertBuylist(t, nf.Buylist(), mtgban.BuylistRecord{
+		elvesID: {
+			{Conditions: "NM", BuyPrice: 0.1, PriceRatio: 0.1 / 0.25 * 100, Quantity: 8, URL: storeURL, OriginalID: "FDN_227"},
+		},
+		elvesFoilID: {
+			{Conditions: "NM", BuyPrice: 0.1, PriceRatio: 0.1 / 1.5 * 100, Quantity: 8, URL: storeURL, OriginalID: "FDN_227"},
+		},
+	})
+
+	report := nf.MatchStats().Report()
+	if report.Matched != 4 || report.Unsupported != 1 || report.Failed != 0 {
+		t.Errorf("unexpected match report %+v", report)
+	}
+}
@@ -14,7 +14,6 @@ import (
 	"github.com/mtgban/go-mtgban/mtgmatcher"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/hashicorp/go-retryablehttp"
 )
 
 const (
@@ -34,6 +33,11 @@ type SecretDesKorrigans struct {
 	exchangeRate float64
 
 	client *http.Client
+
+	// Source of the exchange rates, the default one when nil
+	RateProvider mtgban.ExchangeRateProvider
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a scraper, failing if the edition list cannot be read.
@@ -41,7 +45,7 @@ func NewScraper() (*SecretDesKorrigans, error) {
 	sdk := SecretDesKorrigans{}
 	sdk.inventory = mtgban.InventoryRecord{}
 	sdk.MaxConcurrency = defaultConcurrency
-	client := retryablehttp.NewClient()
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	sdk.client = client.StandardClient()
 	return &sdk, nil
@@ -170,7 +174,7 @@ func (sdk *SecretDesKorrigans) processProduct(ctx context.Context, channel chan<
 			return
 		}
 
-		cardID, err := mtgmatcher.Match(theCard)
+		cardID, err := sdk.matchStats.Match(theCard)
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			return
 		} else if err != nil {
@@ -207,10 +211,12 @@ func (sdk *SecretDesKorrigans) processProduct(ctx context.Context, channel chan<
 		out := responseChan{
 			cardID: cardID,
 			invEntry: &mtgban.InventoryEntry{
-				Price:      price * sdk.exchangeRate,
-				Conditions: conditions,
-				Quantity:   qty,
-				URL:        "https://www.lesecretdeskorrigans.com" + link,
+				Price:       price * sdk.exchangeRate,
+				Conditions:  conditions,
+				Quantity:    qty,
+				URL:         "https://www.lesecretdeskorrigans.com" + link,
+				Currency:    "CAD",
+				NativePrice: price,
 			},
 		}
 		channel <- out
@@ -227,7 +233,7 @@ func (sdk *SecretDesKorrigans) processProduct(ctx context.Context, channel chan<
 
 // Load fetches everything this scraper offers. See mtgban.Scraper.
 func (sdk *SecretDesKorrigans) Load(ctx context.Context) error {
-	rate, err := mtgban.GetExchangeRate(ctx, "CAD")
+	rate, err := mtgban.ExchangeRateFrom(ctx, sdk.RateProvider, "CAD")
 	if err != nil {
 		return err
 	}
@@ -294,10 +300,19 @@ func (sdk *SecretDesKorrigans) Inventory() mtgban.InventoryRecord {
 	return sdk.inventory
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (sdk *SecretDesKorrigans) MatchStats() *mtgban.MatchStats {
+	return &sdk.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (sdk *SecretDesKorrigans) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Le Secret des Korrigans"
 	info.Shorthand = "SK"
 	info.InventoryTimestamp = &sdk.inventoryDate
+	info.Currency = "CAD"
+	if sdk.exchangeRate != 0 {
+		info.ExchangeRates = map[string]float64{"cad": sdk.exchangeRate}
+	}
 	return
 }
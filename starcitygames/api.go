@@ -6,7 +6,7 @@ import (
 	"net/url"
 	"time"
 
-	"github.com/hashicorp/go-retryablehttp"
+	"github.com/mtgban/go-mtgban/mtgban"
 )
 
 // The games this scraper covers, as SCG's API numbers them
@@ -26,7 +26,7 @@ type SCGClient struct {
 // NewSCGClient returns a client using the given API key.
 func NewSCGClient(apiKey string) *SCGClient {
 	scg := SCGClient{}
-	cli := retryablehttp.NewClient()
+	cli := mtgban.NewRetryableClient()
 	cli.Logger = nil
 	cli.RetryMax = 10
 	cli.RetryWaitMin = 2 * time.Second
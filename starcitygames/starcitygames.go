@@ -29,6 +29,8 @@ type Starcitygames struct {
 	setIDs map[string]int
 	client *SCGClient
 	game   int
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a singles scraper for one game, using the given API key.
@@ -74,6 +76,12 @@ func (scg *Starcitygames) processProduct(p CatalogProduct) {
 	}
 
 	cardID, err := resolveProduct(scg.game, p)
+	scg.matchStats.Record(&mtgmatcher.InputCard{
+		Name:      p.Name,
+		Edition:   p.Set,
+		Variation: p.CollectorNumber,
+		Foil:      catalogFoil(p),
+	}, err)
 	if err != nil {
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			return
@@ -225,6 +233,11 @@ func (scg *Starcitygames) Buylist() mtgban.BuylistRecord {
 	return scg.buylist
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (scg *Starcitygames) MatchStats() *mtgban.MatchStats {
+	return &scg.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (scg *Starcitygames) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Star City Games"
@@ -10,9 +10,10 @@ import (
 	"strings"
 	"time"
 
-	"github.com/gocolly/colly/v2"
+	"github.com/PuerkitoBio/goquery"
 
 	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/go-mtgban/mtgban/crawl"
 	"github.com/mtgban/go-mtgban/mtgmatcher"
 )
 
@@ -47,6 +48,10 @@ type Strikezone struct {
 	DisableBuylist bool
 
 	game string
+
+	crawler *crawl.Crawler
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a scraper for one game.
@@ -56,6 +61,8 @@ func NewScraper(game string) *Strikezone {
 	sz.buylist = mtgban.BuylistRecord{}
 	sz.MaxConcurrency = defaultConcurrency
 	sz.game = game
+	sz.crawler = crawl.NewCrawler()
+	sz.crawler.RandomDelay = time.Second
 	return &sz
 }
 
@@ -71,16 +78,16 @@ type respChan struct {
 	bl     *mtgban.BuylistEntry
 }
 
-func (sz *Strikezone) processRow(mode string, channel chan<- respChan, el *colly.HTMLElement, edition string) error {
+func (sz *Strikezone) processRow(mode string, channel chan<- respChan, el *goquery.Selection, edition string) error {
 	var cardName, pathURL, notes, cond, qty, price string
 
-	cardName = el.ChildText("td:nth-child(1)")
+	cardName = crawl.Text(el, "td:nth-child(1)")
 	if cardName == "" || cardName == "Name" {
 		// No error as empty page may not have anything to process
 		return nil
 	}
 
-	pathURL = el.ChildAttr("a", "href")
+	pathURL = crawl.Attr(el, "a", "href")
 
 	// The columns and card construction differ per game; the match and error
 	// handling below are shared.
@@ -88,15 +95,15 @@ func (sz *Strikezone) processRow(mode string, channel chan<- respChan, el *colly
 	switch sz.game {
 	case GameMagic:
 		if mode == modeRetail {
-			notes = el.ChildText("td:nth-child(4)")
-			cond = el.ChildText("td:nth-child(5)")
-			qty = el.ChildText("td:nth-child(6)")
-			price = el.ChildText("td:nth-child(7)")
+			notes = crawl.Text(el, "td:nth-child(4)")
+			cond = crawl.Text(el, "td:nth-child(5)")
+			qty = crawl.Text(el, "td:nth-child(6)")
+			price = crawl.Text(el, "td:nth-child(7)")
 		} else if mode == modeBuylist {
-			notes = el.ChildText("td:nth-child(4)")
+			notes = crawl.Text(el, "td:nth-child(4)")
 			cond = notes
-			qty = el.ChildText("td:nth-child(5)")
-			price = el.ChildText("td:nth-child(6)")
+			qty = crawl.Text(el, "td:nth-child(5)")
+			price = crawl.Text(el, "td:nth-child(6)")
 		}
 
 		c, err := preprocess(cardName, edition, notes)
@@ -105,10 +112,10 @@ func (sz *Strikezone) processRow(mode string, channel chan<- respChan, el *colly
 		}
 		theCard = c
 	case GameLorcana:
-		notes = el.ChildText("td:nth-child(2)")
-		cond = el.ChildText("td:nth-child(4)")
-		qty = el.ChildText("td:nth-child(5)")
-		price = el.ChildText("td:nth-child(6)")
+		notes = crawl.Text(el, "td:nth-child(2)")
+		cond = crawl.Text(el, "td:nth-child(4)")
+		qty = crawl.Text(el, "td:nth-child(5)")
+		price = crawl.Text(el, "td:nth-child(6)")
 
 		foil := strings.Contains(strings.ToLower(cond), "foil")
 		theCard = &mtgmatcher.InputCard{Name: cardName, Edition: edition, Variation: notes, Foil: foil}
@@ -116,7 +123,7 @@ func (sz *Strikezone) processRow(mode string, channel chan<- respChan, el *colly
 		return nil
 	}
 
-	cardID, err := mtgmatcher.Match(theCard)
+	cardID, err := sz.matchStats.Match(theCard)
 	if errors.Is(err, mtgmatcher.ErrUnsupported) {
 		return nil
 	} else if err != nil {
@@ -204,38 +211,43 @@ func (sz *Strikezone) processRow(mode string, channel chan<- respChan, el *colly
 	return nil
 }
 
-func (sz *Strikezone) scrape(ctx context.Context, mode string) error {
-	channel := make(chan respChan)
-
-	c := colly.NewCollector(
-		colly.AllowedDomains("shop.strikezoneonline.com"),
+// visit pushes the listings of a page, returning the other pages of the
+// same mode it links to.
+func (sz *Strikezone) visit(ctx context.Context, mode string, page *crawl.Page, channel chan<- respChan) ([]string, error) {
+	edition := crawl.Text(page.Doc.Selection, "h1")
+	edition = strings.TrimSuffix(edition, " Buy Lists")
+	edition = strings.TrimPrefix(edition, "Singles ")
 
-		// Cache responses to prevent multiple download of pages
-		// even if the collector is restarted - daily
-		colly.CacheDir(fmt.Sprintf(".cache/%d", time.Now().YearDay())),
+	sz.printf("Parsing %s", edition)
 
-		colly.Async(true),
-
-		colly.StdlibContext(ctx),
-	)
+	tableRowName := "table.rtti tr"
+	if mode == modeBuylist || sz.game == GameLorcana {
+		tableRowName = "table.ItemTable tr"
+	}
 
-	c.Limit(&colly.LimitRule{
-		DomainGlob:  "*",
-		RandomDelay: 1 * time.Second,
-		Parallelism: sz.MaxConcurrency,
+	page.Doc.Find(tableRowName).Each(func(_ int, el *goquery.Selection) {
+		err := sz.processRow(mode, channel, el, edition)
+		if err != nil {
+			cardName := crawl.Text(el, "td:nth-child(1)")
+			sz.printf("cannot process %s %s (%s): %s", mode, cardName, edition, err.Error())
+			sz.printf("-> %s", page.URL)
+		}
 	})
 
-	c.OnRequest(func(r *colly.Request) {
-		//sz.printf("Visiting %s", r.URL.String())
-	})
+	// Links to edition names
+	basePath := "/Category/"
+	if mode == modeBuylist {
+		basePath = "/BuyList/"
+	}
 
-	// Callback for links on scraped pages (edition names)
-	c.OnHTML("a[href]", func(e *colly.HTMLElement) {
-		link := e.Attr("href")
+	var links []string
+	page.Doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		link, _ := s.Attr("href")
+		link = page.Abs(link)
 
-		basePath := "/Category/"
-		if mode == modeBuylist {
-			basePath = "/BuyList/"
+		u, err := url.Parse(link)
+		if err != nil || u.Host != page.URL.Host {
+			return
 		}
 
 		if strings.Contains(link, basePath) &&
@@ -246,33 +258,13 @@ func (sz *Strikezone) scrape(ctx context.Context, mode string) error {
 			!strings.HasSuffix(link, "Fat_Packs.html") &&
 			!strings.HasSuffix(link, "Gift_Sets_and_Secret_Lairs.html") &&
 			!strings.HasSuffix(link, "Preconstructed_Decks.html") {
-			c.Visit(e.Request.AbsoluteURL(link))
+			links = append(links, link)
 		}
 	})
+	return links, nil
+}
 
-	// Callback for when a scraped page contains a form element
-	c.OnHTML("body", func(e *colly.HTMLElement) {
-		edition := e.ChildText("h1")
-		edition = strings.TrimSuffix(edition, " Buy Lists")
-		edition = strings.TrimPrefix(edition, "Singles ")
-
-		sz.printf("Parsing %s", edition)
-
-		tableRowName := "table.rtti tr"
-		if mode == modeBuylist || sz.game == GameLorcana {
-			tableRowName = "table.ItemTable tr"
-		}
-
-		e.ForEach(tableRowName, func(_ int, el *colly.HTMLElement) {
-			err := sz.processRow(mode, channel, el, edition)
-			if err != nil {
-				cardName := el.ChildText("td:nth-child(1)")
-				sz.printf("cannot process %s %s (%s): %s", mode, cardName, edition, err.Error())
-				sz.printf("-> %s", e.Request.URL)
-			}
-		})
-	})
-
+func (sz *Strikezone) scrape(ctx context.Context, mode string) error {
 	var link string
 	if mode == modeRetail {
 		link = fmt.Sprintf(szInventoryURL, sz.game)
@@ -280,27 +272,27 @@ func (sz *Strikezone) scrape(ctx context.Context, mode string) error {
 		link = fmt.Sprintf(szBuylistURL, sz.game)
 	}
 	sz.printf("Visiting %s", link)
-	c.Visit(link)
-
-	go func() {
-		c.Wait()
-		close(channel)
-	}()
-
-	for resp := range channel {
-		if resp.inv != nil {
-			err := sz.inventory.Add(resp.cardID, resp.inv)
-			if err != nil {
-				sz.printf("%v", err)
+
+	crawl.Run(ctx, sz.crawler, sz.MaxConcurrency, []string{link},
+		func(ctx context.Context, page *crawl.Page, channel chan<- respChan) ([]string, error) {
+			return sz.visit(ctx, mode, page, channel)
+		},
+		func(resp respChan) {
+			if resp.inv != nil {
+				err := sz.inventory.Add(resp.cardID, resp.inv)
+				if err != nil {
+					sz.printf("%v", err)
+				}
 			}
-		}
-		if resp.bl != nil {
-			err := sz.buylist.Add(resp.cardID, resp.bl)
-			if err != nil {
-				sz.printf("%v", err)
+			if resp.bl != nil {
+				err := sz.buylist.Add(resp.cardID, resp.bl)
+				if err != nil {
+					sz.printf("%v", err)
+				}
 			}
-		}
-	}
+		},
+		sz.printf,
+	)
 
 	if mode == modeRetail {
 		sz.inventoryDate = time.Now()
@@ -349,6 +341,11 @@ func (sz *Strikezone) Buylist() mtgban.BuylistRecord {
 	return sz.buylist
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (sz *Strikezone) MatchStats() *mtgban.MatchStats {
+	return &sz.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (sz *Strikezone) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Strike Zone"
@@ -0,0 +1,48 @@
+package strikezone
+
+import (
+	"testing"
+
+	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/go-mtgban/mtgban/scrapertest"
+)
+
+const (
+	elvesID            = "a0b0c0d0-0000-5000-8000-000000000227"
+	elvesFoilID        = "a0b0c0d0-0000-5000-8000-000000000227_f"
+	counterspellID     = "a0b0c0d0-0000-5000-8000-000000000152"
+	counterspellFoilID = "a0b0c0d0-0000-5000-8000-000000000152_f"
+
+	buylistSearchURL = "http://shop.strikezoneonline.com/TUser?MC=CUSTS&MF=B&BUID=637&ST=D&M=B&CMD=Search&T="
+)
+
+func TestLoad(t *testing.T) {
+	sz := scrapertest.Load(t, "testdata/replay", "testdata/allprintings.json", func() (*Strikezone, error) {
+		sz := NewScraper(GameMagic)
+		sz.crawler.RandomDelay = 0
+		return sz, nil
+	})
+
+	// Only the edition pages of each mode are followed, and the damaged copy
+	// has no grade to map to
+	scrapertest.AssertInventory(t, sz.Inventory(), mtgban.InventoryRecord{
+		elvesID: {
+			{Conditions: "NM", Price: 0.29, Quantity: 4, URL: "http://shop.strikezoneonline.com/p/Llanowar_Elves.html"},
+		},
+		elvesFoilID: {
+			{Conditions: "SP", Price: 1.99, Quantity: 1, URL: "http://shop.strikezoneonline.com/p/Llanowar_Elves_Foil.html"},
+		},
+		counterspellID: {
+			{Conditions: "HP", Price: 0.5, Quantity: 1, URL: "http://shop.strikezoneonline.com/p/Counterspell.html"},
+		},
+	})
+
+	scrapertest.AssertBuylist(t, sz.Buylist(), mtgban.BuylistRecord{
+		elvesID: {
+			{Conditions: "NM", BuyPrice: 0.05, PriceRatio: 0.05 / 0.29 * 100, Quantity: 20, URL: buylistSearchURL + "Llanowar+Elves"},
+		},
+		counterspellFoilID: {
+			{Conditions: "NM", BuyPrice: 0.4, Quantity: 5, URL: buylistSearchURL + "Counterspell"},
+		},
+	})
+}
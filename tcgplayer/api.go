@@ -11,8 +11,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/hashicorp/go-cleanhttp"
-	"github.com/hashicorp/go-retryablehttp"
+	"github.com/mtgban/go-mtgban/mtgban"
 	"github.com/mtgban/go-mtgban/mtgmatcher"
 	"github.com/mtgban/go-tcgplayer"
 )
@@ -221,7 +220,7 @@ func LatestSales(ctx context.Context, tcgProductID string, flags ...bool) ([]Lat
 	req.Header.Set("User-Agent", staticUA)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := cleanhttp.DefaultClient().Do(req)
+	resp, err := mtgban.NewHTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -250,7 +249,7 @@ const (
 // SellerKeyExists reports whether the storefront still serves a page for the
 // seller key.
 func SellerKeyExists(ctx context.Context, sellerKey string) bool {
-	client := cleanhttp.DefaultClient()
+	client := mtgban.NewHTTPClient()
 
 	// Do not follow redirects
 	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
@@ -320,7 +319,7 @@ func SellerName2ID(ctx context.Context, sellerName string) (string, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := cleanhttp.DefaultClient().Do(req)
+	resp, err := mtgban.NewHTTPClient().Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -512,7 +511,7 @@ type SellerClient struct {
 // NewSellerClient returns a client for the storefront's search API.
 func NewSellerClient() *SellerClient {
 	tcg := SellerClient{}
-	client := retryablehttp.NewClient()
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	tcg.client = client.StandardClient()
 	return &tcg
@@ -687,7 +686,7 @@ type CookieClient struct {
 // NewCookieClient returns a client acting as the user whose auth cookie is
 // given.
 func NewCookieClient(authKey string) *CookieClient {
-	client := retryablehttp.NewClient()
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	tcg := CookieClient{}
 	tcg.cookieLine = "TCGAuthTicket_Production=" + authKey + ";"
@@ -698,7 +697,7 @@ func NewCookieClient(authKey string) *CookieClient {
 // NewCookieSetClient returns a client carrying a whole cookie jar, for the
 // pages that need more than the auth cookie alone.
 func NewCookieSetClient(cookies map[string]string) *CookieClient {
-	client := retryablehttp.NewClient()
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	tcg := CookieClient{}
 	for name, value := range cookies {
@@ -835,7 +834,7 @@ func CreateCartKey(ctx context.Context, userID string) (string, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := cleanhttp.DefaultClient().Do(req)
+	resp, err := mtgban.NewHTTPClient().Do(req)
 	if err != nil {
 		return "", err
 	}
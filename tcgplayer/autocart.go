@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 
-	"github.com/hashicorp/go-cleanhttp"
+	"github.com/mtgban/go-mtgban/mtgban"
 )
 
 const tcgAdd2CartURL = "https://mpgateway.tcgplayer.com/v1/cart/%s/item/add"
@@ -21,7 +24,7 @@ type TCGAutoClient struct {
 // NewTCGAutoClient returns a cart client for the cart key given.
 func NewTCGAutoClient(cartID string) *TCGAutoClient {
 	tcg := TCGAutoClient{}
-	tcg.client = cleanhttp.DefaultClient()
+	tcg.client = mtgban.NewHTTPClient()
 	tcg.cartID = cartID
 	return &tcg
 }
@@ -89,3 +92,112 @@ func (tcg *TCGAutoClient) AddProductToCart(ctx context.Context, sellerKey string
 
 	return &response, nil
 }
+
+// maxCartListingPages bounds how far the live listings are walked looking for
+// a seller for an entry, since they are sorted by price and the one wanted is
+// almost always near the top.
+const maxCartListingPages = 5
+
+// Activate prepares the market to fill the cart with the given key. The
+// password is not used: the cart endpoint is keyed by the cart alone.
+func (tcg *Market) Activate(ctx context.Context, cartKey, _ string) error {
+	if cartKey == "" {
+		return errors.New("missing cart key")
+	}
+	tcg.cart = NewTCGAutoClient(cartKey)
+	tcg.cartListings = NewSellerClient()
+	return nil
+}
+
+// Add puts entry in the cart. See mtgban.Carter.
+//
+// The InstanceID is the SKU to buy. The entries this market produces name
+// the pricing tier rather than a seller, so the seller key is resolved from
+// the live listings of the OriginalID, cheapest first: a Direct listing for
+// a Bundle entry, any listing otherwise. An entry carrying a "sellerKey"
+// custom field, as the seller inventory ones do, is added as is.
+func (tcg *Market) Add(ctx context.Context, entry mtgban.InventoryEntry) error {
+	if tcg.cart == nil {
+		return mtgban.ErrCartNotActive
+	}
+
+	skuID, err := strconv.Atoi(entry.InstanceID)
+	if err != nil {
+		return fmt.Errorf("invalid sku %q: %w", entry.InstanceID, err)
+	}
+	qty := entry.Quantity
+	if qty < 1 {
+		qty = 1
+	}
+
+	sellerKey := entry.CustomFields["sellerKey"]
+	isDirect := entry.Bundle
+	if sellerKey == "" {
+		listing, err := tcg.listingForEntry(ctx, entry, skuID, qty)
+		if err != nil {
+			return err
+		}
+		sellerKey = listing.SellerKey
+	}
+
+	response, err := tcg.cart.AddProductToCart(ctx, sellerKey, skuID, qty, isDirect)
+	if err != nil {
+		return err
+	}
+	for _, result := range response.Results {
+		if result.ItemQuantityInCart < qty {
+			return fmt.Errorf("only %d of %d added for sku %d (seller %s has %d)",
+				result.ItemQuantityInCart, qty, skuID, sellerKey, result.SellerQuantityAvailable)
+		}
+	}
+
+	return nil
+}
+
+// listingForEntry finds the cheapest live listing that can fill entry. A
+// listing that cannot cover the whole quantity is kept as a fallback, so a
+// partial fill is reported by the cart rather than refused here.
+func (tcg *Market) listingForEntry(ctx context.Context, entry mtgban.InventoryEntry, skuID, qty int) (*SellerListing, error) {
+	productID, err := strconv.Atoi(entry.OriginalID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid product id %q: %w", entry.OriginalID, err)
+	}
+
+	// A name that is not one of the market tiers is an actual seller
+	sellerName := entry.SellerName
+	if slices.Contains(availableMarketNames, sellerName) {
+		sellerName = ""
+	}
+
+	var fallback *SellerListing
+	for page := 0; page < maxCartListingPages; page++ {
+		listings, err := tcg.cartListings.InventoryListing(ctx, productID, defaultListingSize, page, entry.Bundle)
+		if err != nil {
+			return nil, err
+		}
+		if len(listings) == 0 {
+			break
+		}
+
+		for i := range listings {
+			listing := &listings[i]
+			if int(listing.ProductConditionID) != skuID {
+				continue
+			}
+			if sellerName != "" && listing.SellerName != sellerName {
+				continue
+			}
+			if int(listing.Quantity) >= qty {
+				return listing, nil
+			}
+			if fallback == nil {
+				fallback = listing
+			}
+		}
+	}
+	if fallback == nil {
+		return nil, fmt.Errorf("no live listing for sku %d of product %d", skuID, productID)
+	}
+
+	return fallback, nil
+}
@@ -0,0 +1,118 @@
+package tcgplayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/go-mtgban/mtgban/scrapertest"
+)
+
+// The fixtures hold the live listings of product 100, cheapest first over
+// two pages, and a cart filling each request up to what the seller has:
+//
+//	sku 7  cheap        1 copy
+//	sku 8  other-sku   10 copies
+//	sku 7  named        4 copies, "Named Seller"
+//	sku 7  bulk        10 copies, "Bulk Seller", on the second page
+//
+// They only answer the requests each entry should make, so a wrong sku,
+// seller or quantity is a miss failing the test.
+func testCartMarket(t *testing.T) *Market {
+	scrapertest.Replay(t, "testdata/cart")
+
+	tcg := &Market{}
+	err := tcg.Activate(context.Background(), "cart", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tcg
+}
+
+func TestListingForEntry(t *testing.T) {
+	tcg := testCartMarket(t)
+
+	tests := []struct {
+		name      string
+		entry     mtgban.InventoryEntry
+		skuID     int
+		qty       int
+		sellerKey string
+	}{
+		{"cheapest of the tier", mtgban.InventoryEntry{OriginalID: "100", SellerName: "TCG Player"}, 7, 1, "cheap"},
+		{"enough quantity", mtgban.InventoryEntry{OriginalID: "100", SellerName: "TCG Player"}, 7, 3, "named"},
+		{"actual seller", mtgban.InventoryEntry{OriginalID: "100", SellerName: "Named Seller"}, 7, 1, "named"},
+		{"seller on a later page", mtgban.InventoryEntry{OriginalID: "100", SellerName: "Bulk Seller"}, 7, 1, "bulk"},
+		{"other sku", mtgban.InventoryEntry{OriginalID: "100", SellerName: "TCG Direct"}, 8, 1, "other-sku"},
+		{"partial fallback", mtgban.InventoryEntry{OriginalID: "100", SellerName: "TCG Player"}, 7, 20, "cheap"},
+		{"no such sku", mtgban.InventoryEntry{OriginalID: "100", SellerName: "TCG Player"}, 9, 1, ""},
+		{"no such seller", mtgban.InventoryEntry{OriginalID: "100", SellerName: "Nobody"}, 7, 1, ""},
+		{"invalid product", mtgban.InventoryEntry{OriginalID: "abc", SellerName: "TCG Player"}, 7, 1, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			listing, err := tcg.listingForEntry(context.Background(), test.entry, test.skuID, test.qty)
+			if test.sellerKey == "" {
+				if err == nil {
+					t.Errorf("expected an error, got seller %s", listing.SellerKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if listing.SellerKey != test.sellerKey {
+				t.Errorf("expected seller %s, got %s", test.sellerKey, listing.SellerKey)
+			}
+		})
+	}
+}
+
+func TestMarketAdd(t *testing.T) {
+	err := (&Market{}).Add(context.Background(), mtgban.InventoryEntry{InstanceID: "7"})
+	if !errors.Is(err, mtgban.ErrCartNotActive) {
+		t.Fatalf("expected ErrCartNotActive, got %v", err)
+	}
+
+	tcg := testCartMarket(t)
+
+	tests := []struct {
+		name    string
+		entry   mtgban.InventoryEntry
+		failure bool
+	}{
+		{"resolved seller", mtgban.InventoryEntry{InstanceID: "7", OriginalID: "100", SellerName: "TCG Player", Quantity: 3}, false},
+		{"seller key given", mtgban.InventoryEntry{InstanceID: "7", OriginalID: "100", SellerName: "Given Seller", CustomFields: map[string]string{"sellerKey": "given"}}, false},
+		{"direct bundle", mtgban.InventoryEntry{InstanceID: "7", OriginalID: "100", SellerName: "TCG Direct", Bundle: true}, false},
+		{"partial fill", mtgban.InventoryEntry{InstanceID: "7", OriginalID: "100", SellerName: "TCG Player", Quantity: 20}, true},
+		{"invalid sku", mtgban.InventoryEntry{InstanceID: "abc", OriginalID: "100", SellerName: "TCG Player"}, true},
+		{"no listing", mtgban.InventoryEntry{InstanceID: "9", OriginalID: "100", SellerName: "TCG Player"}, true},
+	}
+
+	var entries []mtgban.InventoryEntry
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := tcg.Add(context.Background(), test.entry)
+			if (err != nil) != test.failure {
+				t.Errorf("expected failure %v, got %v", test.failure, err)
+			}
+		})
+		entries = append(entries, test.entry)
+	}
+
+	// The failures are reported one by one, the rest added
+	err = mtgban.AddToCart(context.Background(), tcg, entries)
+	var cartErr *mtgban.CartError
+	if !errors.As(err, &cartErr) {
+		t.Fatalf("expected a CartError, got %v", err)
+	}
+	if cartErr.Total != len(entries) || len(cartErr.Failures) != 3 {
+		t.Fatalf("expected 3 of %d failures, got %+v", len(entries), cartErr)
+	}
+	for i, test := range tests[3:] {
+		if cartErr.Failures[i].Entry.Quantity != test.entry.Quantity || cartErr.Failures[i].Entry.InstanceID != test.entry.InstanceID {
+			t.Errorf("failure %d: expected the %s entry, got %+v", i, test.name, cartErr.Failures[i].Entry)
+		}
+	}
+}
@@ -23,6 +23,10 @@ type TCGGame struct {
 	Affiliate      string
 	MaxConcurrency int
 
+	// Optional directory where to keep the pages already processed, see
+	// mtgban.CheckpointedWorkerPool
+	CheckpointDir string
+
 	inventory mtgban.InventoryRecord
 
 	editions map[int]tcgplayer.Group
@@ -43,6 +47,8 @@ type TCGGame struct {
 	sealedMap map[int][]string
 
 	client *tcgplayer.Client
+
+	matchStats mtgban.MatchStats
 }
 
 func (tcg *TCGGame) printf(format string, a ...any) {
@@ -176,8 +182,8 @@ func (tcg *TCGGame) processPage(ctx context.Context, channel chan<- genericChan,
 					continue
 				}
 				channel <- genericChan{
-					key: uuids[0],
-					entry: mtgban.InventoryEntry{
+					Key: uuids[0],
+					Entry: mtgban.InventoryEntry{
 						Conditions: "NM",
 						Price:      price,
 						Quantity:   1,
@@ -209,7 +215,7 @@ func (tcg *TCGGame) processPage(ctx context.Context, channel chan<- genericChan,
 				Finish:    printing,
 				Foil:      printing != "Normal",
 			}
-			cardID, err := mtgmatcher.Match(theCard)
+			cardID, err := tcg.matchStats.Match(theCard)
 			if errors.Is(err, mtgmatcher.ErrUnsupported) {
 				continue
 			} else if err != nil {
@@ -235,8 +241,8 @@ func (tcg *TCGGame) processPage(ctx context.Context, channel chan<- genericChan,
 			link := GenerateProductURL(sku.ProductID, printing, tcg.Affiliate, condition, "", false)
 
 			out := genericChan{
-				key: cardID,
-				entry: mtgban.InventoryEntry{
+				Key: cardID,
+				Entry: mtgban.InventoryEntry{
 					Conditions: condition,
 					Price:      price,
 					Quantity:   1,
@@ -253,6 +259,12 @@ func (tcg *TCGGame) processPage(ctx context.Context, channel chan<- genericChan,
 	return nil
 }
 
+// SetConfig applies options after the scraper was built. See
+// mtgban.ScraperConfig.
+func (tcg *TCGGame) SetConfig(opt mtgban.ScraperOptions) {
+	tcg.CheckpointDir = opt.CheckpointDir
+}
+
 // Load fetches everything this scraper offers. See mtgban.Scraper.
 func (tcg *TCGGame) Load(ctx context.Context) error {
 	// Initialize data for debug logs
@@ -297,12 +309,23 @@ func (tcg *TCGGame) Load(ctx context.Context) error {
 		pageNums = append(pageNums, i)
 	}
 
-	mtgban.WorkerPool(ctx, tcg.MaxConcurrency, pageNums,
+	// Pages are offsets into the product list, which only stay put while
+	// the total does, so a checkpoint is only resumed for the same total
+	cp, err := mtgban.OpenCheckpoint(tcg.CheckpointDir, fmt.Sprintf("pages-%d", totals))
+	if err != nil {
+		return err
+	}
+	defer cp.Close()
+	if cp.Len() > 0 {
+		tcg.printf("Resuming after %d pages", cp.Len())
+	}
+
+	mtgban.CheckpointedWorkerPool(ctx, cp, tcg.MaxConcurrency, pageNums,
 		func(ctx context.Context, page int, channel chan<- genericChan) error {
 			return tcg.processPage(ctx, channel, page)
 		},
 		func(result genericChan) {
-			err := tcg.inventory.Add(result.key, &result.entry)
+			err := tcg.inventory.Add(result.Key, &result.Entry)
 			if err != nil {
 				tcg.printf("%s", err.Error())
 			}
@@ -320,6 +343,11 @@ func (tcg *TCGGame) Inventory() mtgban.InventoryRecord {
 	return tcg.inventory
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (tcg *TCGGame) MatchStats() *mtgban.MatchStats {
+	return &tcg.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (tcg *TCGGame) Info() (info mtgban.ScraperInfo) {
 	info.Name = "TCGplayer"
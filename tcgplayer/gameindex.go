@@ -23,6 +23,10 @@ type TCGGameIndex struct {
 	Affiliate      string
 	MaxConcurrency int
 
+	// Optional directory where to keep the pages already processed, see
+	// mtgban.CheckpointedWorkerPool
+	CheckpointDir string
+
 	inventory mtgban.InventoryRecord
 
 	editions map[int]tcgplayer.Group
@@ -35,6 +39,8 @@ type TCGGameIndex struct {
 	productTypes []string
 
 	client *tcgplayer.Client
+
+	matchStats mtgban.MatchStats
 }
 
 func (tcg *TCGGameIndex) printf(format string, a ...any) {
@@ -112,7 +118,7 @@ func (tcg *TCGGameIndex) processPage(ctx context.Context, channel chan<- generic
 			Finish:    result.SubTypeName,
 			Foil:      result.SubTypeName != "Normal",
 		}
-		cardID, err := mtgmatcher.Match(theCard)
+		cardID, err := tcg.matchStats.Match(theCard)
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			continue
 		} else if err != nil {
@@ -146,8 +152,8 @@ func (tcg *TCGGameIndex) processPage(ctx context.Context, channel chan<- generic
 			link := GenerateProductURL(result.ProductID, result.SubTypeName, tcg.Affiliate, "", "", isDirect)
 
 			out := genericChan{
-				key: cardID,
-				entry: mtgban.InventoryEntry{
+				Key: cardID,
+				Entry: mtgban.InventoryEntry{
 					Price:      prices[i],
 					Quantity:   1,
 					URL:        link,
@@ -164,6 +170,12 @@ func (tcg *TCGGameIndex) processPage(ctx context.Context, channel chan<- generic
 	return nil
 }
 
+// SetConfig applies options after the scraper was built. See
+// mtgban.ScraperConfig.
+func (tcg *TCGGameIndex) SetConfig(opt mtgban.ScraperOptions) {
+	tcg.CheckpointDir = opt.CheckpointDir
+}
+
 // Load fetches everything this scraper offers. See mtgban.Scraper.
 func (tcg *TCGGameIndex) Load(ctx context.Context) error {
 	// Initialize data for debug logs
@@ -191,12 +203,23 @@ func (tcg *TCGGameIndex) Load(ctx context.Context) error {
 		pageNums = append(pageNums, i)
 	}
 
-	mtgban.WorkerPool(ctx, tcg.MaxConcurrency, pageNums,
+	// Pages are offsets into the product list, which only stay put while
+	// the total does, so a checkpoint is only resumed for the same total
+	cp, err := mtgban.OpenCheckpoint(tcg.CheckpointDir, fmt.Sprintf("pages-%d", totals))
+	if err != nil {
+		return err
+	}
+	defer cp.Close()
+	if cp.Len() > 0 {
+		tcg.printf("Resuming after %d pages", cp.Len())
+	}
+
+	mtgban.CheckpointedWorkerPool(ctx, cp, tcg.MaxConcurrency, pageNums,
 		func(ctx context.Context, page int, channel chan<- genericChan) error {
 			return tcg.processPage(ctx, channel, page)
 		},
 		func(result genericChan) {
-			err := tcg.inventory.Add(result.key, &result.entry)
+			err := tcg.inventory.Add(result.Key, &result.Entry)
 			if err != nil {
 				tcg.printf("%s", err.Error())
 			}
@@ -228,6 +251,11 @@ func (tcg *TCGGameIndex) InfoForScraper(name string) mtgban.ScraperInfo {
 	return info
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (tcg *TCGGameIndex) MatchStats() *mtgban.MatchStats {
+	return &tcg.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (tcg *TCGGameIndex) Info() (info mtgban.ScraperInfo) {
 	info.Name = "TCG Player Index"
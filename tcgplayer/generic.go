@@ -63,9 +63,10 @@ func NewScraperGeneric(publicID, privateID string, category int, productTypes ..
 	return &tcg, nil
 }
 
+// genericChan is one priced sku, saved as is to the checkpoint.
 type genericChan struct {
-	key   string
-	entry mtgban.InventoryEntry
+	Key   string                `json:"key"`
+	Entry mtgban.InventoryEntry `json:"entry"`
 }
 
 func (tcg *Generic) processPage(ctx context.Context, channel chan<- genericChan, page int) error {
@@ -114,8 +115,8 @@ func (tcg *Generic) processPage(ctx context.Context, channel chan<- genericChan,
 			link := GenerateProductURL(result.ProductID, result.SubTypeName, tcg.Affiliate, "", "", isDirect)
 
 			out := genericChan{
-				key: strings.Join(keys, "|"),
-				entry: mtgban.InventoryEntry{
+				Key: strings.Join(keys, "|"),
+				Entry: mtgban.InventoryEntry{
 					Conditions: "NM",
 					Price:      prices[i],
 					Quantity:   1,
@@ -165,7 +166,7 @@ func (tcg *Generic) Load(ctx context.Context) error {
 			return tcg.processPage(ctx, channel, page)
 		},
 		func(result genericChan) {
-			err := tcg.inventory.Add(result.key, &result.entry)
+			err := tcg.inventory.Add(result.Key, &result.Entry)
 			if err != nil {
 				tcg.printf("%s", err.Error())
 			}
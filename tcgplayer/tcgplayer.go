@@ -32,6 +32,9 @@ type Market struct {
 	buylist   mtgban.BuylistRecord
 
 	client *tcgplayer.Client
+
+	cart         *TCGAutoClient
+	cartListings *SellerClient
 }
 
 type marketChan struct {
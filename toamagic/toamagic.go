@@ -14,7 +14,6 @@ import (
 	"github.com/mtgban/go-mtgban/mtgmatcher"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/hashicorp/go-retryablehttp"
 )
 
 const (
@@ -32,6 +31,8 @@ type TOAMagic struct {
 	inventory     mtgban.InventoryRecord
 
 	client *http.Client
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a scraper.
@@ -39,7 +40,7 @@ func NewScraper() *TOAMagic {
 	toa := TOAMagic{}
 	toa.inventory = mtgban.InventoryRecord{}
 	toa.MaxConcurrency = defaultConcurrency
-	client := retryablehttp.NewClient()
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	toa.client = client.StandardClient()
 	return &toa
@@ -163,7 +164,7 @@ func (toa *TOAMagic) processProduct(ctx context.Context, channel chan<- response
 			return
 		}
 
-		cardID, err := mtgmatcher.Match(theCard)
+		cardID, err := toa.matchStats.Match(theCard)
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			return
 		} else if err != nil {
@@ -283,6 +284,11 @@ func (toa *TOAMagic) Inventory() mtgban.InventoryRecord {
 	return toa.inventory
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (toa *TOAMagic) MatchStats() *mtgban.MatchStats {
+	return &toa.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (toa *TOAMagic) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Tales of Adventure"
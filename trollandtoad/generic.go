@@ -12,11 +12,9 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/gocolly/colly/v2"
-	"github.com/gocolly/colly/v2/queue"
-	"github.com/hashicorp/go-cleanhttp"
 
 	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/go-mtgban/mtgban/crawl"
 	"github.com/mtgban/go-mtgban/mtgmatcher"
 )
 
@@ -46,6 +44,10 @@ type Generic struct {
 	DisableBuylist bool
 
 	game string
+
+	crawler *crawl.Crawler
+
+	matchStats mtgban.MatchStats
 }
 
 // NewGenericScraper returns a singles scraper for one game.
@@ -56,6 +58,7 @@ func NewGenericScraper(game string) *Generic {
 	tnt.game = game
 
 	tnt.MaxConcurrency = defaultConcurrency
+	tnt.crawler = newCrawler()
 	return &tnt
 }
 
@@ -65,37 +68,15 @@ func (tnt *Generic) printf(format string, a ...any) {
 	}
 }
 
-func (tnt *Generic) parsePages(ctx context.Context, link string, lastPage int) error {
-	channel := make(chan responseChan)
-
-	c := colly.NewCollector(
-		colly.AllowedDomains("www.trollandtoad.com"),
-
-		colly.CacheDir(fmt.Sprintf(".cache/%d", time.Now().YearDay())),
-
-		colly.Async(true),
-
-		colly.StdlibContext(ctx),
-	)
-
-	c.SetClient(cleanhttp.DefaultClient())
-
-	c.Limit(&colly.LimitRule{
-		DomainGlob:  "*",
-		RandomDelay: 2 * time.Second,
-		Parallelism: tnt.MaxConcurrency,
-	})
-
-	c.OnRequest(func(r *colly.Request) {
-		tnt.printf("Visiting page %s", r.URL.Query().Get("page-no"))
-	})
+func (tnt *Generic) visit(ctx context.Context, page *crawl.Page, channel chan<- responseChan) ([]string, error) {
+	tnt.printf("Visiting page %s", page.URL.Query().Get("page-no"))
 
-	c.OnHTML(`div[class="product-col col-12 p-0 my-1 mx-sm-1 mw-100"]`, func(e *colly.HTMLElement) {
-		link := e.ChildAttr(`a[class='card-text']`, "href")
-		title := e.ChildText(`a[class='card-text']`)
-		edition := e.ChildText(`div[class='row mb-2'] div[class='col-12 prod-cat']`)
+	page.Doc.Find(productSelector).Each(func(_ int, e *goquery.Selection) {
+		link := crawl.Attr(e, `a[class='card-text']`, "href")
+		title := crawl.Text(e, `a[class='card-text']`)
+		edition := crawl.Text(e, `div[class='row mb-2'] div[class='col-12 prod-cat']`)
 
-		oos := e.ChildText(`div[class='row mb-2 '] div[class='col-12'] div[class='font-weight-bold font-smaller text-muted']`)
+		oos := crawl.Text(e, outOfStockSelector)
 		if oos == "Out of Stock" {
 			return
 		}
@@ -116,7 +97,7 @@ func (tnt *Generic) parsePages(ctx context.Context, link string, lastPage int) e
 		number := chunks[len(chunks)-2]
 		foil := strings.Contains(strings.ToLower(chunks[len(chunks)-1]), "foil")
 
-		cardID, err := mtgmatcher.Match(&mtgmatcher.InputCard{Name: cardName, Variation: number, Foil: foil})
+		cardID, err := tnt.matchStats.Match(&mtgmatcher.InputCard{Name: cardName, Variation: number, Foil: foil})
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			return
 		} else if err != nil {
@@ -135,8 +116,8 @@ func (tnt *Generic) parsePages(ctx context.Context, link string, lastPage int) e
 			return
 		}
 
-		e.ForEach(`div[class="row position-relative align-center py-2 m-auto"]`, func(_ int, el *colly.HTMLElement) {
-			conditions := el.ChildText(`div[class='col-3 text-center p-1']`)
+		e.Find(offerSelector).Each(func(_ int, el *goquery.Selection) {
+			conditions := crawl.Text(el, `div[class='col-3 text-center p-1']`)
 			switch {
 			case strings.Contains(conditions, "Near Mint"):
 				conditions = "NM"
@@ -157,17 +138,12 @@ func (tnt *Generic) parsePages(ctx context.Context, link string, lastPage int) e
 				return
 			}
 
-			qtys := el.ChildTexts(`option`)
-			if len(qtys) == 0 {
-				return
-			}
-			qtyStr := qtys[len(qtys)-1]
-			qty, _ := strconv.Atoi(qtyStr)
+			qty := offerQuantity(el)
 			if qty == 0 {
 				return
 			}
 
-			priceStr := el.ChildText(`div[class='col-2 text-center p-1']`)
+			priceStr := crawl.Text(el, `div[class='col-2 text-center p-1']`)
 			price, err := mtgmatcher.ParsePrice(priceStr)
 			if err != nil {
 				return
@@ -182,73 +158,14 @@ func (tnt *Generic) parsePages(ctx context.Context, link string, lastPage int) e
 					Conditions: conditions,
 					Price:      price,
 					Quantity:   qty,
-					URL:        e.Request.AbsoluteURL(link),
+					URL:        page.Abs(link),
 				},
 			}
 			channel <- out
 		})
 	})
 
-	q, _ := queue.New(
-		tnt.MaxConcurrency,
-		&queue.InMemoryQueueStorage{MaxSize: 10000},
-	)
-
-	for i := 1; i <= lastPage; i++ {
-		opts := fmt.Sprintf(tntOptions, i)
-		q.AddURL(link + opts)
-	}
-
-	q.Run(c)
-
-	go func() {
-		c.Wait()
-		close(channel)
-	}()
-
-	for res := range channel {
-		err := tnt.inventory.Add(res.cardID, res.invEntry)
-		if err != nil {
-			tnt.printf("%v", err)
-		}
-	}
-
-	tnt.inventoryDate = time.Now()
-
-	return nil
-}
-
-func (tnt *Generic) scrapePages(ctx context.Context, link string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, http.NoBody)
-	if err != nil {
-		return err
-	}
-
-	resp, err := cleanhttp.DefaultClient().Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return err
-	}
-
-	lastPage := 0
-	doc.Find(`div[class="lastPage pageLink d-flex font-weight-bold"]`).Each(func(_ int, s *goquery.Selection) {
-		page, _ := s.Attr("data-page")
-		lastPage, err = strconv.Atoi(page)
-	})
-	if err != nil {
-		return err
-	}
-
-	if lastPage == 0 {
-		lastPage = 1
-	}
-	tnt.printf("Parsing %d pages from %s", lastPage, link)
-	return tnt.parsePages(ctx, link, lastPage)
+	return nextPages(page, tnt.printf)
 }
 
 func (tnt *Generic) scrape(ctx context.Context) error {
@@ -257,35 +174,32 @@ func (tnt *Generic) scrape(ctx context.Context) error {
 		link = "https://www.trollandtoad.com/disney-lorcana/19773"
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, http.NoBody)
+	page, err := tnt.crawler.Fetch(ctx, link)
 	if err != nil {
 		return err
 	}
-	resp, err := cleanhttp.DefaultClient().Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return err
-	}
 	var pages []string
-	doc.Find(`ul[id="subCatList"] li a`).Each(func(_ int, s *goquery.Selection) {
-		page, found := s.Attr("href")
+	page.Doc.Find(`ul[id="subCatList"] li a`).Each(func(_ int, s *goquery.Selection) {
+		link, found := s.Attr("href")
 		if !found {
 			return
 		}
-		pages = append(pages, page)
+		pages = append(pages, listingPage(page.Abs(link), 1))
 	})
 
-	for _, page := range pages {
-		err := tnt.scrapePages(ctx, "https://www.trollandtoad.com"+page+tntOptions)
-		if err != nil {
-			return err
-		}
-	}
+	crawl.Run(ctx, tnt.crawler, tnt.MaxConcurrency, pages, tnt.visit,
+		func(res responseChan) {
+			err := tnt.inventory.Add(res.cardID, res.invEntry)
+			if err != nil {
+				tnt.printf("%v", err)
+			}
+		},
+		tnt.printf,
+	)
+
+	tnt.inventoryDate = time.Now()
+
 	return nil
 }
 
@@ -297,7 +211,7 @@ func (tnt *Generic) scrapeBuylist(ctx context.Context) error {
 		return err
 	}
 
-	resp, err := cleanhttp.DefaultClient().Do(req)
+	resp, err := mtgban.NewHTTPClient().Do(req)
 	if err != nil {
 		return err
 	}
@@ -353,7 +267,7 @@ func (tnt *Generic) scrapeBuylist(ctx context.Context) error {
 		foil := strings.Contains(strings.ToLower(chunks[len(chunks)-1]), "foil")
 		link := buylistLinkURL + cardName
 
-		cardID, err := mtgmatcher.Match(&mtgmatcher.InputCard{Name: cardName, Variation: number, Foil: foil})
+		cardID, err := tnt.matchStats.Match(&mtgmatcher.InputCard{Name: cardName, Variation: number, Foil: foil})
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			continue
 		} else if err != nil {
@@ -450,6 +364,11 @@ func (tnt *Generic) Buylist() mtgban.BuylistRecord {
 	return tnt.buylist
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (tnt *Generic) MatchStats() *mtgban.MatchStats {
+	return &tnt.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (tnt *Generic) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Troll and Toad"
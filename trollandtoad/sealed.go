@@ -2,19 +2,13 @@ package trollandtoad
 
 import (
 	"context"
-	"fmt"
-	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/gocolly/colly/v2"
-	"github.com/gocolly/colly/v2/queue"
-	"github.com/hashicorp/go-cleanhttp"
-	"github.com/hashicorp/go-retryablehttp"
 
 	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/go-mtgban/mtgban/crawl"
 	"github.com/mtgban/go-mtgban/mtgmatcher"
 )
 
@@ -31,7 +25,7 @@ type Sealed struct {
 	inventory mtgban.InventoryRecord
 	buylist   mtgban.BuylistRecord
 
-	client *http.Client
+	crawler *crawl.Crawler
 }
 
 // NewScraperSealed returns a sealed scraper.
@@ -39,9 +33,7 @@ func NewScraperSealed() *Sealed {
 	tnt := Sealed{}
 	tnt.inventory = mtgban.InventoryRecord{}
 	tnt.buylist = mtgban.BuylistRecord{}
-	client := retryablehttp.NewClient()
-	client.Logger = nil
-	tnt.client = client.StandardClient()
+	tnt.crawler = newCrawler()
 	tnt.MaxConcurrency = defaultConcurrency
 
 	tnt.productMap = map[string]string{}
@@ -65,35 +57,13 @@ func (tnt *Sealed) printf(format string, a ...any) {
 	}
 }
 
-func (tnt *Sealed) parsePages(ctx context.Context, link string, lastPage int) error {
-	channel := make(chan responseChan)
+func (tnt *Sealed) visit(ctx context.Context, page *crawl.Page, channel chan<- responseChan) ([]string, error) {
+	tnt.printf("Visiting page %s", page.URL.Query().Get("page-no"))
 
-	c := colly.NewCollector(
-		colly.AllowedDomains("www.trollandtoad.com"),
+	page.Doc.Find(productSelector).Each(func(_ int, e *goquery.Selection) {
+		link := crawl.Attr(e, `a[class='card-text']`, "href")
 
-		colly.CacheDir(fmt.Sprintf(".cache/%d", time.Now().YearDay())),
-
-		colly.Async(true),
-
-		colly.StdlibContext(ctx),
-	)
-
-	c.SetClient(cleanhttp.DefaultClient())
-
-	c.Limit(&colly.LimitRule{
-		DomainGlob:  "*",
-		RandomDelay: 2 * time.Second,
-		Parallelism: tnt.MaxConcurrency,
-	})
-
-	c.OnRequest(func(r *colly.Request) {
-		tnt.printf("Visiting page %s", r.URL.Query().Get("page-no"))
-	})
-
-	c.OnHTML(`div[class="product-col col-12 p-0 my-1 mx-sm-1 mw-100"]`, func(e *colly.HTMLElement) {
-		link := e.ChildAttr(`a[class='card-text']`, "href")
-
-		oos := e.ChildText(`div[class='row mb-2 '] div[class='col-12'] div[class='font-weight-bold font-smaller text-muted']`)
+		oos := crawl.Text(e, outOfStockSelector)
 		if oos == "Out of Stock" {
 			return
 		}
@@ -106,18 +76,13 @@ func (tnt *Sealed) parsePages(ctx context.Context, link string, lastPage int) er
 			return
 		}
 
-		e.ForEach(`div[class="row position-relative align-center py-2 m-auto"]`, func(_ int, el *colly.HTMLElement) {
-			qtys := el.ChildTexts(`option`)
-			if len(qtys) == 0 {
-				return
-			}
-			qtyStr := qtys[len(qtys)-1]
-			qty, _ := strconv.Atoi(qtyStr)
+		e.Find(offerSelector).Each(func(_ int, el *goquery.Selection) {
+			qty := offerQuantity(el)
 			if qty == 0 {
 				return
 			}
 
-			priceStr := el.ChildText(`div[class='col-2 text-center p-1']`)
+			priceStr := crawl.Text(el, `div[class='col-2 text-center p-1']`)
 			price, err := mtgmatcher.ParsePrice(priceStr)
 			if err != nil {
 				tnt.printf("%s", err.Error())
@@ -132,39 +97,14 @@ func (tnt *Sealed) parsePages(ctx context.Context, link string, lastPage int) er
 				invEntry: &mtgban.InventoryEntry{
 					Price:    price,
 					Quantity: qty,
-					URL:      e.Request.AbsoluteURL(link),
+					URL:      page.Abs(link),
 				},
 			}
 			channel <- out
 		})
 	})
 
-	q, _ := queue.New(
-		tnt.MaxConcurrency,
-		&queue.InMemoryQueueStorage{MaxSize: 10000},
-	)
-
-	for i := 0; i < lastPage; i++ {
-		opts := fmt.Sprintf(tntOptions, i+1)
-		q.AddURL(link + opts)
-	}
-
-	q.Run(c)
-
-	go func() {
-		c.Wait()
-		close(channel)
-	}()
-
-	for res := range channel {
-		// Discarded deliberately: too many false positives to be worth
-		// reporting one per entry.
-		_ = tnt.inventory.Add(res.cardID, res.invEntry)
-	}
-
-	tnt.inventoryDate = time.Now()
-
-	return nil
+	return nextPages(page, tnt.printf)
 }
 
 const (
@@ -173,37 +113,18 @@ const (
 
 // Load fetches everything this scraper offers. See mtgban.Scraper.
 func (tnt *Sealed) Load(ctx context.Context) error {
-	link := categorySealedPage + tntOptions
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, http.NoBody)
-	if err != nil {
-		return err
-	}
-
-	resp, err := cleanhttp.DefaultClient().Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return err
-	}
+	crawl.Run(ctx, tnt.crawler, tnt.MaxConcurrency, []string{listingPage(categorySealedPage, 1)}, tnt.visit,
+		func(res responseChan) {
+			// Discarded deliberately: too many false positives to be worth
+			// reporting one per entry.
+			_ = tnt.inventory.Add(res.cardID, res.invEntry)
+		},
+		tnt.printf,
+	)
 
-	lastPage := 0
-	doc.Find(`div[class="lastPage pageLink d-flex font-weight-bold"]`).Each(func(_ int, s *goquery.Selection) {
-		page, _ := s.Attr("data-page")
-		lastPage, err = strconv.Atoi(page)
-	})
-	if err != nil {
-		return err
-	}
+	tnt.inventoryDate = time.Now()
 
-	if lastPage == 0 {
-		lastPage = 1
-	}
-	tnt.printf("Parsing %d pages from sealed", lastPage)
-	return tnt.parsePages(ctx, categorySealedPage, lastPage)
+	return nil
 }
 
 // Inventory returns what Load collected. See mtgban.Seller.
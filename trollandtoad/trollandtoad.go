@@ -6,17 +6,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/gocolly/colly/v2"
-	"github.com/gocolly/colly/v2/queue"
-	"github.com/hashicorp/go-cleanhttp"
 
 	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/go-mtgban/mtgban/crawl"
 	"github.com/mtgban/go-mtgban/mtgmatcher"
 )
 
@@ -24,8 +21,59 @@ const (
 	defaultConcurrency = 8
 
 	tntOptions = "?Keywords=&hide-oos=on&min-price=&max-price=&items-pp=60&item-condition=&sort-order=&page-no=%d&view=list&subproduct=0&Rarity=&Ruleset=&minMana=&maxMana=&minPower=&maxPower=&minToughness=&maxToughness="
+
+	productSelector    = `div[class="product-col col-12 p-0 my-1 mx-sm-1 mw-100"]`
+	offerSelector      = `div[class="row position-relative align-center py-2 m-auto"]`
+	outOfStockSelector = `div[class='row mb-2 '] div[class='col-12'] div[class='font-weight-bold font-smaller text-muted']`
+	lastPageSelector   = `div[class="lastPage pageLink d-flex font-weight-bold"]`
 )
 
+// listingPage returns the link to page n of the listing at link.
+func listingPage(link string, n int) string {
+	return link + fmt.Sprintf(tntOptions, n)
+}
+
+// nextPages returns the links to the pages following the first one of a
+// listing, the only page that is crawled with the number of the last one.
+func nextPages(page *crawl.Page, logf func(string, ...any)) ([]string, error) {
+	if page.URL.Query().Get("page-no") != "1" {
+		return nil, nil
+	}
+
+	lastPage := 0
+	var err error
+	page.Doc.Find(lastPageSelector).Each(func(_ int, s *goquery.Selection) {
+		num, _ := s.Attr("data-page")
+		lastPage, err = strconv.Atoi(num)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if lastPage == 0 {
+		lastPage = 1
+	}
+
+	listing := *page.URL
+	listing.RawQuery = ""
+	logf("Parsing %d pages from %s", lastPage, listing.Path)
+
+	var links []string
+	for i := 2; i <= lastPage; i++ {
+		links = append(links, listingPage(listing.String(), i))
+	}
+	return links, nil
+}
+
+// offerQuantity returns the largest quantity that can be picked for an offer.
+func offerQuantity(el *goquery.Selection) int {
+	qtys := crawl.Texts(el, `option`)
+	if len(qtys) == 0 {
+		return 0
+	}
+	qty, _ := strconv.Atoi(qtys[len(qtys)-1])
+	return qty
+}
+
 // Trollandtoad prices Troll and Toad's Magic singles, both what they sell and
 // what they buy.
 type Trollandtoad struct {
@@ -34,6 +82,10 @@ type Trollandtoad struct {
 	MaxConcurrency int
 
 	inventory mtgban.InventoryRecord
+
+	crawler *crawl.Crawler
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a Magic singles scraper.
@@ -41,9 +93,16 @@ func NewScraper() *Trollandtoad {
 	tnt := Trollandtoad{}
 	tnt.inventory = mtgban.InventoryRecord{}
 	tnt.MaxConcurrency = defaultConcurrency
+	tnt.crawler = newCrawler()
 	return &tnt
 }
 
+func newCrawler() *crawl.Crawler {
+	crawler := crawl.NewCrawler()
+	crawler.RandomDelay = 2 * time.Second
+	return crawler
+}
+
 type responseChan struct {
 	cardID   string
 	invEntry *mtgban.InventoryEntry
@@ -55,37 +114,15 @@ func (tnt *Trollandtoad) printf(format string, a ...any) {
 	}
 }
 
-func (tnt *Trollandtoad) parsePages(ctx context.Context, link string, lastPage int) error {
-	channel := make(chan responseChan)
-
-	c := colly.NewCollector(
-		colly.AllowedDomains("www.trollandtoad.com"),
-
-		colly.CacheDir(fmt.Sprintf(".cache/%d", time.Now().YearDay())),
-
-		colly.Async(true),
-
-		colly.StdlibContext(ctx),
-	)
-
-	c.SetClient(cleanhttp.DefaultClient())
-
-	c.Limit(&colly.LimitRule{
-		DomainGlob:  "*",
-		RandomDelay: 2 * time.Second,
-		Parallelism: tnt.MaxConcurrency,
-	})
-
-	c.OnRequest(func(r *colly.Request) {
-		tnt.printf("Visiting page %s", r.URL.Query().Get("page-no"))
-	})
+func (tnt *Trollandtoad) visit(ctx context.Context, page *crawl.Page, channel chan<- responseChan) ([]string, error) {
+	tnt.printf("Visiting page %s", page.URL.Query().Get("page-no"))
 
-	c.OnHTML(`div[class="product-col col-12 p-0 my-1 mx-sm-1 mw-100"]`, func(e *colly.HTMLElement) {
-		link := e.ChildAttr(`a[class='card-text']`, "href")
-		cardName := e.ChildText(`a[class='card-text']`)
-		edition := e.ChildText(`div[class='row mb-2'] div[class='col-12 prod-cat']`)
+	page.Doc.Find(productSelector).Each(func(_ int, e *goquery.Selection) {
+		link := crawl.Attr(e, `a[class='card-text']`, "href")
+		cardName := crawl.Text(e, `a[class='card-text']`)
+		edition := crawl.Text(e, `div[class='row mb-2'] div[class='col-12 prod-cat']`)
 
-		oos := e.ChildText(`div[class='row mb-2 '] div[class='col-12'] div[class='font-weight-bold font-smaller text-muted']`)
+		oos := crawl.Text(e, outOfStockSelector)
 		if oos == "Out of Stock" {
 			return
 		}
@@ -94,7 +131,7 @@ func (tnt *Trollandtoad) parsePages(ctx context.Context, link string, lastPage i
 		if err != nil {
 			return
 		}
-		cardID, err := mtgmatcher.Match(theCard)
+		cardID, err := tnt.matchStats.Match(theCard)
 		if errors.Is(err, mtgmatcher.ErrUnsupported) {
 			return
 		} else if err != nil {
@@ -122,8 +159,8 @@ func (tnt *Trollandtoad) parsePages(ctx context.Context, link string, lastPage i
 			return
 		}
 
-		e.ForEach(`div[class="row position-relative align-center py-2 m-auto"]`, func(_ int, el *colly.HTMLElement) {
-			conditions := el.ChildText(`div[class='col-3 text-center p-1']`)
+		e.Find(offerSelector).Each(func(_ int, el *goquery.Selection) {
+			conditions := crawl.Text(el, `div[class='col-3 text-center p-1']`)
 			switch {
 			case strings.Contains(conditions, "Near Mint"):
 				conditions = "NM"
@@ -144,17 +181,12 @@ func (tnt *Trollandtoad) parsePages(ctx context.Context, link string, lastPage i
 				return
 			}
 
-			qtys := el.ChildTexts(`option`)
-			if len(qtys) == 0 {
-				return
-			}
-			qtyStr := qtys[len(qtys)-1]
-			qty, _ := strconv.Atoi(qtyStr)
+			qty := offerQuantity(el)
 			if qty == 0 {
 				return
 			}
 
-			priceStr := el.ChildText(`div[class='col-2 text-center p-1']`)
+			priceStr := crawl.Text(el, `div[class='col-2 text-center p-1']`)
 			price, err := mtgmatcher.ParsePrice(priceStr)
 			if err != nil {
 				tnt.printf("%s: %s", theCard, err.Error())
@@ -170,70 +202,14 @@ func (tnt *Trollandtoad) parsePages(ctx context.Context, link string, lastPage i
 					Conditions: conditions,
 					Price:      price,
 					Quantity:   qty,
-					URL:        e.Request.AbsoluteURL(link),
+					URL:        page.Abs(link),
 				},
 			}
 			channel <- out
 		})
 	})
 
-	q, _ := queue.New(
-		tnt.MaxConcurrency,
-		&queue.InMemoryQueueStorage{MaxSize: 10000},
-	)
-
-	for i := 0; i < lastPage; i++ {
-		opts := fmt.Sprintf(tntOptions, i+1)
-		q.AddURL(link + opts)
-	}
-
-	q.Run(c)
-
-	go func() {
-		c.Wait()
-		close(channel)
-	}()
-
-	for res := range channel {
-		// Discarded deliberately: too many false positives to be worth
-		// reporting one per entry.
-		_ = tnt.inventory.Add(res.cardID, res.invEntry)
-	}
-
-	return nil
-}
-
-func (tnt *Trollandtoad) scrapePages(ctx context.Context, link, edition string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, http.NoBody)
-	if err != nil {
-		return err
-	}
-
-	resp, err := cleanhttp.DefaultClient().Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return err
-	}
-
-	lastPage := 0
-	doc.Find(`div[class="lastPage pageLink d-flex font-weight-bold"]`).Each(func(_ int, s *goquery.Selection) {
-		page, _ := s.Attr("data-page")
-		lastPage, err = strconv.Atoi(page)
-	})
-	if err != nil {
-		return err
-	}
-
-	if lastPage == 0 {
-		lastPage = 1
-	}
-	tnt.printf("Parsing %d pages from %s", lastPage, edition)
-	return tnt.parsePages(ctx, link, lastPage)
+	return nextPages(page, tnt.printf)
 }
 
 const (
@@ -242,27 +218,14 @@ const (
 
 // Load fetches everything this scraper offers. See mtgban.Scraper.
 func (tnt *Trollandtoad) Load(ctx context.Context) error {
-	link := categoryPage
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, http.NoBody)
-	if err != nil {
-		return err
-	}
-
-	resp, err := cleanhttp.DefaultClient().Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	page, err := tnt.crawler.Fetch(ctx, categoryPage)
 	if err != nil {
 		return err
 	}
 
 	var pages []string
-	var titles []string
-	doc.Find(`div[class="card-body d-none d-sm-block py-0"] ul li a`).Each(func(_ int, s *goquery.Selection) {
-		page, found := s.Attr("href")
+	page.Doc.Find(`div[class="card-body d-none d-sm-block py-0"] ul li a`).Each(func(_ int, s *goquery.Selection) {
+		link, found := s.Attr("href")
 		if !found {
 			return
 		}
@@ -290,16 +253,17 @@ func (tnt *Trollandtoad) Load(ctx context.Context) error {
 			"Toy Figures":
 			return
 		}
-		pages = append(pages, page)
-		titles = append(titles, title)
+		pages = append(pages, listingPage(page.Abs(link), 1))
 	})
 
-	for i, page := range pages {
-		err := tnt.scrapePages(ctx, "https://www.trollandtoad.com"+page+tntOptions, titles[i])
-		if err != nil {
-			tnt.printf("%s error: %s", titles[i], err.Error())
-		}
-	}
+	crawl.Run(ctx, tnt.crawler, tnt.MaxConcurrency, pages, tnt.visit,
+		func(res responseChan) {
+			// Discarded deliberately: too many false positives to be worth
+			// reporting one per entry.
+			_ = tnt.inventory.Add(res.cardID, res.invEntry)
+		},
+		tnt.printf,
+	)
 
 	tnt.inventoryDate = time.Now()
 
@@ -311,6 +275,11 @@ func (tnt *Trollandtoad) Inventory() mtgban.InventoryRecord {
 	return tnt.inventory
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (tnt *Trollandtoad) MatchStats() *mtgban.MatchStats {
+	return &tnt.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (tnt *Trollandtoad) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Troll and Toad"
@@ -0,0 +1,33 @@
+package trollandtoad
+
+import (
+	"testing"
+
+	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/go-mtgban/mtgban/scrapertest"
+)
+
+const (
+	elvesID     = "a0b0c0d0-0000-5000-8000-000000000227"
+	elvesFoilID = "a0b0c0d0-0000-5000-8000-000000000227_f"
+)
+
+func TestLoad(t *testing.T) {
+	tnt := scrapertest.Load(t, "testdata/replay", "testdata/allprintings.json", func() (*Trollandtoad, error) {
+		tnt := NewScraper()
+		tnt.crawler.RandomDelay = 0
+		return tnt, nil
+	})
+
+	// The first page of the listing tells there is a second one, and the
+	// copies out of stock or graded from a picture are skipped
+	scrapertest.AssertInventory(t, tnt.Inventory(), mtgban.InventoryRecord{
+		elvesID: {
+			{Conditions: "NM", Price: 0.35, Quantity: 3, URL: "https://www.trollandtoad.com/magic-the-gathering/foundations-singles/llanowar-elves/1700001"},
+			{Conditions: "MP", Price: 0.2, Quantity: 1, URL: "https://www.trollandtoad.com/magic-the-gathering/foundations-singles/llanowar-elves/1700001"},
+		},
+		elvesFoilID: {
+			{Conditions: "NM", Price: 1.25, Quantity: 2, URL: "https://www.trollandtoad.com/magic-the-gathering/foundations-foil-singles/llanowar-elves-foil/1700003"},
+		},
+	})
+}
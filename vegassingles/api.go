@@ -9,7 +9,7 @@ import (
 	"net/url"
 	"strconv"
 
-	"github.com/hashicorp/go-retryablehttp"
+	"github.com/mtgban/go-mtgban/mtgban"
 )
 
 const (
@@ -72,7 +72,7 @@ type VSClient struct {
 // NewVSClient returns a client.
 func NewVSClient() *VSClient {
 	vs := VSClient{}
-	client := retryablehttp.NewClient()
+	client := mtgban.NewRetryableClient()
 	client.Logger = nil
 	vs.client = client.StandardClient()
 	return &vs
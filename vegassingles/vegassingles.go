@@ -50,6 +50,8 @@ type Vegassingles struct {
 	buylistDate   time.Time
 	inventory     mtgban.InventoryRecord
 	buylist       mtgban.BuylistRecord
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a scraper.
@@ -74,7 +76,7 @@ func (vs *Vegassingles) processProduct(product VSProduct) error {
 		return err
 	}
 
-	cardID, err := mtgmatcher.Match(theCard)
+	cardID, err := vs.matchStats.Match(theCard)
 	if errors.Is(err, mtgmatcher.ErrUnsupported) {
 		return nil
 	} else if err != nil {
@@ -208,6 +210,11 @@ func (vs *Vegassingles) Buylist() mtgban.BuylistRecord {
 	return vs.buylist
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (vs *Vegassingles) MatchStats() *mtgban.MatchStats {
+	return &vs.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (vs *Vegassingles) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Vegas Singles"
@@ -4,16 +4,15 @@ package wizardscupboard
 import (
 	"context"
 	"errors"
-	"fmt"
 	"net/url"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/gocolly/colly/v2"
+	"github.com/PuerkitoBio/goquery"
 
 	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/go-mtgban/mtgban/crawl"
 	"github.com/mtgban/go-mtgban/mtgmatcher"
 )
 
@@ -30,6 +29,10 @@ type Wizardscupboard struct {
 	MaxConcurrency int
 
 	inventory mtgban.InventoryRecord
+
+	crawler *crawl.Crawler
+
+	matchStats mtgban.MatchStats
 }
 
 // NewScraper returns a buylist scraper.
@@ -37,6 +40,8 @@ func NewScraper() *Wizardscupboard {
 	wc := Wizardscupboard{}
 	wc.inventory = mtgban.InventoryRecord{}
 	wc.MaxConcurrency = defaultConcurrency
+	wc.crawler = crawl.NewCrawler()
+	wc.crawler.RandomDelay = time.Second
 	return &wc
 }
 
@@ -51,30 +56,21 @@ type respChan struct {
 	entry  *mtgban.InventoryEntry
 }
 
-// Load fetches everything this scraper offers. See mtgban.Scraper.
-func (wc *Wizardscupboard) Load(ctx context.Context) error {
-	channel := make(chan respChan)
-
-	c := colly.NewCollector(
-		colly.AllowedDomains("www.wizardscupboard.com"),
-
-		// Cache responses to prevent multiple download of pages
-		// even if the collector is restarted - daily
-		colly.CacheDir(fmt.Sprintf(".cache/%d", time.Now().YearDay())),
-
-		colly.URLFilters(
-			regexp.MustCompile(`^https://www\.wizardscupboard\.com/singles-.+`),
-			regexp.MustCompile(`^https://www\.wizardscupboard\.com/foils-.+`),
-		),
-
-		colly.Async(true),
-		colly.StdlibContext(ctx),
-	)
+// visit pushes the listings of a page, returning the other pages of singles
+// it links to.
+func (wc *Wizardscupboard) visit(ctx context.Context, page *crawl.Page, channel chan<- respChan) ([]string, error) {
+	page.Doc.Find(`table.productListing tr`).Each(func(_ int, s *goquery.Selection) {
+		wc.processRow(channel, s)
+	})
 
-	// Callback for links on scraped pages (edition names)
-	c.OnHTML("a[href]", func(e *colly.HTMLElement) {
-		link := e.Attr("href")
-		u, _ := url.Parse(link)
+	// Links to edition names
+	var links []string
+	page.Doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		u, err := url.Parse(page.Abs(href))
+		if err != nil {
+			return
+		}
 		q := u.Query()
 		if q.Get("osCsid") != "" {
 			return
@@ -93,134 +89,113 @@ func (wc *Wizardscupboard) Load(ctx context.Context) error {
 		q.Set("sort", "1a")
 
 		u.RawQuery = q.Encode()
-		link = u.String()
+		link := u.String()
 
 		if strings.HasPrefix(link, "https://www.wizardscupboard.com/singles-") ||
 			strings.HasPrefix(link, "https://www.wizardscupboard.com/foils-") {
-
-			err := c.Visit(e.Request.AbsoluteURL(link))
-			if err != nil {
-				var ave *colly.AlreadyVisitedError
-				if !errors.As(err, &ave) {
-					wc.printf("error while linking: %s", err.Error())
-				}
-			}
+			links = append(links, link)
 		}
 	})
+	return links, nil
+}
 
-	c.Limit(&colly.LimitRule{
-		DomainGlob:  "*",
-		RandomDelay: 1 * time.Second,
-		Parallelism: wc.MaxConcurrency,
-	})
-
-	c.OnRequest(func(r *colly.Request) {
-		//log.Printf("Visiting %s\n", r.URL.String())
-	})
-
-	// Callback for when a scraped page contains a form element
-	c.OnHTML(`table.productListing`, func(e *colly.HTMLElement) {
-		e.ForEach(`tr`, func(_ int, elem *colly.HTMLElement) {
-			if elem.ChildAttr("td", "class") != "productListing-data" {
-				return
-			}
+func (wc *Wizardscupboard) processRow(channel chan<- respChan, s *goquery.Selection) {
+	if crawl.Attr(s, "td", "class") != "productListing-data" {
+		return
+	}
 
-			link := strings.TrimSpace(elem.ChildAttr("td:nth-child(1) a", "href"))
-			cardName := strings.TrimSpace(elem.ChildText("td:nth-child(1)"))
-			edition := strings.TrimSpace(elem.ChildText("td:nth-child(2)"))
-			notes := strings.TrimSpace(elem.ChildText("td:nth-child(5)"))
-			qtyStr := elem.ChildText("td:nth-child(6)")
-			priceStr := strings.TrimSpace(elem.ChildText("td:nth-child(7)"))
+	link := crawl.Attr(s, "td:nth-child(1) a", "href")
+	cardName := crawl.Text(s, "td:nth-child(1)")
+	edition := crawl.Text(s, "td:nth-child(2)")
+	notes := crawl.Text(s, "td:nth-child(5)")
+	qtyStr := crawl.Text(s, "td:nth-child(6)")
+	priceStr := crawl.Text(s, "td:nth-child(7)")
 
-			if priceStr == "" || qtyStr == "" {
-				return
-			}
-			price, err := mtgmatcher.ParsePrice(priceStr)
-			if err != nil {
-				wc.printf("%s %s", cardName, err.Error())
-				return
-			}
+	if priceStr == "" || qtyStr == "" {
+		return
+	}
+	price, err := mtgmatcher.ParsePrice(priceStr)
+	if err != nil {
+		wc.printf("%s %s", cardName, err.Error())
+		return
+	}
 
-			if price <= 0 {
-				return
-			}
+	if price <= 0 {
+		return
+	}
 
-			qty, err := strconv.Atoi(qtyStr)
-			if err != nil {
-				wc.printf("%s %s", cardName, err.Error())
-				return
-			}
+	qty, err := strconv.Atoi(qtyStr)
+	if err != nil {
+		wc.printf("%s %s", cardName, err.Error())
+		return
+	}
 
-			if qty < 1 {
-				return
-			}
+	if qty < 1 {
+		return
+	}
 
-			conditions, err := parseConditions(notes)
-			if err != nil {
-				return
-			}
+	conditions, err := parseConditions(notes)
+	if err != nil {
+		return
+	}
 
-			theCard, err := preprocess(cardName, edition, notes)
-			if err != nil {
-				return
-			}
+	theCard, err := preprocess(cardName, edition, notes)
+	if err != nil {
+		return
+	}
 
-			cardID, err := mtgmatcher.Match(theCard)
-			if errors.Is(err, mtgmatcher.ErrUnsupported) {
-				return
-			} else if err != nil {
-				// Skip logging errors for basic lands
-				if !mtgmatcher.IsBasicLand(cardName) {
-					wc.printf("%v", err)
-					wc.printf("%s", theCard)
-					wc.printf("'%s' '%s' '%s'", cardName, edition, notes)
-
-					var alias *mtgmatcher.AliasingError
-					if errors.As(err, &alias) {
-						probes := alias.Probe()
-						for _, probe := range probes {
-							card, _ := mtgmatcher.GetUUID(probe)
-							wc.printf("- %s", card)
-						}
-					}
+	cardID, err := wc.matchStats.Match(theCard)
+	if errors.Is(err, mtgmatcher.ErrUnsupported) {
+		return
+	} else if err != nil {
+		// Skip logging errors for basic lands
+		if !mtgmatcher.IsBasicLand(cardName) {
+			wc.printf("%v", err)
+			wc.printf("%s", theCard)
+			wc.printf("'%s' '%s' '%s'", cardName, edition, notes)
+
+			var alias *mtgmatcher.AliasingError
+			if errors.As(err, &alias) {
+				probes := alias.Probe()
+				for _, probe := range probes {
+					card, _ := mtgmatcher.GetUUID(probe)
+					wc.printf("- %s", card)
 				}
-				return
 			}
+		}
+		return
+	}
 
-			channel <- respChan{
-				cardID: cardID,
-				entry: &mtgban.InventoryEntry{
-					Price:      price,
-					Conditions: conditions,
-					Quantity:   qty,
-					URL:        link,
-				},
-			}
-		})
-	})
-
-	c.Visit(wcInventoryURL)
-
-	go func() {
-		c.Wait()
-		close(channel)
-	}()
+	channel <- respChan{
+		cardID: cardID,
+		entry: &mtgban.InventoryEntry{
+			Price:      price,
+			Conditions: conditions,
+			Quantity:   qty,
+			URL:        link,
+		},
+	}
+}
 
+// Load fetches everything this scraper offers. See mtgban.Scraper.
+func (wc *Wizardscupboard) Load(ctx context.Context) error {
 	dupes := map[string]bool{}
 
-	for resp := range channel {
-		key := resp.cardID + resp.entry.Conditions
-		if dupes[key] {
-			continue
-		}
-		dupes[key] = true
+	crawl.Run(ctx, wc.crawler, wc.MaxConcurrency, []string{wcInventoryURL}, wc.visit,
+		func(resp respChan) {
+			key := resp.cardID + resp.entry.Conditions
+			if dupes[key] {
+				return
+			}
+			dupes[key] = true
 
-		err := wc.inventory.Add(resp.cardID, resp.entry)
-		if err != nil {
-			wc.printf("%v", err)
-			continue
-		}
-	}
+			err := wc.inventory.Add(resp.cardID, resp.entry)
+			if err != nil {
+				wc.printf("%v", err)
+			}
+		},
+		wc.printf,
+	)
 
 	wc.inventoryDate = time.Now()
 
@@ -232,6 +207,11 @@ func (wc *Wizardscupboard) Inventory() mtgban.InventoryRecord {
 	return wc.inventory
 }
 
+// MatchStats reports how the listings were matched. See mtgban.MatchReporter.
+func (wc *Wizardscupboard) MatchStats() *mtgban.MatchStats {
+	return &wc.matchStats
+}
+
 // Info describes this scraper. See mtgban.Scraper.
 func (wc *Wizardscupboard) Info() (info mtgban.ScraperInfo) {
 	info.Name = "Wizard's Cupboard"
@@ -0,0 +1,42 @@
+package wizardscupboard
+
+import (
+	"testing"
+
+	"github.com/mtgban/go-mtgban/mtgban"
+	"github.com/mtgban/go-mtgban/mtgban/scrapertest"
+)
+
+const (
+	elvesID        = "a0b0c0d0-0000-5000-8000-000000000227"
+	elvesFoilID    = "a0b0c0d0-0000-5000-8000-000000000227_f"
+	counterspellID = "a0b0c0d0-0000-5000-8000-000000000152"
+)
+
+func TestLoad(t *testing.T) {
+	wc := scrapertest.Load(t, "testdata/replay", "testdata/allprintings.json", func() (*Wizardscupboard, error) {
+		wc := NewScraper()
+		wc.crawler.RandomDelay = 0
+		return wc, nil
+	})
+
+	// The second page is reached through its sorted link, the second copy of
+	// a card in the same condition is dropped, and so are the listings out
+	// of stock or missing from the datastore
+	scrapertest.AssertInventory(t, wc.Inventory(), mtgban.InventoryRecord{
+		elvesID: {
+			{Conditions: "NM", Price: 0.25, Quantity: 3, URL: "https://www.wizardscupboard.com/llanowar-elves-p-1001.html"},
+		},
+		elvesFoilID: {
+			{Conditions: "SP", Price: 1.5, Quantity: 1, URL: "https://www.wizardscupboard.com/llanowar-elves-p-1003.html"},
+		},
+		counterspellID: {
+			{Conditions: "MP", Price: 1.1, Quantity: 2, URL: "https://www.wizardscupboard.com/counterspell-p-1005.html"},
+		},
+	})
+
+	report := wc.MatchStats().Report()
+	if report.Matched != 4 || report.Failed != 1 {
+		t.Errorf("unexpected match report %+v", report)
+	}
+}